@@ -13,6 +13,11 @@ import (
 	"github.com/gift-redemption/internal/config"
 	"github.com/gift-redemption/internal/database"
 	"github.com/gift-redemption/internal/handler"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/pkg/scheduler"
+	"github.com/gift-redemption/internal/pkg/storage"
 	"github.com/gift-redemption/internal/repository"
 	"github.com/gift-redemption/internal/service"
 	"github.com/gift-redemption/seeds"
@@ -32,6 +37,11 @@ import (
 // @in                          header
 // @name                        Authorization
 // @description                 Enter: Bearer {token}
+
+// @securityDefinitions.apikey  APIKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 Service integration key issued by an admin
 func main() {
 	cfg := config.Load()
 
@@ -52,28 +62,121 @@ func main() {
 	giftRepo := repository.NewGiftRepository(db)
 	redemptionRepo := repository.NewRedemptionRepository(db)
 	ratingRepo := repository.NewRatingRepository(db)
+	reviewVoteRepo := repository.NewReviewVoteRepository(db)
+	reviewReplyRepo := repository.NewReviewReplyRepository(db)
+	imageRepo := repository.NewImageRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	orgRepo := repository.NewOrganisationRepository(db)
+	userImportRepo := repository.NewUserImportRepository(db)
+	tierRepo := repository.NewTierRepository(db)
+	pointRepo := repository.NewPointRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	earningRepo := repository.NewEarningRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	flashSaleRepo := repository.NewFlashSaleRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+
+	// imports run in memory, so any left unfinished by the last shutdown never will be
+	if n, err := userImportRepo.FailUnfinished(); err != nil {
+		log.Printf("failed to close interrupted user imports: %v", err)
+	} else if n > 0 {
+		log.Printf("marked %d interrupted user imports as failed", n)
+	}
+
+	// infrastructure
+	notify := service.NewPreferenceNotifier(userRepo, notifier.NewLogNotifier())
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %v", err)
+	}
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("failed to initialize mailer: %v", err)
+	}
+	keys, err := jwtkeys.Load(cfg.JWT)
+	if err != nil {
+		log.Fatalf("failed to load JWT keys: %v", err)
+	}
 
 	// services
-	authService := service.NewAuthService(userRepo, cfg)
-	userService := service.NewUserService(userRepo)
-	giftService := service.NewGiftService(giftRepo)
-	redemptionService := service.NewRedemptionService(db, giftRepo, redemptionRepo, ratingRepo)
+	lockoutService := service.NewLockoutService(loginThrottleRepo, auditRepo, cfg.Lockout)
+	twoFactorService := service.NewTwoFactorService(db, userRepo, recoveryCodeRepo, roleRepo, cfg.TwoFactor)
+	authService := service.NewAuthService(db, userRepo, refreshTokenRepo, lockoutService, twoFactorService, mail, keys, cfg)
+	passwordService := service.NewPasswordService(db, userRepo, passwordResetRepo, refreshTokenRepo, mail, cfg)
+	userService := service.NewUserService(userRepo, roleRepo, redemptionRepo, auditRepo)
+	userImportService := service.NewUserImportService(userRepo, roleRepo, passwordResetRepo, userImportRepo, pointRepo, auditRepo, mail, cfg)
+	giftService := service.NewGiftService(giftRepo, userRepo, tierRepo, campaignRepo)
+	redemptionService := service.NewRedemptionService(db, userRepo, giftRepo, redemptionRepo, ratingRepo, tierRepo, pointRepo, campaignRepo, flashSaleRepo, couponRepo)
+	reviewService := service.NewReviewService(db, giftRepo, ratingRepo, reviewVoteRepo, reviewReplyRepo, notify)
+	imageService := service.NewImageService(giftRepo, ratingRepo, imageRepo, store, cfg.Storage.MaxUploadBytes)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, orgRepo, auditRepo)
+	roleService := service.NewRoleService(db, roleRepo, auditRepo)
+	orgService := service.NewOrganisationService(orgRepo, auditRepo)
+	profileService := service.NewProfileService(userRepo, redemptionRepo, tierRepo, refreshTokenRepo, auditRepo)
+	privacyService := service.NewPrivacyService(db, userRepo, roleRepo, redemptionRepo, pointRepo, ratingRepo, reviewVoteRepo, refreshTokenRepo, recoveryCodeRepo, passwordResetRepo, imageRepo, auditRepo, store)
+	impersonationService := service.NewImpersonationService(userRepo, roleService, auditRepo, keys, cfg.JWT)
+	tierService := service.NewTierService(tierRepo, userRepo, redemptionRepo, orgRepo, cfg.Tiers)
+	pointService := service.NewPointService(userRepo, pointRepo, cfg.Points)
+	transferService := service.NewTransferService(db, userRepo, tierRepo, pointRepo, transferRepo, auditRepo, notify)
+	earningService := service.NewEarningService(db, userRepo, pointRepo, earningRepo, cfg.Points)
+	campaignService := service.NewCampaignService(campaignRepo, giftRepo)
+	flashSaleService := service.NewFlashSaleService(db, flashSaleRepo, giftRepo)
+	couponService := service.NewCouponService(couponRepo, giftRepo)
 
 	// handlers
 	handlers := Handlers{
-		Auth:       handler.NewAuthHandler(authService),
-		User:       handler.NewUserHandler(userService),
-		Gift:       handler.NewGiftHandler(giftService),
-		Redemption: handler.NewRedemptionHandler(redemptionService),
+		Auth:          handler.NewAuthHandler(authService),
+		User:          handler.NewUserHandler(userService),
+		Gift:          handler.NewGiftHandler(giftService),
+		Redemption:    handler.NewRedemptionHandler(redemptionService),
+		Review:        handler.NewReviewHandler(reviewService),
+		Image:         handler.NewImageHandler(imageService, cfg.Storage.MaxUploadBytes),
+		Password:      handler.NewPasswordHandler(passwordService),
+		Lockout:       handler.NewLockoutHandler(lockoutService),
+		TwoFactor:     handler.NewTwoFactorHandler(twoFactorService),
+		JWKS:          handler.NewJWKSHandler(keys),
+		APIKey:        handler.NewAPIKeyHandler(apiKeyService),
+		Role:          handler.NewRoleHandler(roleService),
+		Organisation:  handler.NewOrganisationHandler(orgService),
+		Impersonation: handler.NewImpersonationHandler(impersonationService),
+		Profile:       handler.NewProfileHandler(profileService),
+		UserImport:    handler.NewUserImportHandler(userImportService),
+		Privacy:       handler.NewPrivacyHandler(privacyService),
+		Tier:          handler.NewTierHandler(tierService),
+		Point:         handler.NewPointHandler(pointService),
+		Transfer:      handler.NewTransferHandler(transferService),
+		Earning:       handler.NewEarningHandler(earningService),
+		Campaign:      handler.NewCampaignHandler(campaignService),
+		FlashSale:     handler.NewFlashSaleHandler(flashSaleService),
+		Coupon:        handler.NewCouponHandler(couponService),
 	}
 
-	r := NewRouter(cfg, handlers)
+	r := NewRouter(cfg, handlers, Security{
+		Keys:          keys,
+		Sessions:      authService,
+		APIKeys:       apiKeyService,
+		Permissions:   roleService,
+		Organisations: orgService,
+		Audit:         auditRepo,
+	})
 
 	server := &http.Server{
 		Addr:    ":" + cfg.AppPort,
 		Handler: r,
 	}
 
+	// background jobs stop with the server
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	go scheduler.Every(jobCtx, "tier evaluation", cfg.Tiers.EvaluationInterval, tierService.EvaluateAll)
+	go scheduler.Every(jobCtx, "point expiry", cfg.Points.ExpiryInterval, pointService.ExpireDue)
+	go scheduler.Every(jobCtx, "flash sale release", cfg.FlashSales.ReleaseInterval, flashSaleService.ReleaseEnded)
+
 	go func() {
 		log.Printf("server running on port %s", cfg.AppPort)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -90,6 +193,7 @@ func main() {
 	defer cancel()
 
 	log.Println("shutting down server...")
+	stopJobs()
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("server shutdown error: %v", err)
 	}
@@ -1,52 +1,215 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/gift-redemption/internal/config"
 	"github.com/gift-redemption/internal/handler"
 	"github.com/gift-redemption/internal/middleware"
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 type Handlers struct {
-	Auth       *handler.AuthHandler
-	User       *handler.UserHandler
-	Gift       *handler.GiftHandler
-	Redemption *handler.RedemptionHandler
+	Auth          *handler.AuthHandler
+	User          *handler.UserHandler
+	Gift          *handler.GiftHandler
+	Redemption    *handler.RedemptionHandler
+	Review        *handler.ReviewHandler
+	Image         *handler.ImageHandler
+	Password      *handler.PasswordHandler
+	Lockout       *handler.LockoutHandler
+	TwoFactor     *handler.TwoFactorHandler
+	JWKS          *handler.JWKSHandler
+	APIKey        *handler.APIKeyHandler
+	Role          *handler.RoleHandler
+	Organisation  *handler.OrganisationHandler
+	Impersonation *handler.ImpersonationHandler
+	Profile       *handler.ProfileHandler
+	UserImport    *handler.UserImportHandler
+	Privacy       *handler.PrivacyHandler
+	Tier          *handler.TierHandler
+	Point         *handler.PointHandler
+	Transfer      *handler.TransferHandler
+	Earning       *handler.EarningHandler
+	Campaign      *handler.CampaignHandler
+	FlashSale     *handler.FlashSaleHandler
+	Coupon        *handler.CouponHandler
+}
+
+// Security holds the dependencies of the authentication middlewares
+type Security struct {
+	Keys          *jwtkeys.KeySet
+	Sessions      middleware.SessionValidator
+	APIKeys       middleware.APIKeyAuthenticator
+	Permissions   middleware.PermissionResolver
+	Organisations middleware.OrganisationResolver
+	Audit         middleware.AuditWriter
 }
 
-func NewRouter(cfg *config.Config, h Handlers) *gin.Engine {
+func NewRouter(cfg *config.Config, h Handlers, sec Security) *gin.Engine {
 	r := gin.Default()
+	r.Use(middleware.AuditAPIKeyRequests(sec.Audit))
+	r.Use(middleware.AuditImpersonatedRequests(sec.Audit))
 
 	// swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	auth := middleware.Authenticate(cfg)
-	adminOnly := middleware.RequireRole(model.RoleAdmin)
+	// uploaded files are served by the app only when stored on local disk
+	if cfg.Storage.Driver == "local" && strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+		r.Static(cfg.Storage.PublicURL, cfg.Storage.LocalDir)
+	}
+
+	auth := middleware.Authenticate(sec.Keys, cfg.JWT, sec.Sessions, sec.APIKeys, sec.Permissions)
+	tenant := middleware.RequireOrganisation(sec.Organisations)
+	can := middleware.RequirePermission
+	// actions an admin must not take on a user's behalf
+	notImpersonated := middleware.ForbidImpersonation()
+
+	r.GET("/.well-known/jwks.json", h.JWKS.GetKeys)
 
 	r.POST("/login", h.Auth.Login)
+	r.POST("/login/2fa", h.Auth.LoginTwoFactor)
+	r.POST("/login/2fa/setup", h.Auth.SetupTwoFactor)
+	r.POST("/register", middleware.PublicOrganisation(sec.Organisations), h.Auth.Register)
+	r.GET("/verify-email", h.Auth.VerifyEmail)
+	r.POST("/verify-email/resend", h.Auth.ResendVerification)
+
+	r.POST("/password/forgot", h.Password.ForgotPassword)
+	r.POST("/password/reset", h.Password.ResetPassword)
+
+	authGroup := r.Group("/auth")
+	{
+		authGroup.POST("/refresh", h.Auth.Refresh)
+		authGroup.POST("/logout", h.Auth.Logout)
+	}
+
+	me := r.Group("/me", auth, middleware.RequireUser())
+	{
+		me.GET("", h.Profile.Get)
+		me.PATCH("", h.Profile.Update)
+		me.DELETE("", notImpersonated, h.Profile.Deactivate)
+		me.GET("/points", h.Point.GetBalance)
+		me.GET("/transfers", h.Transfer.GetHistory)
+		me.POST("/transfers", notImpersonated, h.Transfer.Send)
+		me.GET("/data-export", notImpersonated, h.Privacy.ExportOwn)
+		me.POST("/password", notImpersonated, h.Password.ChangePassword)
+		me.GET("/2fa", h.TwoFactor.GetStatus)
+		me.POST("/2fa/setup", notImpersonated, h.TwoFactor.Setup)
+		me.POST("/2fa/confirm", notImpersonated, h.TwoFactor.Confirm)
+		me.POST("/2fa/recovery-codes", notImpersonated, h.TwoFactor.RegenerateRecoveryCodes)
+		me.POST("/2fa/disable", notImpersonated, h.TwoFactor.Disable)
+	}
+
+	gifts := r.Group("/gifts", auth, tenant)
+	{
+		gifts.GET("", can(model.PermGiftsRead), h.Gift.GetAll)
+		gifts.GET("/:id", can(model.PermGiftsRead), h.Gift.GetByID)
+		gifts.POST("", can(model.PermGiftsWrite), h.Gift.Create)
+		gifts.PUT("/:id", can(model.PermGiftsWrite), h.Gift.Update)
+		gifts.PATCH("/:id", can(model.PermGiftsWrite), h.Gift.Patch)
+		gifts.DELETE("/:id", can(model.PermGiftsWrite), h.Gift.Delete)
+		gifts.POST("/:id/redeem", notImpersonated, can(model.PermGiftsRedeem), h.Redemption.Redeem)
+		gifts.POST("/:id/rating", can(model.PermReviewsWrite), h.Redemption.Rate)
+		gifts.GET("/:id/reviews", can(model.PermReviewsRead), h.Review.GetByGift)
+		gifts.POST("/:id/images", can(model.PermGiftsWrite), h.Image.UploadGiftImages)
+		gifts.DELETE("/:id/images/:imageId", can(model.PermGiftsWrite), h.Image.DeleteGiftImage)
+	}
+
+	tiers := r.Group("/tiers", auth, tenant)
+	{
+		tiers.GET("", can(model.PermGiftsRead), h.Tier.GetAll)
+		tiers.POST("", can(model.PermTiersManage), h.Tier.Create)
+		tiers.PUT("/:id", can(model.PermTiersManage), h.Tier.Update)
+		tiers.DELETE("/:id", can(model.PermTiersManage), h.Tier.Delete)
+		tiers.POST("/evaluate", can(model.PermTiersManage), h.Tier.Evaluate)
+	}
+
+	campaigns := r.Group("/campaigns", auth, tenant)
+	{
+		campaigns.GET("", can(model.PermCampaignsManage), h.Campaign.GetAll)
+		campaigns.POST("", can(model.PermCampaignsManage), h.Campaign.Create)
+		campaigns.PUT("/:id", can(model.PermCampaignsManage), h.Campaign.Update)
+		campaigns.DELETE("/:id", can(model.PermCampaignsManage), h.Campaign.Delete)
+		campaigns.GET("/:id/report", can(model.PermCampaignsManage), h.Campaign.Report)
+	}
+
+	flashSales := r.Group("/flash-sales", auth, tenant)
+	{
+		flashSales.GET("", can(model.PermCampaignsManage), h.FlashSale.GetAll)
+		flashSales.POST("", can(model.PermCampaignsManage), h.FlashSale.Create)
+		flashSales.DELETE("/:id", can(model.PermCampaignsManage), h.FlashSale.Delete)
+	}
+
+	coupons := r.Group("/coupons", auth, tenant)
+	{
+		coupons.GET("", can(model.PermCouponsManage), h.Coupon.GetAll)
+		coupons.POST("", can(model.PermCouponsManage), h.Coupon.Create)
+		coupons.PUT("/:id", can(model.PermCouponsManage), h.Coupon.Update)
+		coupons.DELETE("/:id", can(model.PermCouponsManage), h.Coupon.Delete)
+	}
+
+	transferPolicy := r.Group("/transfer-policy", auth, tenant)
+	{
+		transferPolicy.GET("", can(model.PermPointsManage), h.Transfer.GetPolicy)
+		transferPolicy.PUT("", middleware.RequireUser(), notImpersonated, can(model.PermPointsManage), h.Transfer.UpdatePolicy)
+	}
+
+	earningRules := r.Group("/earning-rules", auth, tenant)
+	{
+		earningRules.GET("", can(model.PermPointsManage), h.Earning.GetRules)
+		earningRules.POST("", can(model.PermPointsManage), h.Earning.CreateRule)
+		earningRules.PUT("/:id", can(model.PermPointsManage), h.Earning.UpdateRule)
+		earningRules.DELETE("/:id", can(model.PermPointsManage), h.Earning.DeleteRule)
+	}
+
+	r.POST("/events", auth, middleware.RequireAPIKey(), tenant, can(model.PermEventsIngest), h.Earning.Ingest)
+
+	reviews := r.Group("/reviews", auth, tenant)
+	{
+		reviews.PUT("/:id/vote", can(model.PermReviewsWrite), h.Review.Vote)
+		reviews.DELETE("/:id/vote", can(model.PermReviewsWrite), h.Review.Unvote)
+		reviews.POST("/:id/hide", can(model.PermReviewsModerate), h.Review.Hide)
+		reviews.PUT("/:id/reply", can(model.PermReviewsModerate), h.Review.Reply)
+		reviews.POST("/:id/photos", can(model.PermReviewsWrite), h.Image.UploadReviewPhotos)
+	}
 
-	gifts := r.Group("/gifts", auth)
+	users := r.Group("/users", auth, tenant)
 	{
-		gifts.GET("", h.Gift.GetAll)
-		gifts.GET("/:id", h.Gift.GetByID)
-		gifts.POST("", adminOnly, h.Gift.Create)
-		gifts.PUT("/:id", adminOnly, h.Gift.Update)
-		gifts.PATCH("/:id", adminOnly, h.Gift.Patch)
-		gifts.DELETE("/:id", adminOnly, h.Gift.Delete)
-		gifts.POST("/:id/redeem", h.Redemption.Redeem)
-		gifts.POST("/:id/rating", h.Redemption.Rate)
+		users.GET("", can(model.PermUsersRead), h.User.GetAll)
+		users.GET("/export", can(model.PermUsersRead), h.User.Export)
+		users.POST("/imports", middleware.RequireUser(), notImpersonated, can(model.PermUsersWrite), h.UserImport.Start)
+		users.GET("/imports/:id", can(model.PermUsersWrite), h.UserImport.Get)
+		users.GET("/:id", can(model.PermUsersRead), h.User.GetByID)
+		users.POST("", notImpersonated, can(model.PermUsersWrite), h.User.Create)
+		users.PUT("/:id", notImpersonated, can(model.PermUsersWrite), h.User.Update)
+		users.DELETE("/:id", notImpersonated, can(model.PermUsersWrite), h.User.Delete)
+		users.GET("/:id/data-export", can(model.PermUsersRead), h.Privacy.Export)
+		users.POST("/:id/erase", notImpersonated, can(model.PermUsersWrite), h.Privacy.Erase)
+		users.POST("/:id/impersonate", middleware.RequireUser(), notImpersonated, can(model.PermUsersImpersonate), h.Impersonation.Impersonate)
 	}
 
-	users := r.Group("/users", auth, adminOnly)
+	// API keys never administer the system, even if a future scope would match
+	admin := r.Group("/admin", auth, middleware.RequireUser(), notImpersonated)
 	{
-		users.GET("", h.User.GetAll)
-		users.GET("/:id", h.User.GetByID)
-		users.POST("", h.User.Create)
-		users.PUT("/:id", h.User.Update)
-		users.DELETE("/:id", h.User.Delete)
+		admin.GET("/lockouts", can(model.PermSecurityManage), h.Lockout.GetAll)
+		admin.DELETE("/lockouts/:id", can(model.PermSecurityManage), h.Lockout.Clear)
+		admin.GET("/api-keys", can(model.PermSecurityManage), h.APIKey.GetAll)
+		admin.POST("/api-keys", can(model.PermSecurityManage), h.APIKey.Create)
+		admin.DELETE("/api-keys/:id", can(model.PermSecurityManage), h.APIKey.Revoke)
+		admin.GET("/permissions", can(model.PermRolesManage), h.Role.GetPermissions)
+		admin.GET("/roles", can(model.PermRolesManage), h.Role.GetAll)
+		admin.GET("/roles/:id", can(model.PermRolesManage), h.Role.GetByID)
+		admin.POST("/roles", can(model.PermRolesManage), h.Role.Create)
+		admin.PUT("/roles/:id", can(model.PermRolesManage), h.Role.Update)
+		admin.DELETE("/roles/:id", can(model.PermRolesManage), h.Role.Delete)
+		admin.GET("/organisations", can(model.PermOrgsManage), h.Organisation.GetAll)
+		admin.GET("/organisations/:id", can(model.PermOrgsManage), h.Organisation.GetByID)
+		admin.POST("/organisations", can(model.PermOrgsManage), h.Organisation.Create)
+		admin.PUT("/organisations/:id", can(model.PermOrgsManage), h.Organisation.Update)
 	}
 
 	return r
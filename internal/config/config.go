@@ -5,17 +5,26 @@ import (
     "os"
     "strconv"
     "strings"
+    "time"
     "net/url"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	AppPort  string
-	AppEnv   string
-	AppHost  string
-	Database DatabaseConfig
-	JWT      JWTConfig
+	AppPort    string
+	AppEnv     string
+	AppHost    string
+	AppBaseURL string // public URL used in links sent to users
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Storage    StorageConfig
+	Mail       MailConfig
+	Lockout    LockoutConfig
+	TwoFactor  TwoFactorConfig
+	Tiers      TierConfig
+	Points     PointConfig
+	FlashSales FlashSaleConfig
 }
 
 type DatabaseConfig struct {
@@ -28,8 +37,78 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Algorithm  string // "HS256" | "RS256" | "EdDSA"
+	Secret     string // HS256 only
+	Issuer     string // iss of issued tokens, required on incoming ones
+	Audience   string // aud of issued tokens, required on incoming ones
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// ImpersonationTTL is the lifetime of tokens issued to admins acting as a user
+	ImpersonationTTL time.Duration
+	// PEM private key used to sign new tokens (RS256/EdDSA)
+	SigningKeyFile string
+	// PEM public keys of previous signing keys, still accepted during rotation
+	VerificationKeyFiles []string
+}
+
+// LockoutConfig controls failed-login backoff. Once MaxAttempts is reached the
+// lock lasts BaseDelay and doubles with every further failure up to MaxDelay.
+type LockoutConfig struct {
+	MaxAttempts   int
+	IPMaxAttempts int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	ResetAfter    time.Duration
+}
+
+type TwoFactorConfig struct {
+	Issuer           string // shown in authenticator apps
+	RequiredForAdmin bool   // admins, super admins and platform roles without 2FA must enrol before their first token is issued
+}
+
+// TierConfig controls loyalty tier evaluation. Users are placed by the points
+// they redeemed within QualifyingWindow, re-evaluated every EvaluationInterval.
+type TierConfig struct {
+	EvaluationInterval time.Duration
+	QualifyingWindow   time.Duration
+}
+
+// PointConfig controls point expiry. Earned points expire ValidMonths after
+// they are earned (0 keeps them forever); due lots are expired every
+// ExpiryInterval and balances warn of expirations within ExpiryWarning.
+type PointConfig struct {
+	ValidMonths    int
+	ExpiryInterval time.Duration
+	ExpiryWarning  time.Duration
+}
+
+// FlashSaleConfig controls how often the unclaimed units of ended flash
+// sales are returned to their gifts' stock.
+type FlashSaleConfig struct {
+	ReleaseInterval time.Duration
+}
+
+type MailConfig struct {
+	Driver string // "log" | "file"
+	From   string
+	Dir    string
+}
+
+type StorageConfig struct {
+	Driver         string // "local" | "s3"
+	LocalDir       string
+	PublicURL      string
+	MaxUploadBytes int64
+	S3             S3Config
+}
+
+// S3Config targets any S3-compatible endpoint (AWS, MinIO, R2, ...) using path-style URLs.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
 }
 
 func (d DatabaseConfig) DSN() string {
@@ -88,17 +167,33 @@ func Load() *Config {
 		_ = godotenv.Load()
 	}
 
-	jwtExpiry, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	accessTTL, _ := strconv.Atoi(getEnv("JWT_ACCESS_TTL_MINUTES", "15"))
+	refreshTTL, _ := strconv.Atoi(getEnv("JWT_REFRESH_TTL_HOURS", "720"))
+	impersonationTTL, _ := strconv.Atoi(getEnv("JWT_IMPERSONATION_TTL_MINUTES", "10"))
+	maxUpload, _ := strconv.ParseInt(getEnv("UPLOAD_MAX_BYTES", "5242880"), 10, 64)
+	lockoutAttempts, _ := strconv.Atoi(getEnv("LOGIN_MAX_ATTEMPTS", "5"))
+	lockoutIPAttempts, _ := strconv.Atoi(getEnv("LOGIN_IP_MAX_ATTEMPTS", "20"))
+	lockoutBase, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_BASE_SECONDS", "30"))
+	lockoutMax, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MAX_MINUTES", "60"))
+	require2FA, _ := strconv.ParseBool(getEnv("TWO_FACTOR_REQUIRED_FOR_ADMIN", "false"))
+	tierInterval, _ := strconv.Atoi(getEnv("TIER_EVALUATION_INTERVAL_HOURS", "24"))
+	tierWindow, _ := strconv.Atoi(getEnv("TIER_QUALIFYING_DAYS", "365"))
+	pointMonths, _ := strconv.Atoi(getEnv("POINT_EXPIRY_MONTHS", "12"))
+	pointInterval, _ := strconv.Atoi(getEnv("POINT_EXPIRY_INTERVAL_HOURS", "1"))
+	pointWarning, _ := strconv.Atoi(getEnv("POINT_EXPIRY_WARNING_DAYS", "90"))
+	flashSaleRelease, _ := strconv.Atoi(getEnv("FLASH_SALE_RELEASE_INTERVAL_MINUTES", "5"))
 
 	port := getEnv("PORT", "")
 	if port == "" {
 		port = getEnv("APP_PORT", "8080")
 	}
+	baseURL := getEnv("APP_BASE_URL", "http://localhost:"+port)
 
 	return &Config{
-		AppPort: port,
-		AppEnv:  appEnv,
-		AppHost: getEnv("APP_HOST", ""),
+		AppPort:    port,
+		AppEnv:     appEnv,
+		AppHost:    getEnv("APP_HOST", ""),
+		AppBaseURL: baseURL,
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
@@ -108,8 +203,56 @@ func Load() *Config {
 			URL:      getEnv("DATABASE_URL", ""),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", ""),
-			ExpiryHours: jwtExpiry,
+			Algorithm:            getEnv("JWT_ALGORITHM", "HS256"),
+			Secret:               getEnv("JWT_SECRET", ""),
+			Issuer:               getEnv("JWT_ISSUER", baseURL),
+			Audience:             getEnv("JWT_AUDIENCE", "gift-redemption-api"),
+			AccessTTL:            time.Duration(accessTTL) * time.Minute,
+			RefreshTTL:           time.Duration(refreshTTL) * time.Hour,
+			ImpersonationTTL:     time.Duration(impersonationTTL) * time.Minute,
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: splitList(getEnv("JWT_VERIFICATION_KEY_FILES", "")),
+		},
+		Lockout: LockoutConfig{
+			MaxAttempts:   lockoutAttempts,
+			IPMaxAttempts: lockoutIPAttempts,
+			BaseDelay:     time.Duration(lockoutBase) * time.Second,
+			MaxDelay:      time.Duration(lockoutMax) * time.Minute,
+			ResetAfter:    24 * time.Hour,
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:           getEnv("TOTP_ISSUER", "Gift Redemption"),
+			RequiredForAdmin: require2FA,
+		},
+		Tiers: TierConfig{
+			EvaluationInterval: time.Duration(tierInterval) * time.Hour,
+			QualifyingWindow:   time.Duration(tierWindow) * 24 * time.Hour,
+		},
+		Points: PointConfig{
+			ValidMonths:    pointMonths,
+			ExpiryInterval: time.Duration(pointInterval) * time.Hour,
+			ExpiryWarning:  time.Duration(pointWarning) * 24 * time.Hour,
+		},
+		FlashSales: FlashSaleConfig{
+			ReleaseInterval: time.Duration(flashSaleRelease) * time.Minute,
+		},
+		Mail: MailConfig{
+			Driver: getEnv("MAIL_DRIVER", "log"),
+			From:   getEnv("MAIL_FROM", "no-reply@gift-redemption.com"),
+			Dir:    getEnv("MAIL_DIR", "tmp/mail"),
+		},
+		Storage: StorageConfig{
+			Driver:         getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:       getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			PublicURL:      getEnv("STORAGE_PUBLIC_URL", "/uploads"),
+			MaxUploadBytes: maxUpload,
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", ""),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+			},
 		},
 	}
 }
@@ -120,3 +263,14 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// splitList parses a comma-separated env value, ignoring empty entries
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
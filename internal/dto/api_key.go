@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+	// ExpiresInDays of 0 creates a key that never expires
+	ExpiresInDays int `json:"expires_in_days" binding:"omitempty,min=1,max=730"`
+	// OrganisationID binds the key to one organisation; unbound keys must send X-Organisation
+	OrganisationID *uint `json:"organisation_id" binding:"omitempty,min=1"`
+}
+
+type APIKeyResponse struct {
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	Prefix         string   `json:"prefix"`
+	Scopes         []string `json:"scopes"`
+	OrganisationID *uint    `json:"organisation_id"`
+	Status         string   `json:"status"` // active | expired | revoked
+	CreatedByID    uint     `json:"created_by_id"`
+	ExpiresAt      string   `json:"expires_at,omitempty"`
+	LastUsedAt     string   `json:"last_used_at,omitempty"`
+	LastUsedIP     string   `json:"last_used_ip,omitempty"`
+	RevokedAt      string   `json:"revoked_at,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+// APIKeyCreatedResponse is the only response that contains the full key.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(k model.APIKey, now time.Time) APIKeyResponse {
+	res := APIKeyResponse{
+		ID:             k.ID,
+		Name:           k.Name,
+		Prefix:         k.Prefix,
+		Scopes:         []string(k.Scopes),
+		OrganisationID: k.OrganisationID,
+		Status:         "active",
+		CreatedByID:    k.CreatedByID,
+		LastUsedIP:     k.LastUsedIP,
+		CreatedAt:      k.CreatedAt.Format(time.RFC3339),
+	}
+	if res.Scopes == nil {
+		res.Scopes = []string{}
+	}
+	if k.ExpiresAt != nil {
+		res.ExpiresAt = k.ExpiresAt.Format(time.RFC3339)
+		if !k.ExpiresAt.After(now) {
+			res.Status = "expired"
+		}
+	}
+	if k.LastUsedAt != nil {
+		res.LastUsedAt = k.LastUsedAt.Format(time.RFC3339)
+	}
+	if k.RevokedAt != nil {
+		res.RevokedAt = k.RevokedAt.Format(time.RFC3339)
+		res.Status = "revoked"
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type CampaignRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// DiscountType is "percent" or "fixed" (points off)
+	DiscountType  string `json:"discount_type" binding:"required,oneof=percent fixed"`
+	DiscountValue int    `json:"discount_value" binding:"required,min=1"`
+	// GiftIDs and Categories select the discounted gifts; at least one is needed
+	GiftIDs    []uint    `json:"gift_ids" binding:"max=500,dive,min=1"`
+	Categories []string  `json:"categories" binding:"max=50,dive,required,max=50"`
+	StartsAt   time.Time `json:"starts_at" binding:"required"`
+	EndsAt     time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+}
+
+type CampaignResponse struct {
+	ID            uint     `json:"id"`
+	Name          string   `json:"name"`
+	DiscountType  string   `json:"discount_type"`
+	DiscountValue int      `json:"discount_value"`
+	GiftIDs       []uint   `json:"gift_ids"`
+	Categories    []string `json:"categories"`
+	StartsAt      string   `json:"starts_at"`
+	EndsAt        string   `json:"ends_at"`
+	Status        string   `json:"status"` // scheduled | running | ended
+	CreatedAt     string   `json:"created_at"`
+}
+
+func ToCampaignResponse(c model.Campaign, now time.Time) CampaignResponse {
+	res := CampaignResponse{
+		ID:            c.ID,
+		Name:          c.Name,
+		DiscountType:  c.DiscountType,
+		DiscountValue: c.DiscountValue,
+		GiftIDs:       []uint(c.GiftIDs),
+		Categories:    []string(c.Categories),
+		StartsAt:      c.StartsAt.Format(time.RFC3339),
+		EndsAt:        c.EndsAt.Format(time.RFC3339),
+		Status:        "running",
+		CreatedAt:     c.CreatedAt.Format(time.RFC3339),
+	}
+	if res.GiftIDs == nil {
+		res.GiftIDs = []uint{}
+	}
+	if res.Categories == nil {
+		res.Categories = []string{}
+	}
+	switch {
+	case now.Before(c.StartsAt):
+		res.Status = "scheduled"
+	case !now.Before(c.EndsAt):
+		res.Status = "ended"
+	}
+	return res
+}
+
+// CampaignReportResponse sums up the redemptions a campaign priced
+type CampaignReportResponse struct {
+	CampaignID  uint  `json:"campaign_id"`
+	Redemptions int64 `json:"redemptions"`
+	Quantity    int64 `json:"quantity"`
+	Users       int64 `json:"users"`
+	// PointsSpent is what users paid; PointsDiscounted what the campaign saved them
+	PointsSpent      int64 `json:"points_spent"`
+	PointsDiscounted int64 `json:"points_discounted"`
+}
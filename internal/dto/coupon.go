@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type CouponRequest struct {
+	// Code is stored upper case; letters, digits, "-" and "_"
+	Code string `json:"code" binding:"required,min=3,max=50"`
+	// DiscountType is "percent" or "fixed" (points off the total), or "free"
+	// for one unit free
+	DiscountType  string `json:"discount_type" binding:"required,oneof=percent fixed free"`
+	DiscountValue int    `json:"discount_value" binding:"min=0"`
+	// MaxUses and MaxUsesPerUser limit the uses; 0 is no limit
+	MaxUses        int `json:"max_uses" binding:"min=0"`
+	MaxUsesPerUser int `json:"max_uses_per_user" binding:"min=0"`
+	// MinPoints is the least the redemption must cost before the coupon
+	MinPoints int `json:"min_points" binding:"min=0"`
+	// GiftIDs and Categories restrict the coupon; with neither it fits every gift
+	GiftIDs    []uint     `json:"gift_ids" binding:"max=500,dive,min=1"`
+	Categories []string   `json:"categories" binding:"max=50,dive,required,max=50"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+type CouponResponse struct {
+	ID             uint     `json:"id"`
+	Code           string   `json:"code"`
+	DiscountType   string   `json:"discount_type"`
+	DiscountValue  int      `json:"discount_value"`
+	MaxUses        int      `json:"max_uses"`
+	MaxUsesPerUser int      `json:"max_uses_per_user"`
+	Uses           int      `json:"uses"`
+	MinPoints      int      `json:"min_points"`
+	GiftIDs        []uint   `json:"gift_ids"`
+	Categories     []string `json:"categories"`
+	StartsAt       string   `json:"starts_at,omitempty"`
+	EndsAt         string   `json:"ends_at,omitempty"`
+	Status         string   `json:"status"` // scheduled | active | expired | used_up
+	CreatedAt      string   `json:"created_at"`
+}
+
+func ToCouponResponse(c model.Coupon, now time.Time) CouponResponse {
+	res := CouponResponse{
+		ID:             c.ID,
+		Code:           c.Code,
+		DiscountType:   c.DiscountType,
+		DiscountValue:  c.DiscountValue,
+		MaxUses:        c.MaxUses,
+		MaxUsesPerUser: c.MaxUsesPerUser,
+		Uses:           c.Uses,
+		MinPoints:      c.MinPoints,
+		GiftIDs:        []uint(c.GiftIDs),
+		Categories:     []string(c.Categories),
+		Status:         "active",
+		CreatedAt:      c.CreatedAt.Format(time.RFC3339),
+	}
+	if res.GiftIDs == nil {
+		res.GiftIDs = []uint{}
+	}
+	if res.Categories == nil {
+		res.Categories = []string{}
+	}
+	if c.StartsAt != nil {
+		res.StartsAt = c.StartsAt.Format(time.RFC3339)
+	}
+	if c.EndsAt != nil {
+		res.EndsAt = c.EndsAt.Format(time.RFC3339)
+	}
+	switch {
+	case c.MaxUses > 0 && c.Uses >= c.MaxUses:
+		res.Status = "used_up"
+	case c.EndsAt != nil && !now.Before(*c.EndsAt):
+		res.Status = "expired"
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		res.Status = "scheduled"
+	}
+	return res
+}
@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type EarningRuleRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	EventType string `json:"event_type" binding:"required,max=50"`
+	// Conditions maps attribute names to the values an event must carry
+	Conditions map[string]interface{} `json:"conditions"`
+	// Points is awarded for every matching event
+	Points int `json:"points" binding:"min=0,max=1000000"`
+	// UnitAttribute names a numeric event attribute earning PointsPerUnit per unit
+	UnitAttribute string  `json:"unit_attribute" binding:"max=50"`
+	PointsPerUnit float64 `json:"points_per_unit" binding:"min=0,max=100000"`
+	// Multiplier scales the award; defaults to 1
+	Multiplier  *float64   `json:"multiplier" binding:"omitnil,gt=0,max=100"`
+	MaxPerEvent int        `json:"max_per_event" binding:"min=0"`
+	UserCap     int        `json:"user_cap" binding:"min=0"`
+	UserCapDays int        `json:"user_cap_days" binding:"min=0,max=3660"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+// RuleMultiplier reads Multiplier with its default
+func (r EarningRuleRequest) RuleMultiplier() float64 {
+	if r.Multiplier == nil {
+		return 1
+	}
+	return *r.Multiplier
+}
+
+// IsActive reads Active with its default
+func (r EarningRuleRequest) IsActive() bool {
+	return r.Active == nil || *r.Active
+}
+
+type EarningRuleResponse struct {
+	ID            uint                   `json:"id"`
+	Name          string                 `json:"name"`
+	EventType     string                 `json:"event_type"`
+	Conditions    map[string]interface{} `json:"conditions"`
+	Points        int                    `json:"points"`
+	UnitAttribute string                 `json:"unit_attribute"`
+	PointsPerUnit float64                `json:"points_per_unit"`
+	Multiplier    float64                `json:"multiplier"`
+	MaxPerEvent   int                    `json:"max_per_event"`
+	UserCap       int                    `json:"user_cap"`
+	UserCapDays   int                    `json:"user_cap_days"`
+	StartsAt      string                 `json:"starts_at,omitempty"`
+	EndsAt        string                 `json:"ends_at,omitempty"`
+	Active        bool                   `json:"active"`
+	CreatedAt     string                 `json:"created_at"`
+}
+
+func ToEarningRuleResponse(r model.EarningRule) EarningRuleResponse {
+	res := EarningRuleResponse{
+		ID:            r.ID,
+		Name:          r.Name,
+		EventType:     r.EventType,
+		Conditions:    r.Conditions,
+		Points:        r.Points,
+		UnitAttribute: r.UnitAttribute,
+		PointsPerUnit: r.PointsPerUnit,
+		Multiplier:    r.Multiplier,
+		MaxPerEvent:   r.MaxPerEvent,
+		UserCap:       r.UserCap,
+		UserCapDays:   r.UserCapDays,
+		Active:        r.Active,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+	}
+	if res.Conditions == nil {
+		res.Conditions = map[string]interface{}{}
+	}
+	if r.StartsAt != nil {
+		res.StartsAt = r.StartsAt.Format(time.RFC3339)
+	}
+	if r.EndsAt != nil {
+		res.EndsAt = r.EndsAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+// PointEventRequest is a business event sent by another system
+type PointEventRequest struct {
+	// EventID is the sender's ID for the event; resending it awards nothing new
+	EventID   string `json:"event_id" binding:"required,max=100"`
+	Type      string `json:"type" binding:"required,max=50"`
+	UserEmail string `json:"user_email" binding:"required,email"`
+	// OccurredAt defaults to the time the event is received
+	OccurredAt *time.Time             `json:"occurred_at"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type PointAwardResponse struct {
+	RuleID uint `json:"rule_id"`
+	Points int  `json:"points"`
+}
+
+type PointEventResponse struct {
+	ID            uint                 `json:"id"`
+	EventID       string               `json:"event_id"`
+	Type          string               `json:"type"`
+	UserID        uint                 `json:"user_id"`
+	OccurredAt    string               `json:"occurred_at"`
+	PointsAwarded int                  `json:"points_awarded"`
+	Awards        []PointAwardResponse `json:"awards"`
+	// Duplicate is set when the event had already been received
+	Duplicate bool   `json:"duplicate"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToPointEventResponse expects the event's Awards to be loaded
+func ToPointEventResponse(e model.PointEvent, duplicate bool) PointEventResponse {
+	res := PointEventResponse{
+		ID:            e.ID,
+		EventID:       e.ExternalID,
+		Type:          e.Type,
+		UserID:        e.UserID,
+		OccurredAt:    e.OccurredAt.Format(time.RFC3339),
+		PointsAwarded: e.PointsAwarded,
+		Awards:        make([]PointAwardResponse, len(e.Awards)),
+		Duplicate:     duplicate,
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+	}
+	for i, a := range e.Awards {
+		res.Awards[i] = PointAwardResponse{RuleID: a.RuleID, Points: a.Points}
+	}
+	return res
+}
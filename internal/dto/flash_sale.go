@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type FlashSaleRequest struct {
+	GiftID   uint      `json:"gift_id" binding:"required,min=1"`
+	Quantity int       `json:"quantity" binding:"required,min=1,max=100000"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	// EndsAt is optional; without it the sale runs until it sells out
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+type FlashSaleResponse struct {
+	ID        uint   `json:"id"`
+	GiftID    uint   `json:"gift_id"`
+	Quantity  int    `json:"quantity"`
+	Claimed   int    `json:"claimed"`
+	Remaining int    `json:"remaining"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at,omitempty"`
+	Status    string `json:"status"` // scheduled | running | ended
+	CreatedAt string `json:"created_at"`
+}
+
+func ToFlashSaleResponse(s model.FlashSale, now time.Time) FlashSaleResponse {
+	res := FlashSaleResponse{
+		ID:        s.ID,
+		GiftID:    s.GiftID,
+		Quantity:  s.Quantity,
+		Claimed:   s.Claimed,
+		Remaining: max(s.Quantity-s.Claimed, 0),
+		StartsAt:  s.StartsAt.Format(time.RFC3339),
+		Status:    "running",
+		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.EndsAt != nil {
+		res.EndsAt = s.EndsAt.Format(time.RFC3339)
+	}
+	switch {
+	case s.EndedAt(now):
+		res.Status = "ended"
+	case now.Before(s.StartsAt):
+		res.Status = "scheduled"
+	}
+	return res
+}
@@ -14,6 +14,10 @@ type CreateGiftRequest struct {
 	ImageURL     string `json:"image_url"`
 	IsNew        bool   `json:"is_new"`
 	IsBestSeller bool   `json:"is_best_seller"`
+	// MinTierID reserves the gift for that tier and higher ones
+	MinTierID *uint `json:"min_tier_id" binding:"omitempty,min=1"`
+	// Category groups gifts for campaigns, e.g. "electronics"
+	Category string `json:"category" binding:"max=50"`
 }
 
 type UpdateGiftRequest struct {
@@ -24,6 +28,10 @@ type UpdateGiftRequest struct {
 	ImageURL     string `json:"image_url"`
 	IsNew        bool   `json:"is_new"`
 	IsBestSeller bool   `json:"is_best_seller"`
+	// MinTierID reserves the gift for that tier and higher ones
+	MinTierID *uint `json:"min_tier_id" binding:"omitempty,min=1"`
+	// Category groups gifts for campaigns, e.g. "electronics"
+	Category string `json:"category" binding:"max=50"`
 }
 
 type PatchGiftRequest struct {
@@ -34,22 +42,40 @@ type PatchGiftRequest struct {
 	ImageURL     *string `json:"image_url"`
 	IsNew        *bool   `json:"is_new"`
 	IsBestSeller *bool   `json:"is_best_seller"`
+	// MinTierID 0 opens the gift to every tier again
+	MinTierID *uint   `json:"min_tier_id"`
+	Category  *string `json:"category" binding:"omitnil,max=50"`
 }
 
 type GiftResponse struct {
-	ID           uint    `json:"id"`
-	Name         string  `json:"name"`
-	Description  string  `json:"description"`
-	Point        int     `json:"point"`
-	Stock        int     `json:"stock"`
-	ImageURL     string  `json:"image_url"`
-	IsNew        bool    `json:"is_new"`
-	IsBestSeller bool    `json:"is_best_seller"`
-	AvgRating    float64 `json:"avg_rating"`
-	StarRating   float64 `json:"star_rating"`
-	TotalReviews int     `json:"total_reviews"`
-	InStock      bool    `json:"in_stock"`
-	CreatedAt    string  `json:"created_at"`
+	ID           uint            `json:"id"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Point        int             `json:"point"`
+	Stock        int             `json:"stock"`
+	ImageURL     string          `json:"image_url"`
+	IsNew        bool            `json:"is_new"`
+	IsBestSeller bool            `json:"is_best_seller"`
+	AvgRating    float64         `json:"avg_rating"`
+	StarRating   float64         `json:"star_rating"`
+	TotalReviews int             `json:"total_reviews"`
+	InStock      bool            `json:"in_stock"`
+	MinTierID    *uint           `json:"min_tier_id,omitempty"`
+	Category     string          `json:"category"`
+	Images       []ImageResponse `json:"images,omitempty"`
+	CreatedAt    string          `json:"created_at"`
+	// EffectivePoint is Point after the running campaign, before any tier discount
+	EffectivePoint int                   `json:"effective_point"`
+	Campaign       *GiftCampaignResponse `json:"campaign,omitempty"`
+}
+
+// GiftCampaignResponse is the campaign discounting a gift
+type GiftCampaignResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	DiscountType  string `json:"discount_type"`
+	DiscountValue int    `json:"discount_value"`
+	EndsAt        string `json:"ends_at"`
 }
 
 func ToGiftResponse(g model.Gift) GiftResponse {
@@ -66,6 +92,27 @@ func ToGiftResponse(g model.Gift) GiftResponse {
 		StarRating:   RoundToHalf(g.AvgRating),
 		TotalReviews: g.TotalReviews,
 		InStock:      g.InStock(),
+		MinTierID:    g.MinTierID,
+		Category:     g.Category,
+		Images:       ToImageResponses(g.Images),
 		CreatedAt:    g.CreatedAt.Format(time.RFC3339),
+		// no campaign until ApplyCampaign
+		EffectivePoint: g.Point,
+	}
+}
+
+// ApplyCampaign prices the gift with the campaign discounting it; nil is none
+func (r *GiftResponse) ApplyCampaign(c *model.Campaign) {
+	r.EffectivePoint = c.Price(r.Point)
+	if c == nil {
+		r.Campaign = nil
+		return
+	}
+	r.Campaign = &GiftCampaignResponse{
+		ID:            c.ID,
+		Name:          c.Name,
+		DiscountType:  c.DiscountType,
+		DiscountValue: c.DiscountValue,
+		EndsAt:        c.EndsAt.Format(time.RFC3339),
 	}
 }
@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/gift-redemption/internal/model"
+
+// UploadFile is a file read from a multipart request, decoupled from net/http
+type UploadFile struct {
+	Filename string
+	Data     []byte
+}
+
+type ImageResponse struct {
+	ID           uint   `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+}
+
+func ToImageResponse(img model.Image) ImageResponse {
+	return ImageResponse{
+		ID:           img.ID,
+		URL:          img.URL,
+		ThumbnailURL: img.ThumbnailURL,
+		ContentType:  img.ContentType,
+		Width:        img.Width,
+		Height:       img.Height,
+		Position:     img.Position,
+	}
+}
+
+func ToImageResponses(images []model.Image) []ImageResponse {
+	if len(images) == 0 {
+		return nil
+	}
+	result := make([]ImageResponse, len(images))
+	for i, img := range images {
+		result[i] = ToImageResponse(img)
+	}
+	return result
+}
@@ -0,0 +1,15 @@
+package dto
+
+type ImpersonateRequest struct {
+	// Reason is kept in the audit log, e.g. a support ticket reference
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ImpersonationResponse carries an access token acting as User. There is no
+// refresh token; the admin starts a new impersonation once it expires.
+type ImpersonationResponse struct {
+	Token          string       `json:"token"`
+	ExpiresIn      int          `json:"expires_in"` // seconds
+	ImpersonatorID uint         `json:"impersonator_id"`
+	User           UserResponse `json:"user"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type LockoutResponse struct {
+	ID           uint   `json:"id"`
+	Scope        string `json:"scope"`
+	Key          string `json:"key"`
+	FailedCount  int    `json:"failed_count"`
+	LastFailedAt string `json:"last_failed_at"`
+	LockedUntil  string `json:"locked_until"`
+}
+
+func ToLockoutResponse(t model.LoginThrottle) LockoutResponse {
+	res := LockoutResponse{
+		ID:           t.ID,
+		Scope:        t.Scope,
+		Key:          t.Key,
+		FailedCount:  t.FailedCount,
+		LastFailedAt: t.LastFailedAt.Format(time.RFC3339),
+	}
+	if t.LockedUntil != nil {
+		res.LockedUntil = t.LockedUntil.Format(time.RFC3339)
+	}
+	return res
+}
@@ -7,14 +7,46 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// LoginResponse either carries tokens or, when a second factor is needed, a
+// short-lived challenge token to exchange at /login/2fa.
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token                  string       `json:"token,omitempty"`
+	RefreshToken           string       `json:"refresh_token,omitempty"`
+	ExpiresIn              int          `json:"expires_in,omitempty"` // access token lifetime in seconds
+	TwoFactorRequired      bool         `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool         `json:"two_factor_setup_required,omitempty"`
+	ChallengeToken         string       `json:"challenge_token,omitempty"`
+	RecoveryCodes          []string     `json:"recovery_codes,omitempty"` // only after enrolling during login
+	User                   UserResponse `json:"user"`
 }
 
-func ToLoginResponse(token string, user model.User) LoginResponse {
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func ToLoginResponse(tokens TokenResponse, user model.User) LoginResponse {
 	return LoginResponse{
-		Token: token,
-		User:  ToUserResponse(user),
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         ToUserResponse(user),
 	}
 }
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
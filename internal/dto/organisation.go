@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type CreateOrganisationRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Slug identifies the organisation in the X-Organisation header and at sign-up
+	Slug string `json:"slug" binding:"required,min=2,max=50"`
+}
+
+type UpdateOrganisationRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type OrganisationResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	CreatedAt string `json:"created_at"`
+}
+
+func ToOrganisationResponse(o model.Organisation) OrganisationResponse {
+	return OrganisationResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Slug:      o.Slug,
+		CreatedAt: o.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+// PersonalDataExport is everything held about one user, for subject access
+// requests. No postal addresses are stored, so none are exported.
+type PersonalDataExport struct {
+	GeneratedAt    time.Time               `json:"generated_at"`
+	Profile        PersonalProfile         `json:"profile"`
+	Points         PersonalPoints          `json:"points"`
+	Redemptions    []PersonalRedemption    `json:"redemptions"`
+	Ratings        []PersonalRating        `json:"ratings"`
+	ReviewVotes    []PersonalReviewVote    `json:"review_votes"`
+	Sessions       []PersonalSession       `json:"sessions"`
+	SecurityEvents []PersonalSecurityEvent `json:"security_events"`
+}
+
+type PersonalProfile struct {
+	ID                      uint                          `json:"id"`
+	OrganisationID          uint                          `json:"organisation_id"`
+	Name                    string                        `json:"name"`
+	Email                   string                        `json:"email"`
+	Role                    string                        `json:"role"`
+	AvatarURL               string                        `json:"avatar_url"`
+	Locale                  string                        `json:"locale"`
+	NotificationPreferences model.NotificationPreferences `json:"notification_preferences"`
+	EmailVerifiedAt         *time.Time                    `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled        bool                          `json:"two_factor_enabled"`
+	CreatedAt               time.Time                     `json:"created_at"`
+	UpdatedAt               time.Time                     `json:"updated_at"`
+	DeactivatedAt           *time.Time                    `json:"deactivated_at,omitempty"`
+	ErasedAt                *time.Time                    `json:"erased_at,omitempty"`
+}
+
+// PersonalPoints is the current balance and every movement recorded against it
+type PersonalPoints struct {
+	Balance int                `json:"balance"`
+	Ledger  []PointLedgerEntry `json:"ledger"`
+}
+
+type PointLedgerEntry struct {
+	At     time.Time `json:"at"`
+	Type   string    `json:"type"`
+	Points int       `json:"points"`
+	// Reference identifies the record behind the movement: the redemption ID
+	// for redemptions, otherwise the ID of the point lot credited or expired
+	Reference uint `json:"reference"`
+}
+
+type PersonalRedemption struct {
+	ID         uint      `json:"id"`
+	GiftID     uint      `json:"gift_id"`
+	GiftName   string    `json:"gift_name"`
+	Quantity   int       `json:"quantity"`
+	TotalPoint int       `json:"total_point"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+type PersonalRating struct {
+	ID           uint       `json:"id"`
+	GiftID       uint       `json:"gift_id"`
+	GiftName     string     `json:"gift_name"`
+	RedemptionID uint       `json:"redemption_id"`
+	Score        float64    `json:"score"`
+	HiddenAt     *time.Time `json:"hidden_at,omitempty"`
+	PhotoURLs    []string   `json:"photo_urls"`
+	Reply        string     `json:"reply,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type PersonalReviewVote struct {
+	RatingID  uint      `json:"rating_id"`
+	IsHelpful bool      `json:"is_helpful"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PersonalSession is one refresh token; the token itself is never exported
+type PersonalSession struct {
+	SessionID string     `json:"session_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type PersonalSecurityEvent struct {
+	Action    string    `json:"action"`
+	IP        string    `json:"ip,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EraseUserResponse reports what an erasure removed
+type EraseUserResponse struct {
+	UserID        uint      `json:"user_id"`
+	ErasedAt      time.Time `json:"erased_at"`
+	PhotosDeleted int       `json:"photos_deleted"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+// PointBalanceResponse is the caller's spendable points and what is about to expire
+type PointBalanceResponse struct {
+	Balance int `json:"balance"`
+	// ExpiringSoon totals UpcomingExpirations
+	ExpiringSoon        int               `json:"expiring_soon"`
+	UpcomingExpirations []PointExpiration `json:"upcoming_expirations"`
+	RecentActivity      []PointActivity   `json:"recent_activity"`
+}
+
+// PointExpiration is how many points expire on a day (UTC)
+type PointExpiration struct {
+	Date   string `json:"date"`
+	Points int    `json:"points"`
+}
+
+type PointActivity struct {
+	Type         string `json:"type"`
+	Points       int    `json:"points"`
+	RedemptionID *uint  `json:"redemption_id,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// ToPointExpirations groups lots, ordered by expiry, by the day they expire
+func ToPointExpirations(lots []model.PointLot) []PointExpiration {
+	res := make([]PointExpiration, 0, len(lots))
+	for _, lot := range lots {
+		if lot.ExpiresAt == nil {
+			continue
+		}
+		date := lot.ExpiresAt.UTC().Format(time.DateOnly)
+		if n := len(res); n > 0 && res[n-1].Date == date {
+			res[n-1].Points += lot.Remaining
+			continue
+		}
+		res = append(res, PointExpiration{Date: date, Points: lot.Remaining})
+	}
+	return res
+}
+
+func ToPointActivities(entries []model.PointLedgerEntry) []PointActivity {
+	res := make([]PointActivity, len(entries))
+	for i, e := range entries {
+		res[i] = PointActivity{
+			Type:         e.Type,
+			Points:       e.Points,
+			RedemptionID: e.RedemptionID,
+			CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return res
+}
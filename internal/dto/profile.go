@@ -0,0 +1,16 @@
+package dto
+
+// UpdateProfileRequest changes only the fields that are present. An empty
+// avatar_url removes the avatar; notification preferences are merged.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" binding:"omitnil,min=1,max=100"`
+	AvatarURL *string `json:"avatar_url" binding:"omitnil,max=500,len=0|url"`
+	// Locale is a BCP 47 language tag such as "en" or "id-ID"
+	Locale                  *string         `json:"locale" binding:"omitnil,bcp47_language_tag,max=35"`
+	NotificationPreferences map[string]bool `json:"notification_preferences"`
+}
+
+// DeactivateAccountRequest confirms self-deactivation with the current password
+type DeactivateAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
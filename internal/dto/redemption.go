@@ -8,6 +8,8 @@ import (
 
 type RedemptionRequest struct {
 	Quantity int `json:"quantity" binding:"required,min=1"`
+	// CouponCode is optional and matched case-insensitively
+	CouponCode string `json:"coupon_code" binding:"max=50"`
 }
 
 type RedemptionResponse struct {
@@ -17,15 +19,25 @@ type RedemptionResponse struct {
 	Quantity     int    `json:"quantity"`
 	TotalPoint   int    `json:"total_point"`
 	RedeemedAt   string `json:"redeemed_at"`
+	// CampaignID is the campaign that discounted the redemption by CampaignDiscount points
+	CampaignID       *uint `json:"campaign_id,omitempty"`
+	CampaignDiscount int   `json:"campaign_discount"`
+	// CouponID is the coupon that took CouponDiscount points off
+	CouponID       *uint `json:"coupon_id,omitempty"`
+	CouponDiscount int   `json:"coupon_discount"`
 }
 
 func ToRedemptionResponse(r model.Redemption, giftName string) RedemptionResponse {
 	return RedemptionResponse{
-		RedemptionID: r.ID,
-		GiftID:       r.GiftID,
-		GiftName:     giftName,
-		Quantity:     r.Quantity,
-		TotalPoint:   r.TotalPoint,
-		RedeemedAt:   r.RedeemedAt.Format(time.RFC3339),
+		RedemptionID:     r.ID,
+		GiftID:           r.GiftID,
+		GiftName:         giftName,
+		Quantity:         r.Quantity,
+		TotalPoint:       r.TotalPoint,
+		RedeemedAt:       r.RedeemedAt.Format(time.RFC3339),
+		CampaignID:       r.CampaignID,
+		CampaignDiscount: r.CampaignDiscount,
+		CouponID:         r.CouponID,
+		CouponDiscount:   r.CouponDiscount,
 	}
 }
\ No newline at end of file
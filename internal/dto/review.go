@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type ReviewQuery struct {
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+	SortBy  string `form:"sort_by"`
+	SortDir string `form:"sort_dir"`
+}
+
+func (q *ReviewQuery) Normalize() {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 10
+	}
+	if q.SortBy != "score" && q.SortBy != "most_helpful" {
+		q.SortBy = "created_at"
+	}
+	if q.SortDir != "asc" {
+		q.SortDir = "desc"
+	}
+}
+
+type ReviewVoteRequest struct {
+	// pointer so that an explicit false is not rejected by "required"
+	IsHelpful *bool `json:"is_helpful" binding:"required"`
+}
+
+type ReviewReplyRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+type ReviewReplyResponse struct {
+	ID        uint   `json:"id"`
+	AdminID   uint   `json:"admin_id"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ReviewResponse struct {
+	ID              uint                 `json:"id"`
+	GiftID          uint                 `json:"gift_id"`
+	UserID          uint                 `json:"user_id"`
+	UserName        string               `json:"user_name"`
+	Score           float64              `json:"score"`
+	HelpfulCount    int                  `json:"helpful_count"`
+	NotHelpfulCount int                  `json:"not_helpful_count"`
+	Reply           *ReviewReplyResponse `json:"reply,omitempty"`
+	Photos          []ImageResponse      `json:"photos,omitempty"`
+	CreatedAt       string               `json:"created_at"`
+}
+
+func ToReviewReplyResponse(r model.ReviewReply) ReviewReplyResponse {
+	return ReviewReplyResponse{
+		ID:        r.ID,
+		AdminID:   r.AdminID,
+		Body:      r.Body,
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToReviewResponse(r model.Rating) ReviewResponse {
+	res := ReviewResponse{
+		ID:              r.ID,
+		GiftID:          r.GiftID,
+		UserID:          r.UserID,
+		Score:           RoundToHalf(r.Score),
+		HelpfulCount:    r.HelpfulCount,
+		NotHelpfulCount: r.NotHelpfulCount,
+		Photos:          ToImageResponses(r.Photos),
+		CreatedAt:       r.CreatedAt.Format(time.RFC3339),
+	}
+	if r.User != nil {
+		res.UserName = r.User.Name
+	}
+	if r.Reply != nil {
+		reply := ToReviewReplyResponse(*r.Reply)
+		res.Reply = &reply
+	}
+	return res
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=50"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateRoleRequest replaces the description and the full permission set
+type UpdateRoleRequest struct {
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type PermissionResponse struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	APIKeyGrantable bool   `json:"api_key_grantable"`
+}
+
+type RoleResponse struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+func ToPermissionResponse(p model.Permission) PermissionResponse {
+	return PermissionResponse{
+		Name:            p.Name,
+		Description:     p.Description,
+		APIKeyGrantable: p.APIKeyGrantable,
+	}
+}
+
+func ToRoleResponse(r model.Role) RoleResponse {
+	return RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		IsSystem:    r.IsSystem,
+		Permissions: r.PermissionNames(),
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   r.UpdatedAt.Format(time.RFC3339),
+	}
+}
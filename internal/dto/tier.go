@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type TierRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	// MinPoints is the qualifying points needed to reach the tier
+	MinPoints       int    `json:"min_points" binding:"min=0"`
+	DiscountPercent int    `json:"discount_percent" binding:"min=0,max=100"`
+	Benefits        string `json:"benefits" binding:"max=500"`
+	// TransfersEnabled lets members send points; defaults to true
+	TransfersEnabled *bool `json:"transfers_enabled"`
+}
+
+// AllowsTransfers reads TransfersEnabled with its default
+func (r TierRequest) AllowsTransfers() bool {
+	return r.TransfersEnabled == nil || *r.TransfersEnabled
+}
+
+type TierResponse struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	MinPoints        int    `json:"min_points"`
+	DiscountPercent  int    `json:"discount_percent"`
+	Benefits         string `json:"benefits"`
+	TransfersEnabled bool   `json:"transfers_enabled"`
+	CreatedAt        string `json:"created_at"`
+}
+
+func ToTierResponse(t model.Tier) TierResponse {
+	return TierResponse{
+		ID:               t.ID,
+		Name:             t.Name,
+		MinPoints:        t.MinPoints,
+		DiscountPercent:  t.DiscountPercent,
+		Benefits:         t.Benefits,
+		TransfersEnabled: t.TransfersEnabled,
+		CreatedAt:        t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type TierChangeResponse struct {
+	FromTier         string `json:"from_tier"`
+	ToTier           string `json:"to_tier"`
+	QualifyingPoints int64  `json:"qualifying_points"`
+	ChangedAt        string `json:"changed_at"`
+}
+
+func ToTierChangeResponses(changes []model.TierChange) []TierChangeResponse {
+	res := make([]TierChangeResponse, len(changes))
+	for i, c := range changes {
+		res[i] = TierChangeResponse{
+			FromTier:         c.FromTierName,
+			ToTier:           c.ToTierName,
+			QualifyingPoints: c.QualifyingPoints,
+			ChangedAt:        c.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return res
+}
+
+type TierEvaluationResponse struct {
+	UsersEvaluated int `json:"users_evaluated"`
+	Promoted       int `json:"promoted"`
+	Demoted        int `json:"demoted"`
+}
+
+// ProfileResponse is the caller's own account with its loyalty tier
+type ProfileResponse struct {
+	UserResponse
+	Tier        *TierResponse        `json:"tier"`
+	TierChanges []TierChangeResponse `json:"tier_changes"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+type TransferRequest struct {
+	// RecipientEmail identifies a colleague in the sender's organisation
+	RecipientEmail string `json:"recipient_email" binding:"required,email"`
+	Points         int    `json:"points" binding:"required,min=1,max=1000000000"`
+	Message        string `json:"message" binding:"max=200"`
+}
+
+type TransferResponse struct {
+	ID            uint   `json:"id"`
+	SenderID      uint   `json:"sender_id"`
+	SenderName    string `json:"sender_name"`
+	RecipientID   uint   `json:"recipient_id"`
+	RecipientName string `json:"recipient_name"`
+	Points        int    `json:"points"`
+	Message       string `json:"message"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// ToTransferResponse expects Sender and Recipient to be loaded
+func ToTransferResponse(t model.PointTransfer) TransferResponse {
+	res := TransferResponse{
+		ID:          t.ID,
+		SenderID:    t.SenderID,
+		RecipientID: t.RecipientID,
+		Points:      t.Points,
+		Message:     t.Message,
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+	}
+	if t.Sender != nil {
+		res.SenderName = t.Sender.Name
+	}
+	if t.Recipient != nil {
+		res.RecipientName = t.Recipient.Name
+	}
+	return res
+}
+
+// TransferPolicyRequest replaces the organisation's transfer policy
+type TransferPolicyRequest struct {
+	Enabled bool `json:"enabled"`
+	// DailyLimit caps the points one user sends per UTC day; 0 is unlimited
+	DailyLimit int `json:"daily_limit" binding:"min=0"`
+	// MinBalance is what a sender must keep after a transfer
+	MinBalance int `json:"min_balance" binding:"min=0"`
+}
+
+type TransferPolicyResponse struct {
+	Enabled    bool `json:"enabled"`
+	DailyLimit int  `json:"daily_limit"`
+	MinBalance int  `json:"min_balance"`
+}
+
+func ToTransferPolicyResponse(p model.TransferPolicy) TransferPolicyResponse {
+	return TransferPolicyResponse{
+		Enabled:    p.Enabled,
+		DailyLimit: p.DailyLimit,
+		MinBalance: p.MinBalance,
+	}
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gift-redemption/internal/model"
@@ -10,29 +11,94 @@ type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"omitempty,oneof=admin user"`
+	Role     string `json:"role" binding:"omitempty,max=50"`
 }
 
 type UpdateUserRequest struct {
 	Name  string `json:"name" binding:"required"`
 	Email string `json:"email" binding:"required,email"`
-	Role  string `json:"role" binding:"required,oneof=admin user"`
+	Role  string `json:"role" binding:"required,max=50"`
+}
+
+type UserQuery struct {
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+	SortBy  string `form:"sort_by"`
+	SortDir string `form:"sort_dir"`
+	// Search matches name or email
+	Search   string `form:"search" binding:"max=100"`
+	Role     string `form:"role" binding:"max=50"`
+	Verified *bool  `form:"verified"`
+	// CreatedFrom and CreatedTo are inclusive calendar dates (YYYY-MM-DD, UTC)
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02" time_utc:"1"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02" time_utc:"1"`
+	Deleted     string    `form:"deleted" binding:"omitempty,oneof=exclude include only"`
+}
+
+func (q *UserQuery) Normalize() {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 10
+	}
+	if q.SortBy != "name" && q.SortBy != "email" {
+		q.SortBy = "created_at"
+	}
+	if q.SortDir != "asc" {
+		q.SortDir = "desc"
+	}
+	if q.Deleted == "" {
+		q.Deleted = "exclude"
+	}
+	q.Search = strings.TrimSpace(q.Search)
 }
 
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	CreatedAt string `json:"created_at"`
+	ID                      uint            `json:"id"`
+	Name                    string          `json:"name"`
+	Email                   string          `json:"email"`
+	Role                    string          `json:"role"`
+	EmailVerified           bool            `json:"email_verified"`
+	AvatarURL               string          `json:"avatar_url"`
+	Locale                  string          `json:"locale"`
+	NotificationPreferences map[string]bool `json:"notification_preferences"`
+	TwoFactorEnabled        bool            `json:"two_factor_enabled"`
+	PointBalance            int             `json:"point_balance"`
+	TierID                  *uint           `json:"tier_id,omitempty"`
+	RedemptionCount         int64           `json:"redemption_count"`
+	PointsRedeemed          int64           `json:"points_redeemed"`
+	CreatedAt               string          `json:"created_at"`
+	DeletedAt               string          `json:"deleted_at,omitempty"`
+	ErasedAt                string          `json:"erased_at,omitempty"`
 }
 
+// ToUserResponse leaves the redemption counts at zero; services fill them in
+// from repository.RedemptionRepository.StatsByUsers.
 func ToUserResponse(u model.User) UserResponse {
-	return UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Role:      string(u.Role),
-		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+	prefs := map[string]bool(u.NotificationPreferences)
+	if prefs == nil {
+		prefs = map[string]bool{}
+	}
+	res := UserResponse{
+		ID:                      u.ID,
+		Name:                    u.Name,
+		Email:                   u.Email,
+		Role:                    string(u.Role),
+		EmailVerified:           u.IsEmailVerified(),
+		AvatarURL:               u.AvatarURL,
+		Locale:                  u.Locale,
+		NotificationPreferences: prefs,
+		TwoFactorEnabled:        u.IsTwoFactorEnabled(),
+		PointBalance:            u.PointBalance,
+		TierID:                  u.TierID,
+		CreatedAt:               u.CreatedAt.Format(time.RFC3339),
+	}
+	if u.DeletedAt.Valid {
+		res.DeletedAt = u.DeletedAt.Time.Format(time.RFC3339)
+	}
+	if u.ErasedAt != nil {
+		res.ErasedAt = u.ErasedAt.Format(time.RFC3339)
 	}
+	return res
 }
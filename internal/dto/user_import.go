@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+)
+
+// ImportRow is one parsed line of a user import file. Line is the line number
+// in the file, the header being line 1.
+type ImportRow struct {
+	Line   int
+	Name   string
+	Email  string
+	Role   string
+	Points string
+}
+
+type UserImportResponse struct {
+	ID            uint                  `json:"id"`
+	Filename      string                `json:"filename"`
+	Status        string                `json:"status"`
+	TotalRows     int                   `json:"total_rows"`
+	ProcessedRows int                   `json:"processed_rows"`
+	SucceededRows int                   `json:"succeeded_rows"`
+	FailedRows    int                   `json:"failed_rows"`
+	Failures      []model.ImportFailure `json:"failures"`
+	CreatedByID   uint                  `json:"created_by_id"`
+	CreatedAt     time.Time             `json:"created_at"`
+	StartedAt     *time.Time            `json:"started_at,omitempty"`
+	FinishedAt    *time.Time            `json:"finished_at,omitempty"`
+}
+
+func ToUserImportResponse(job model.UserImport) UserImportResponse {
+	failures := []model.ImportFailure(job.Failures)
+	if failures == nil {
+		failures = []model.ImportFailure{}
+	}
+	return UserImportResponse{
+		ID:            job.ID,
+		Filename:      job.Filename,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		SucceededRows: job.SucceededRows,
+		FailedRows:    job.FailedRows,
+		Failures:      failures,
+		CreatedByID:   job.CreatedByID,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService}
+}
+
+// GetAPIKeys godoc
+// @Summary      List API keys
+// @Description  Returns all API keys with their scopes, status and last use. Secrets are never returned. (requires security:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.APIKeyResponse}
+// @Failure      403  {object}  response.envelope
+// @Router       /admin/api-keys [get]
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAll()
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch api keys")
+		return
+	}
+	response.Success(c, "api keys retrieved successfully", keys)
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Issues a scoped key for a service integration. The full key is only shown in this response. (requires security:manage)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateAPIKeyRequest  true  "Key name, scopes, optional expiry and organisation"
+// @Success      201   {object}  response.envelope{data=dto.APIKeyCreatedResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Unknown scope or organisation"
+// @Router       /admin/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	key, err := h.apiKeyService.Create(middleware.GetUserID(c), req, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidScope) || errors.Is(err, apperror.ErrUnknownOrg) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "failed to create api key")
+		return
+	}
+	response.Created(c, "api key created; store it now, it will not be shown again", key)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Immediately stops the key from authenticating. Revoking an already revoked key is a no-op. (requires security:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  response.envelope
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(middleware.GetUserID(c), id, c.ClientIP()); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "api key not found")
+			return
+		}
+		response.InternalServerError(c, "failed to revoke api key")
+		return
+	}
+	response.Success(c, "api key revoked successfully", nil)
+}
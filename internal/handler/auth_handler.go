@@ -2,8 +2,11 @@ package handler
 
 import (
 	"errors"
+	"math"
+	"strconv"
 
 	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gift-redemption/internal/service"
@@ -18,9 +21,96 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 	return &AuthHandler{authService}
 }
 
+// Register godoc
+// @Summary      Register
+// @Description  Create a regular user account. A verification link is emailed; login is blocked until the email is verified.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        X-Organisation  header    string                 false  "Organisation slug to join (default organisation when omitted)"
+// @Param        body            body      dto.CreateUserRequest  true   "Account data (role is ignored)"
+// @Success      201   {object}  response.envelope{data=dto.UserResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope
+// @Router       /register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	user, err := h.authService.Register(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrDuplicateEntry) {
+			response.UnprocessableEntity(c, "email already registered", nil)
+			return
+		}
+		response.InternalServerError(c, "failed to register")
+		return
+	}
+
+	response.Created(c, "registration successful, please check your email to verify your account", user)
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Verify an email address with the signed token from the verification link
+// @Tags         Auth
+// @Produce      json
+// @Param        token  query     string  true  "Verification token"
+// @Success      200    {object}  response.envelope{data=dto.UserResponse}
+// @Failure      400    {object}  response.envelope
+// @Failure      401    {object}  response.envelope
+// @Router       /verify-email [get]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "invalid query parameters", err.Error())
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidToken) {
+			response.Unauthorized(c, "invalid or expired verification link")
+			return
+		}
+		response.InternalServerError(c, "failed to verify email")
+		return
+	}
+
+	response.Success(c, "email verified successfully", user)
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link. Always succeeds so account existence is not revealed.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.ResendVerificationRequest  true  "Email"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Router       /verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.authService.ResendVerification(req); err != nil {
+		response.InternalServerError(c, "something went wrong")
+		return
+	}
+
+	response.Success(c, "if the account exists and is not verified, a new link has been sent", nil)
+}
+
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user and return JWT token
+// @Description  Authenticate user and return JWT token. Accounts using two-factor authentication receive a challenge token for /login/2fa instead.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -28,6 +118,8 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Success      200   {object}  response.envelope{data=dto.LoginResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      401   {object}  response.envelope
+// @Failure      403   {object}  response.envelope  "Email not verified"
+// @Failure      429   {object}  response.envelope  "Too many failed attempts"
 // @Router       /login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -36,15 +128,169 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Login(req)
+	result, err := h.authService.Login(req, c.ClientIP())
 	if err != nil {
-		if errors.Is(err, apperror.ErrNotFound) {
+		var locked *apperror.LockedError
+		switch {
+		case errors.As(err, &locked):
+			tooManyAttempts(c, locked)
+		case errors.Is(err, apperror.ErrNotFound):
 			response.Unauthorized(c, "invalid email or password")
+		case errors.Is(err, apperror.ErrEmailNotVerified):
+			response.Forbidden(c, "email not verified, please check your inbox")
+		default:
+			response.InternalServerError(c, "something went wrong")
+		}
+		return
+	}
+
+	if result.TwoFactorRequired {
+		response.Success(c, "two-factor authentication required", result)
+		return
+	}
+	response.Success(c, "login successful", result)
+}
+
+// SetupTwoFactorLogin godoc
+// @Summary      Set up two-factor authentication during login
+// @Description  For accounts that must use 2FA but have not enrolled yet. Returns a TOTP secret and otpauth URI; confirm with the first code at /login/2fa.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.TwoFactorChallengeRequest  true  "Challenge token from /login"
+// @Success      200   {object}  response.envelope{data=dto.TwoFactorSetupResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      401   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Two-factor authentication already enabled"
+// @Router       /login/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.authService.SetupTwoFactor(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidToken):
+			response.Unauthorized(c, "invalid or expired challenge token")
+		case errors.Is(err, apperror.ErrTwoFactorEnabled):
+			response.UnprocessableEntity(c, "two-factor authentication already enabled", nil)
+		default:
+			response.InternalServerError(c, "something went wrong")
+		}
+		return
+	}
+
+	response.Success(c, "scan the QR code with your authenticator app", result)
+}
+
+// LoginTwoFactor godoc
+// @Summary      Complete login with a second factor
+// @Description  Exchange the challenge token from /login and an authenticator or recovery code for JWT tokens
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.LoginTwoFactorRequest  true  "Challenge token and code"
+// @Success      200   {object}  response.envelope{data=dto.LoginResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      401   {object}  response.envelope
+// @Failure      429   {object}  response.envelope  "Too many failed attempts"
+// @Router       /login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req dto.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.authService.LoginTwoFactor(req, c.ClientIP())
+	if err != nil {
+		var locked *apperror.LockedError
+		switch {
+		case errors.As(err, &locked):
+			tooManyAttempts(c, locked)
+		case errors.Is(err, apperror.ErrInvalidToken):
+			response.Unauthorized(c, "invalid or expired challenge token")
+		case errors.Is(err, apperror.ErrInvalidOTP):
+			response.Unauthorized(c, "invalid two-factor code")
+		case errors.Is(err, apperror.ErrTwoFactorNotSetUp):
+			response.BadRequest(c, "two-factor setup has not been started", nil)
+		default:
+			response.InternalServerError(c, "something went wrong")
+		}
+		return
+	}
+
+	response.Success(c, "login successful", result)
+}
+
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Exchange a refresh token for a new access and refresh token. Each refresh token can be used once; reusing one revokes the whole session.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.RefreshRequest  true  "Refresh token"
+// @Success      200   {object}  response.envelope{data=dto.TokenResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      401   {object}  response.envelope
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.authService.Refresh(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrTokenReused):
+			response.Unauthorized(c, "refresh token already used, session revoked")
+		case errors.Is(err, apperror.ErrInvalidToken):
+			response.Unauthorized(c, "invalid or expired refresh token")
+		default:
+			response.InternalServerError(c, "something went wrong")
+		}
+		return
+	}
+
+	response.Success(c, "token refreshed successfully", result)
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Revoke the session of the given refresh token. Access tokens of the session stop working immediately.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.LogoutRequest  true  "Refresh token"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Failure      401   {object}  response.envelope
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.authService.Logout(req); err != nil {
+		if errors.Is(err, apperror.ErrInvalidToken) {
+			response.Unauthorized(c, "invalid refresh token")
 			return
 		}
 		response.InternalServerError(c, "something went wrong")
 		return
 	}
 
-	response.Success(c, "login successful", result)
+	response.Success(c, "logout successful", nil)
+}
+
+func tooManyAttempts(c *gin.Context, locked *apperror.LockedError) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	response.TooManyRequests(c, "too many failed login attempts, please try again later")
 }
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CampaignHandler struct {
+	campaignService service.CampaignService
+}
+
+func NewCampaignHandler(campaignService service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaignService}
+}
+
+// GetCampaigns godoc
+// @Summary      List campaigns
+// @Description  Returns the organisation's campaigns, latest start first, with whether each is scheduled, running or ended (requires campaigns:manage)
+// @Tags         Campaigns
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.CampaignResponse}
+// @Router       /campaigns [get]
+func (h *CampaignHandler) GetAll(c *gin.Context) {
+	campaigns, err := h.campaignService.GetAll(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch campaigns")
+		return
+	}
+	response.Success(c, "campaigns retrieved successfully", campaigns)
+}
+
+// CreateCampaign godoc
+// @Summary      Create campaign
+// @Description  Schedules a percent or fixed points discount on the targeted gifts and categories. When several campaigns target a gift the lowest price wins, and tier discounts apply on top (requires campaigns:manage)
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CampaignRequest  true  "Campaign data"
+// @Success      201   {object}  response.envelope{data=dto.CampaignResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "No target, unknown gift or percent over 100"
+// @Router       /campaigns [post]
+func (h *CampaignHandler) Create(c *gin.Context) {
+	var req dto.CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.Create(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		h.handleError(c, err, "failed to create campaign")
+		return
+	}
+	response.Created(c, "campaign created successfully", campaign)
+}
+
+// UpdateCampaign godoc
+// @Summary      Update campaign
+// @Description  Replaces a campaign. Gift prices change at once; past redemptions keep what they paid (requires campaigns:manage)
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                  true  "Campaign ID"
+// @Param        body  body      dto.CampaignRequest  true  "Campaign data"
+// @Success      200   {object}  response.envelope{data=dto.CampaignResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "No target, unknown gift or percent over 100"
+// @Router       /campaigns/{id} [put]
+func (h *CampaignHandler) Update(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.Update(middleware.GetOrganisationID(c), id, req)
+	if err != nil {
+		h.handleError(c, err, "failed to update campaign")
+		return
+	}
+	response.Success(c, "campaign updated successfully", campaign)
+}
+
+// DeleteCampaign godoc
+// @Summary      Delete campaign
+// @Description  Ends a campaign's discount at once. Its report stays available through past redemptions (requires campaigns:manage)
+// @Tags         Campaigns
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Campaign ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /campaigns/{id} [delete]
+func (h *CampaignHandler) Delete(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.campaignService.Delete(middleware.GetOrganisationID(c), id); err != nil {
+		h.handleError(c, err, "failed to delete campaign")
+		return
+	}
+	response.Success(c, "campaign deleted successfully", nil)
+}
+
+// GetCampaignReport godoc
+// @Summary      Campaign report
+// @Description  Sums up the redemptions the campaign priced: how many, by how many users, the points spent and the points the discount saved (requires campaigns:manage)
+// @Tags         Campaigns
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Campaign ID"
+// @Success      200  {object}  response.envelope{data=dto.CampaignReportResponse}
+// @Failure      404  {object}  response.envelope
+// @Router       /campaigns/{id}/report [get]
+func (h *CampaignHandler) Report(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	report, err := h.campaignService.Report(middleware.GetOrganisationID(c), id)
+	if err != nil {
+		h.handleError(c, err, "failed to build campaign report")
+		return
+	}
+	response.Success(c, "campaign report retrieved successfully", report)
+}
+
+func (h *CampaignHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "campaign not found")
+	case errors.Is(err, apperror.ErrInvalidCampaign):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
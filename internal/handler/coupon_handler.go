@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CouponHandler struct {
+	couponService service.CouponService
+}
+
+func NewCouponHandler(couponService service.CouponService) *CouponHandler {
+	return &CouponHandler{couponService}
+}
+
+// GetCoupons godoc
+// @Summary      List coupons
+// @Description  Returns the organisation's coupon codes with their uses so far and whether each is scheduled, active, expired or used up (requires coupons:manage)
+// @Tags         Coupons
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.CouponResponse}
+// @Router       /coupons [get]
+func (h *CouponHandler) GetAll(c *gin.Context) {
+	coupons, err := h.couponService.GetAll(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch coupons")
+		return
+	}
+	response.Success(c, "coupons retrieved successfully", coupons)
+}
+
+// CreateCoupon godoc
+// @Summary      Create coupon
+// @Description  Creates a code users can enter when redeeming. It takes a percent or fixed number of points off the total, or makes one unit free, within optional usage limits, validity window, minimum total and gift or category targets (requires coupons:manage)
+// @Tags         Coupons
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CouponRequest  true  "Coupon data"
+// @Success      201   {object}  response.envelope{data=dto.CouponResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Invalid or taken code, invalid discount or limits, or unknown gift"
+// @Router       /coupons [post]
+func (h *CouponHandler) Create(c *gin.Context) {
+	var req dto.CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	coupon, err := h.couponService.Create(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		h.handleError(c, err, "failed to create coupon")
+		return
+	}
+	response.Created(c, "coupon created successfully", coupon)
+}
+
+// UpdateCoupon godoc
+// @Summary      Update coupon
+// @Description  Replaces a coupon. Uses so far count against the new limits; past redemptions keep their discount (requires coupons:manage)
+// @Tags         Coupons
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                true  "Coupon ID"
+// @Param        body  body      dto.CouponRequest  true  "Coupon data"
+// @Success      200   {object}  response.envelope{data=dto.CouponResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Invalid or taken code, invalid discount or limits, or unknown gift"
+// @Router       /coupons/{id} [put]
+func (h *CouponHandler) Update(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	coupon, err := h.couponService.Update(middleware.GetOrganisationID(c), id, req)
+	if err != nil {
+		h.handleError(c, err, "failed to update coupon")
+		return
+	}
+	response.Success(c, "coupon updated successfully", coupon)
+}
+
+// DeleteCoupon godoc
+// @Summary      Delete coupon
+// @Description  Stops the code from being accepted. Redemptions that used it keep their discount (requires coupons:manage)
+// @Tags         Coupons
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Coupon ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /coupons/{id} [delete]
+func (h *CouponHandler) Delete(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.couponService.Delete(middleware.GetOrganisationID(c), id); err != nil {
+		h.handleError(c, err, "failed to delete coupon")
+		return
+	}
+	response.Success(c, "coupon deleted successfully", nil)
+}
+
+func (h *CouponHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "coupon not found")
+	case errors.Is(err, apperror.ErrDuplicateEntry):
+		response.UnprocessableEntity(c, "coupon code already taken", nil)
+	case errors.Is(err, apperror.ErrInvalidCoupon):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EarningHandler struct {
+	earningService service.EarningService
+}
+
+func NewEarningHandler(earningService service.EarningService) *EarningHandler {
+	return &EarningHandler{earningService}
+}
+
+// GetEarningRules godoc
+// @Summary      List earning rules
+// @Description  Returns the rules that turn business events into points (requires points:manage)
+// @Tags         Earning
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.EarningRuleResponse}
+// @Router       /earning-rules [get]
+func (h *EarningHandler) GetRules(c *gin.Context) {
+	rules, err := h.earningService.GetRules(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch earning rules")
+		return
+	}
+	response.Success(c, "earning rules retrieved successfully", rules)
+}
+
+// CreateEarningRule godoc
+// @Summary      Create earning rule
+// @Description  Adds a rule awarding points for events of one type. An event earns points plus points_per_unit for each unit of unit_attribute, times multiplier, capped by max_per_event and by user_cap over user_cap_days. Every matching rule awards (requires points:manage)
+// @Tags         Earning
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.EarningRuleRequest  true  "Rule data"
+// @Success      201   {object}  response.envelope{data=dto.EarningRuleResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Inconsistent rule"
+// @Router       /earning-rules [post]
+func (h *EarningHandler) CreateRule(c *gin.Context) {
+	var req dto.EarningRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.earningService.CreateRule(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		h.handleRuleError(c, err, "failed to create earning rule")
+		return
+	}
+	response.Created(c, "earning rule created successfully", rule)
+}
+
+// UpdateEarningRule godoc
+// @Summary      Update earning rule
+// @Description  Replaces a rule. It applies to events received from now on (requires points:manage)
+// @Tags         Earning
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                     true  "Rule ID"
+// @Param        body  body      dto.EarningRuleRequest  true  "Rule data"
+// @Success      200   {object}  response.envelope{data=dto.EarningRuleResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Inconsistent rule"
+// @Router       /earning-rules/{id} [put]
+func (h *EarningHandler) UpdateRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.EarningRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.earningService.UpdateRule(middleware.GetOrganisationID(c), id, req)
+	if err != nil {
+		h.handleRuleError(c, err, "failed to update earning rule")
+		return
+	}
+	response.Success(c, "earning rule updated successfully", rule)
+}
+
+// DeleteEarningRule godoc
+// @Summary      Delete earning rule
+// @Description  Stops a rule from awarding points. Points it already awarded stay (requires points:manage)
+// @Tags         Earning
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Rule ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /earning-rules/{id} [delete]
+func (h *EarningHandler) DeleteRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.earningService.DeleteRule(middleware.GetOrganisationID(c), id); err != nil {
+		h.handleRuleError(c, err, "failed to delete earning rule")
+		return
+	}
+	response.Success(c, "earning rule deleted successfully", nil)
+}
+
+// IngestEvent godoc
+// @Summary      Send a business event
+// @Description  Records an event such as a purchase, completed training or work anniversary and credits the points every matching earning rule awards. Resending an event_id awards nothing and returns the original event with duplicate set (API keys with events:ingest only)
+// @Tags         Earning
+// @Accept       json
+// @Produce      json
+// @Security     APIKeyAuth
+// @Param        body  body      dto.PointEventRequest  true  "Event"
+// @Success      201   {object}  response.envelope{data=dto.PointEventResponse}
+// @Success      200   {object}  response.envelope{data=dto.PointEventResponse}  "Event already received"
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Unknown user or event in the future"
+// @Router       /events [post]
+func (h *EarningHandler) Ingest(c *gin.Context) {
+	var req dto.PointEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	event, err := h.earningService.Ingest(middleware.GetOrganisationID(c), middleware.GetAPIKeyID(c), req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidEvent) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "failed to record event")
+		return
+	}
+	if event.Duplicate {
+		response.Success(c, "event already received", event)
+		return
+	}
+	response.Created(c, "event recorded successfully", event)
+}
+
+func (h *EarningHandler) handleRuleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "earning rule not found")
+	case errors.Is(err, apperror.ErrInvalidRule):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FlashSaleHandler struct {
+	flashSaleService service.FlashSaleService
+}
+
+func NewFlashSaleHandler(flashSaleService service.FlashSaleService) *FlashSaleHandler {
+	return &FlashSaleHandler{flashSaleService}
+}
+
+// GetFlashSales godoc
+// @Summary      List flash sales
+// @Description  Returns the organisation's flash sales, latest start first, with units claimed and remaining (requires campaigns:manage)
+// @Tags         Flash Sales
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.FlashSaleResponse}
+// @Router       /flash-sales [get]
+func (h *FlashSaleHandler) GetAll(c *gin.Context) {
+	sales, err := h.flashSaleService.GetAll(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch flash sales")
+		return
+	}
+	response.Success(c, "flash sales retrieved successfully", sales)
+}
+
+// CreateFlashSale godoc
+// @Summary      Create flash sale
+// @Description  Reserves quantity units of a gift's stock and sells them one per user from starts_at. While the sale runs, redeeming the gift claims a sale unit instead of taking regular stock (requires campaigns:manage)
+// @Tags         Flash Sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.FlashSaleRequest  true  "Flash sale data"
+// @Success      201   {object}  response.envelope{data=dto.FlashSaleResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Unknown gift, overlapping sale or not enough stock"
+// @Router       /flash-sales [post]
+func (h *FlashSaleHandler) Create(c *gin.Context) {
+	var req dto.FlashSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	sale, err := h.flashSaleService.Create(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		h.handleError(c, err, "failed to create flash sale")
+		return
+	}
+	response.Created(c, "flash sale created successfully", sale)
+}
+
+// DeleteFlashSale godoc
+// @Summary      Delete flash sale
+// @Description  Stops a flash sale and returns its unclaimed units to the gift's stock (requires campaigns:manage)
+// @Tags         Flash Sales
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Flash sale ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /flash-sales/{id} [delete]
+func (h *FlashSaleHandler) Delete(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.flashSaleService.Delete(middleware.GetOrganisationID(c), id); err != nil {
+		h.handleError(c, err, "failed to delete flash sale")
+		return
+	}
+	response.Success(c, "flash sale deleted successfully", nil)
+}
+
+func (h *FlashSaleHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "flash sale not found")
+	case errors.Is(err, apperror.ErrInvalidFlashSale):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	case errors.Is(err, apperror.ErrInsufficientStock):
+		response.UnprocessableEntity(c, "gift stock is below the sale quantity", nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
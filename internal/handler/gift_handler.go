@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gift-redemption/internal/service"
@@ -20,10 +22,11 @@ func NewGiftHandler(giftService service.GiftService) *GiftHandler {
 
 // GetGifts godoc
 // @Summary      Get all gifts
-// @Description  Returns paginated list of gifts with sorting options
+// @Description  Returns paginated list of gifts with sorting options. Tier-exclusive gifts are only listed for users in that tier or higher, and for callers with gifts:write. effective_point is the price after the running campaign, if any
 // @Tags         Gifts
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        page      query     int     false  "Page number (default: 1)"
 // @Param        limit     query     int     false  "Items per page (default: 10, max: 100)"
 // @Param        sort_by   query     string  false  "Sort field: created_at | avg_rating (default: created_at)"
@@ -38,7 +41,7 @@ func (h *GiftHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	gifts, pagination, err := h.giftService.GetAll(query)
+	gifts, pagination, err := h.giftService.GetAll(middleware.GetOrganisationID(c), giftViewer(c), query)
 	if err != nil {
 		response.InternalServerError(c, "failed to fetch gifts")
 		return
@@ -53,6 +56,7 @@ func (h *GiftHandler) GetAll(c *gin.Context) {
 // @Tags         Gifts
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        id   path      int  true  "Gift ID"
 // @Success      200  {object}  response.envelope{data=dto.GiftResponse}
 // @Failure      404  {object}  response.envelope
@@ -63,7 +67,7 @@ func (h *GiftHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	gift, err := h.giftService.GetByID(id)
+	gift, err := h.giftService.GetByID(middleware.GetOrganisationID(c), giftViewer(c), id)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "gift not found")
@@ -78,14 +82,16 @@ func (h *GiftHandler) GetByID(c *gin.Context) {
 
 // CreateGift godoc
 // @Summary      Create gift
-// @Description  Create a new gift item (admin only)
+// @Description  Create a new gift item (requires gifts:write)
 // @Tags         Gifts
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        body  body      dto.CreateGiftRequest  true  "Gift data"
 // @Success      201   {object}  response.envelope{data=dto.GiftResponse}
 // @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Tier does not exist"
 // @Router       /gifts [post]
 func (h *GiftHandler) Create(c *gin.Context) {
 	var req dto.CreateGiftRequest
@@ -94,8 +100,12 @@ func (h *GiftHandler) Create(c *gin.Context) {
 		return
 	}
 
-	gift, err := h.giftService.Create(req)
+	gift, err := h.giftService.Create(middleware.GetOrganisationID(c), req)
 	if err != nil {
+		if errors.Is(err, apperror.ErrUnknownTier) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "failed to create gift")
 		return
 	}
@@ -105,16 +115,18 @@ func (h *GiftHandler) Create(c *gin.Context) {
 
 // UpdateGift godoc
 // @Summary      Update gift
-// @Description  Full update of a gift item (admin only)
+// @Description  Full update of a gift item (requires gifts:write)
 // @Tags         Gifts
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        id    path      int                    true  "Gift ID"
 // @Param        body  body      dto.UpdateGiftRequest  true  "Gift data"
 // @Success      200   {object}  response.envelope{data=dto.GiftResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Tier does not exist"
 // @Router       /gifts/{id} [put]
 func (h *GiftHandler) Update(c *gin.Context) {
 	id, err := parseID(c, "id")
@@ -128,12 +140,16 @@ func (h *GiftHandler) Update(c *gin.Context) {
 		return
 	}
 
-	gift, err := h.giftService.Update(id, req)
+	gift, err := h.giftService.Update(middleware.GetOrganisationID(c), id, req)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "gift not found")
 			return
 		}
+		if errors.Is(err, apperror.ErrUnknownTier) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "failed to update gift")
 		return
 	}
@@ -143,16 +159,18 @@ func (h *GiftHandler) Update(c *gin.Context) {
 
 // PatchGift godoc
 // @Summary      Patch gift
-// @Description  Partial update of a gift item (admin only)
+// @Description  Partial update of a gift item (requires gifts:write)
 // @Tags         Gifts
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        id    path      int                   true  "Gift ID"
 // @Param        body  body      dto.PatchGiftRequest  true  "Partial gift data"
 // @Success      200   {object}  response.envelope{data=dto.GiftResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Tier does not exist"
 // @Router       /gifts/{id} [patch]
 func (h *GiftHandler) Patch(c *gin.Context) {
 	id, err := parseID(c, "id")
@@ -166,12 +184,16 @@ func (h *GiftHandler) Patch(c *gin.Context) {
 		return
 	}
 
-	gift, err := h.giftService.Patch(id, req)
+	gift, err := h.giftService.Patch(middleware.GetOrganisationID(c), id, req)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "gift not found")
 			return
 		}
+		if errors.Is(err, apperror.ErrUnknownTier) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
 		response.InternalServerError(c, "failed to patch gift")
 		return
 	}
@@ -181,10 +203,11 @@ func (h *GiftHandler) Patch(c *gin.Context) {
 
 // DeleteGift godoc
 // @Summary      Delete gift
-// @Description  Soft delete a gift item (admin only)
+// @Description  Soft delete a gift item (requires gifts:write)
 // @Tags         Gifts
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        id   path      int  true  "Gift ID"
 // @Success      200  {object}  response.envelope
 // @Failure      404  {object}  response.envelope
@@ -195,7 +218,7 @@ func (h *GiftHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.giftService.Delete(id); err != nil {
+	if err := h.giftService.Delete(middleware.GetOrganisationID(c), id); err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "gift not found")
 			return
@@ -206,3 +229,11 @@ func (h *GiftHandler) Delete(c *gin.Context) {
 
 	response.Success(c, "gift deleted successfully", nil)
 }
+
+// giftViewer lets catalogue managers and integrations see every tier's gifts
+func giftViewer(c *gin.Context) service.GiftViewer {
+	return service.GiftViewer{
+		UserID:   middleware.GetUserID(c),
+		AllTiers: middleware.HasPermission(c, model.PermGiftsWrite) || middleware.GetActor(c).Type == model.ActorAPIKey,
+	}
+}
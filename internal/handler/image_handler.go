@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	imageService service.ImageService
+	maxBytes     int64
+}
+
+func NewImageHandler(imageService service.ImageService, maxBytes int64) *ImageHandler {
+	return &ImageHandler{imageService, maxBytes}
+}
+
+// UploadGiftImages godoc
+// @Summary      Upload gift gallery images
+// @Description  Upload one or more JPEG/PNG/GIF images to a gift gallery (requires gifts:write). Thumbnails are generated.
+// @Tags         Gifts
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int   true  "Gift ID"
+// @Param        files  formData  file  true  "Image files"
+// @Success      201    {object}  response.envelope{data=[]dto.ImageResponse}
+// @Failure      400    {object}  response.envelope
+// @Failure      404    {object}  response.envelope
+// @Failure      413    {object}  response.envelope
+// @Failure      422    {object}  response.envelope  "Unsupported file type or too many files"
+// @Router       /gifts/{id}/images [post]
+func (h *ImageHandler) UploadGiftImages(c *gin.Context) {
+	giftID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	files, ok := h.readFiles(c, service.MaxGiftImages)
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.UploadGiftImages(middleware.GetOrganisationID(c), middleware.GetUserID(c), giftID, files)
+	if err != nil {
+		h.handleError(c, err, "gift not found")
+		return
+	}
+
+	response.Created(c, "images uploaded successfully", images)
+}
+
+// DeleteGiftImage godoc
+// @Summary      Delete gift gallery image
+// @Description  Remove an image from a gift gallery (requires gifts:write)
+// @Tags         Gifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int  true  "Gift ID"
+// @Param        imageId  path      int  true  "Image ID"
+// @Success      200      {object}  response.envelope
+// @Failure      404      {object}  response.envelope
+// @Router       /gifts/{id}/images/{imageId} [delete]
+func (h *ImageHandler) DeleteGiftImage(c *gin.Context) {
+	giftID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+	imageID, err := parseID(c, "imageId")
+	if err != nil {
+		return
+	}
+
+	if err := h.imageService.DeleteGiftImage(middleware.GetOrganisationID(c), giftID, imageID); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "image not found")
+			return
+		}
+		response.InternalServerError(c, "failed to delete image")
+		return
+	}
+
+	response.Success(c, "image deleted successfully", nil)
+}
+
+// UploadReviewPhotos godoc
+// @Summary      Upload review photos
+// @Description  Attach photos to your own review. Thumbnails are generated.
+// @Tags         Reviews
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int   true  "Review ID"
+// @Param        files  formData  file  true  "Image files"
+// @Success      201    {object}  response.envelope{data=[]dto.ImageResponse}
+// @Failure      400    {object}  response.envelope
+// @Failure      403    {object}  response.envelope
+// @Failure      404    {object}  response.envelope
+// @Failure      413    {object}  response.envelope
+// @Failure      422    {object}  response.envelope  "Unsupported file type or too many files"
+// @Router       /reviews/{id}/photos [post]
+func (h *ImageHandler) UploadReviewPhotos(c *gin.Context) {
+	ratingID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	files, ok := h.readFiles(c, service.MaxReviewPhotos)
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.UploadReviewPhotos(middleware.GetOrganisationID(c), middleware.GetUserID(c), ratingID, files)
+	if err != nil {
+		h.handleError(c, err, "review not found")
+		return
+	}
+
+	response.Created(c, "photos uploaded successfully", images)
+}
+
+// readFiles reads the "files" multipart field. The request body is capped so
+// an oversized upload is cut off before it is buffered.
+func (h *ImageHandler) readFiles(c *gin.Context, maxFiles int) ([]dto.UploadFile, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes*int64(maxFiles)+1<<20)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.PayloadTooLarge(c, "request body too large")
+			return nil, false
+		}
+		response.BadRequest(c, "invalid multipart form", err.Error())
+		return nil, false
+	}
+
+	headers := form.File["files"]
+	if len(headers) == 0 {
+		response.BadRequest(c, "no files provided", nil)
+		return nil, false
+	}
+	if len(headers) > maxFiles {
+		response.UnprocessableEntity(c, "too many files", nil)
+		return nil, false
+	}
+
+	files := make([]dto.UploadFile, 0, len(headers))
+	for _, fh := range headers {
+		if fh.Size > h.maxBytes {
+			response.PayloadTooLarge(c, "file too large")
+			return nil, false
+		}
+		f, err := fh.Open()
+		if err != nil {
+			response.BadRequest(c, "failed to read file", nil)
+			return nil, false
+		}
+		data, err := io.ReadAll(io.LimitReader(f, h.maxBytes+1))
+		f.Close()
+		if err != nil {
+			response.BadRequest(c, "failed to read file", nil)
+			return nil, false
+		}
+		files = append(files, dto.UploadFile{Filename: fh.Filename, Data: data})
+	}
+
+	return files, true
+}
+
+func (h *ImageHandler) handleError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, notFoundMsg)
+	case errors.Is(err, apperror.ErrForbidden):
+		response.Forbidden(c, "you can only add photos to your own review")
+	case errors.Is(err, apperror.ErrFileTooLarge):
+		response.PayloadTooLarge(c, "file too large")
+	case errors.Is(err, apperror.ErrUnsupportedFile):
+		response.UnprocessableEntity(c, "only JPEG, PNG and GIF images are allowed", nil)
+	case errors.Is(err, apperror.ErrTooManyFiles):
+		response.UnprocessableEntity(c, "image limit reached", nil)
+	default:
+		response.InternalServerError(c, "failed to upload images")
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService}
+}
+
+// Impersonate godoc
+// @Summary      Impersonate a user
+// @Description  Issues a short-lived access token acting as the user, so support can see what they see. The token carries the admin's ID in its act claim, ends with the admin's session, cannot change credentials or spend points, and every request made with it is audited. Users holding a permission the admin lacks cannot be impersonated. (requires users:impersonate)
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                     true  "User ID"
+// @Param        body  body      dto.ImpersonateRequest  true  "Reason for the audit log"
+// @Success      200   {object}  response.envelope{data=dto.ImpersonationResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope  "Missing permission, already impersonating, or user cannot be impersonated"
+// @Failure      404   {object}  response.envelope
+// @Router       /users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.impersonationService.Impersonate(
+		middleware.GetOrganisationID(c),
+		middleware.GetUserID(c),
+		id,
+		middleware.GetSessionID(c),
+		req,
+		c.ClientIP(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		case errors.Is(err, apperror.ErrCannotImpersonate):
+			response.Forbidden(c, err.Error())
+		default:
+			response.InternalServerError(c, "failed to impersonate user")
+		}
+		return
+	}
+	response.Success(c, "impersonation token issued", result)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys}
+}
+
+// GetKeys godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys for verifying access tokens, selected by the token's kid header. Served as a plain RFC 7517 document without the response envelope. Empty when tokens are signed with HS256.
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  jwtkeys.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetKeys(c *gin.Context) {
+	// verifiers cache the set; rotated keys stay listed long enough for them to refresh
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
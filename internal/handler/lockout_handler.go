@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LockoutHandler struct {
+	lockoutService service.LockoutService
+}
+
+func NewLockoutHandler(lockoutService service.LockoutService) *LockoutHandler {
+	return &LockoutHandler{lockoutService}
+}
+
+// GetLockouts godoc
+// @Summary      List active login lockouts
+// @Description  Returns accounts and IP addresses currently locked after repeated failed logins (requires security:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.LockoutResponse}
+// @Failure      403  {object}  response.envelope
+// @Router       /admin/lockouts [get]
+func (h *LockoutHandler) GetAll(c *gin.Context) {
+	lockouts, err := h.lockoutService.GetLocked()
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch lockouts")
+		return
+	}
+	response.Success(c, "lockouts retrieved successfully", lockouts)
+}
+
+// ClearLockout godoc
+// @Summary      Clear a login lockout
+// @Description  Unlocks an account or IP address and resets its failed attempt counter (requires security:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Lockout ID"
+// @Success      200  {object}  response.envelope
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /admin/lockouts/{id} [delete]
+func (h *LockoutHandler) Clear(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.lockoutService.Clear(middleware.GetUserID(c), id, c.ClientIP()); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "lockout not found")
+			return
+		}
+		response.InternalServerError(c, "failed to clear lockout")
+		return
+	}
+
+	response.Success(c, "lockout cleared successfully", nil)
+}
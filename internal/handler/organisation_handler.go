@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganisationHandler struct {
+	orgService service.OrganisationService
+}
+
+func NewOrganisationHandler(orgService service.OrganisationService) *OrganisationHandler {
+	return &OrganisationHandler{orgService}
+}
+
+// GetOrganisations godoc
+// @Summary      List organisations
+// @Description  Returns every tenant on the platform (requires organisations:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.OrganisationResponse}
+// @Failure      403  {object}  response.envelope
+// @Router       /admin/organisations [get]
+func (h *OrganisationHandler) GetAll(c *gin.Context) {
+	orgs, err := h.orgService.GetAll()
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch organisations")
+		return
+	}
+	response.Success(c, "organisations retrieved successfully", orgs)
+}
+
+// GetOrganisation godoc
+// @Summary      Get organisation by ID
+// @Description  Returns a single tenant (requires organisations:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Organisation ID"
+// @Success      200  {object}  response.envelope{data=dto.OrganisationResponse}
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /admin/organisations/{id} [get]
+func (h *OrganisationHandler) GetByID(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	org, err := h.orgService.GetByID(id)
+	if err != nil {
+		h.handleError(c, err, "failed to fetch organisation")
+		return
+	}
+	response.Success(c, "organisation retrieved successfully", org)
+}
+
+// CreateOrganisation godoc
+// @Summary      Create organisation
+// @Description  Creates a tenant. Its slug is used in the X-Organisation header and cannot be changed later. (requires organisations:manage)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateOrganisationRequest  true  "Organisation name and slug"
+// @Success      201   {object}  response.envelope{data=dto.OrganisationResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Invalid slug or slug already taken"
+// @Router       /admin/organisations [post]
+func (h *OrganisationHandler) Create(c *gin.Context) {
+	var req dto.CreateOrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	org, err := h.orgService.Create(middleware.GetUserID(c), req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "failed to create organisation")
+		return
+	}
+	response.Created(c, "organisation created successfully", org)
+}
+
+// UpdateOrganisation godoc
+// @Summary      Update organisation
+// @Description  Renames a tenant (requires organisations:manage)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                            true  "Organisation ID"
+// @Param        body  body      dto.UpdateOrganisationRequest  true  "Organisation name"
+// @Success      200   {object}  response.envelope{data=dto.OrganisationResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Router       /admin/organisations/{id} [put]
+func (h *OrganisationHandler) Update(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.UpdateOrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	org, err := h.orgService.Update(middleware.GetUserID(c), id, req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "failed to update organisation")
+		return
+	}
+	response.Success(c, "organisation updated successfully", org)
+}
+
+func (h *OrganisationHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "organisation not found")
+	case errors.Is(err, apperror.ErrDuplicateEntry):
+		response.UnprocessableEntity(c, "organisation slug already taken", nil)
+	case errors.Is(err, apperror.ErrInvalidSlug):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordHandler struct {
+	passwordService service.PasswordService
+}
+
+func NewPasswordHandler(passwordService service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{passwordService}
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Change the current user's password. Other sessions are signed out and existing access tokens stop working; the current session continues after a refresh.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.ChangePasswordRequest  true  "Current and new password"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Current password is incorrect"
+// @Router       /me/password [post]
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	err := h.passwordService.ChangePassword(middleware.GetUserID(c), middleware.GetSessionID(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidPassword):
+			response.UnprocessableEntity(c, "current password is incorrect", nil)
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		default:
+			response.InternalServerError(c, "failed to change password")
+		}
+		return
+	}
+
+	response.Success(c, "password changed successfully", nil)
+}
+
+// ForgotPassword godoc
+// @Summary      Forgot password
+// @Description  Email a password reset link. The response is identical whether or not the email exists.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.ForgotPasswordRequest  true  "Email"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Router       /password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(req); err != nil {
+		response.InternalServerError(c, "something went wrong")
+		return
+	}
+
+	response.Success(c, "if the email is registered, a reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with a reset token. All sessions of the user are signed out.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.ResetPasswordRequest  true  "Reset token and new password"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Failure      401   {object}  response.envelope
+// @Router       /password/reset [post]
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(req); err != nil {
+		if errors.Is(err, apperror.ErrInvalidToken) {
+			response.Unauthorized(c, "invalid or expired reset token")
+			return
+		}
+		response.InternalServerError(c, "failed to reset password")
+		return
+	}
+
+	response.Success(c, "password reset successfully", nil)
+}
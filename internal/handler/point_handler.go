@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PointHandler struct {
+	pointService service.PointService
+}
+
+func NewPointHandler(pointService service.PointService) *PointHandler {
+	return &PointHandler{pointService}
+}
+
+// GetPointBalance godoc
+// @Summary      Get my point balance
+// @Description  Returns the caller's point balance, the points expiring in the coming weeks grouped by day, and their latest point movements. Points expire 12 months after they are earned by default
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=dto.PointBalanceResponse}
+// @Failure      401  {object}  response.envelope
+// @Router       /me/points [get]
+func (h *PointHandler) GetBalance(c *gin.Context) {
+	balance, err := h.pointService.GetBalance(middleware.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "user not found")
+			return
+		}
+		response.InternalServerError(c, "failed to fetch point balance")
+		return
+	}
+	response.Success(c, "point balance retrieved successfully", balance)
+}
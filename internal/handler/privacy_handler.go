@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService}
+}
+
+// ExportMyData godoc
+// @Summary      Export my data
+// @Description  Downloads a JSON archive of everything held about the caller: profile, points ledger, redemptions, ratings, review votes, sessions and security events
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.PersonalDataExport
+// @Failure      401  {object}  response.envelope
+// @Failure      403  {object}  response.envelope
+// @Router       /me/data-export [get]
+func (h *PrivacyHandler) ExportOwn(c *gin.Context) {
+	archive, err := h.privacyService.ExportOwn(middleware.GetUserID(c), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "user not found")
+			return
+		}
+		response.InternalServerError(c, "failed to export data")
+		return
+	}
+
+	sendArchive(c, archive)
+}
+
+// ExportUserData godoc
+// @Summary      Export a user's data
+// @Description  Downloads the personal data archive of a user, deactivated users included, to answer a subject access request. Audited (requires users:read)
+// @Tags         Users
+// @Produce      json
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.PersonalDataExport
+// @Failure      400  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /users/{id}/data-export [get]
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	archive, err := h.privacyService.Export(middleware.GetOrganisationID(c), middleware.GetActor(c), id, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "user not found")
+			return
+		}
+		response.InternalServerError(c, "failed to export data")
+		return
+	}
+
+	sendArchive(c, archive)
+}
+
+// EraseUser godoc
+// @Summary      Erase a user's personal data
+// @Description  Anonymises the user's profile, deactivates the account, ends its sessions and deletes its review photos. Redemptions and ratings are kept without personal data so gift ratings and stock history do not change. Cannot be undone (requires users:write)
+// @Tags         Users
+// @Produce      json
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  response.envelope{data=dto.EraseUserResponse}
+// @Failure      400  {object}  response.envelope
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /users/{id}/erase [post]
+func (h *PrivacyHandler) Erase(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	res, err := h.privacyService.Erase(middleware.GetOrganisationID(c), middleware.GetActor(c), id, isPlatformAdmin(c), c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		case errors.Is(err, apperror.ErrForbidden):
+			response.Forbidden(c, "you cannot erase this user")
+		default:
+			response.InternalServerError(c, "failed to erase user")
+		}
+		return
+	}
+
+	response.Success(c, "user erased successfully", res)
+}
+
+func sendArchive(c *gin.Context, archive *dto.PersonalDataExport) {
+	filename := fmt.Sprintf("user-%d-data-%s.json", archive.Profile.ID, archive.GeneratedAt.Format("20060102"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.JSON(http.StatusOK, archive)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	profileService service.ProfileService
+}
+
+func NewProfileHandler(profileService service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService}
+}
+
+// GetProfile godoc
+// @Summary      Get my profile
+// @Description  Returns the caller's account with point balance, redemption counts, loyalty tier and latest tier changes
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=dto.ProfileResponse}
+// @Failure      401  {object}  response.envelope
+// @Router       /me [get]
+func (h *ProfileHandler) Get(c *gin.Context) {
+	user, err := h.profileService.Get(middleware.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "user not found")
+			return
+		}
+		response.InternalServerError(c, "failed to fetch profile")
+		return
+	}
+	response.Success(c, "profile retrieved successfully", user)
+}
+
+// UpdateProfile godoc
+// @Summary      Update my profile
+// @Description  Changes name, avatar, locale or notification preferences. Only the fields sent are changed; notification preferences are merged by event type.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.UpdateProfileRequest  true  "Fields to change"
+// @Success      200   {object}  response.envelope{data=dto.UserResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Unknown notification type"
+// @Router       /me [patch]
+func (h *ProfileHandler) Update(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	user, err := h.profileService.Update(middleware.GetUserID(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrUnknownEventType):
+			response.UnprocessableEntity(c, err.Error(), nil)
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		default:
+			response.InternalServerError(c, "failed to update profile")
+		}
+		return
+	}
+	response.Success(c, "profile updated successfully", user)
+}
+
+// DeactivateAccount godoc
+// @Summary      Deactivate my account
+// @Description  Deactivates the caller's account after confirming the password and signs out every session. Super admins cannot deactivate themselves.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.DeactivateAccountRequest  true  "Current password"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope  "Super admin or impersonating"
+// @Failure      422   {object}  response.envelope  "Password is incorrect"
+// @Router       /me [delete]
+func (h *ProfileHandler) Deactivate(c *gin.Context) {
+	var req dto.DeactivateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.profileService.Deactivate(middleware.GetUserID(c), req, c.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidPassword):
+			response.UnprocessableEntity(c, "current password is incorrect", nil)
+		case errors.Is(err, apperror.ErrForbidden):
+			response.Forbidden(c, "super admins cannot deactivate their own account")
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		default:
+			response.InternalServerError(c, "failed to deactivate account")
+		}
+		return
+	}
+	response.Success(c, "account deactivated", nil)
+}
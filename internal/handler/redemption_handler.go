@@ -21,7 +21,7 @@ func NewRedemptionHandler(redemptionService service.RedemptionService) *Redempti
 
 // RedeemGift godoc
 // @Summary      Redeem a gift
-// @Description  Redeem a gift item. Stock must be available. Supports quantity > 1.
+// @Description  Redeem a gift item. Stock must be available and the total price is debited from the user's point balance. Supports quantity > 1, except while the gift is in a flash sale, which sells one unit per user. An optional coupon_code takes points off the total after campaign and tier pricing.
 // @Tags         Gifts
 // @Accept       json
 // @Produce      json
@@ -31,7 +31,7 @@ func NewRedemptionHandler(redemptionService service.RedemptionService) *Redempti
 // @Success      201   {object}  response.envelope{data=dto.RedemptionResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      404   {object}  response.envelope
-// @Failure      422   {object}  response.envelope  "Insufficient stock"
+// @Failure      422   {object}  response.envelope  "Insufficient stock or points, flash sale sold out or already claimed, coupon not applicable or used up"
 // @Router       /gifts/{id}/redeem [post]
 func (h *RedemptionHandler) Redeem(c *gin.Context) {
 	giftID, err := parseID(c, "id")
@@ -47,13 +47,21 @@ func (h *RedemptionHandler) Redeem(c *gin.Context) {
 
 	userID := middleware.GetUserID(c)
 
-	result, err := h.redemptionService.Redeem(userID, giftID, req)
+	result, err := h.redemptionService.Redeem(middleware.GetOrganisationID(c), userID, giftID, req)
 	if err != nil {
 		switch {
 		case errors.Is(err, apperror.ErrNotFound):
 			response.NotFound(c, "gift not found")
 		case errors.Is(err, apperror.ErrInsufficientStock):
 			response.UnprocessableEntity(c, "insufficient stock", nil)
+		case errors.Is(err, apperror.ErrInsufficientPoints):
+			response.UnprocessableEntity(c, "insufficient points", nil)
+		case errors.Is(err, apperror.ErrSoldOut):
+			response.UnprocessableEntity(c, "flash sale sold out", nil)
+		case errors.Is(err, apperror.ErrFlashSaleLimit), errors.Is(err, apperror.ErrCouponNotValid):
+			response.UnprocessableEntity(c, err.Error(), nil)
+		case errors.Is(err, apperror.ErrCouponUsedUp):
+			response.UnprocessableEntity(c, "coupon usage limit reached", nil)
 		default:
 			response.InternalServerError(c, "failed to redeem gift")
 		}
@@ -90,7 +98,7 @@ func (h *RedemptionHandler) Rate(c *gin.Context) {
 
 	userID := middleware.GetUserID(c)
 
-	result, err := h.redemptionService.Rate(userID, giftID, req)
+	result, err := h.redemptionService.Rate(middleware.GetOrganisationID(c), userID, giftID, req)
 	if err != nil {
 		switch {
 		case errors.Is(err, apperror.ErrNotFound):
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService}
+}
+
+// GetGiftReviews godoc
+// @Summary      Get reviews of a gift
+// @Description  Returns paginated visible reviews of a gift
+// @Tags         Reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Gift ID"
+// @Param        page      query     int     false  "Page number (default: 1)"
+// @Param        limit     query     int     false  "Items per page (default: 10, max: 100)"
+// @Param        sort_by   query     string  false  "Sort field: created_at | score | most_helpful (default: created_at)"
+// @Param        sort_dir  query     string  false  "Sort direction: asc | desc (default: desc)"
+// @Success      200       {object}  response.envelope{data=[]dto.ReviewResponse}
+// @Failure      404       {object}  response.envelope
+// @Router       /gifts/{id}/reviews [get]
+func (h *ReviewHandler) GetByGift(c *gin.Context) {
+	giftID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var query dto.ReviewQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "invalid query parameters", err.Error())
+		return
+	}
+
+	reviews, pagination, err := h.reviewService.GetByGift(middleware.GetOrganisationID(c), giftID, query)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "gift not found")
+			return
+		}
+		response.InternalServerError(c, "failed to fetch reviews")
+		return
+	}
+
+	response.SuccessPaginated(c, "reviews retrieved successfully", reviews, pagination)
+}
+
+// VoteReview godoc
+// @Summary      Vote on a review
+// @Description  Mark a review as helpful or not helpful. Voting again replaces the previous vote.
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                    true  "Review ID"
+// @Param        body  body      dto.ReviewVoteRequest  true  "Vote"
+// @Success      200   {object}  response.envelope{data=dto.ReviewResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Own review"
+// @Router       /reviews/{id}/vote [put]
+func (h *ReviewHandler) Vote(c *gin.Context) {
+	ratingID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.ReviewVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.reviewService.Vote(middleware.GetOrganisationID(c), userID, ratingID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "review not found")
+		case errors.Is(err, apperror.ErrOwnReview):
+			response.UnprocessableEntity(c, "you cannot vote on your own review", nil)
+		default:
+			response.InternalServerError(c, "failed to submit vote")
+		}
+		return
+	}
+
+	response.Success(c, "vote submitted successfully", result)
+}
+
+// UnvoteReview godoc
+// @Summary      Remove vote from a review
+// @Description  Remove the current user's vote from a review
+// @Tags         Reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Review ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /reviews/{id}/vote [delete]
+func (h *ReviewHandler) Unvote(c *gin.Context) {
+	ratingID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := h.reviewService.Unvote(middleware.GetOrganisationID(c), userID, ratingID); err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "vote not found")
+		case errors.Is(err, apperror.ErrOwnReview):
+			response.UnprocessableEntity(c, "you cannot vote on your own review", nil)
+		default:
+			response.InternalServerError(c, "failed to remove vote")
+		}
+		return
+	}
+
+	response.Success(c, "vote removed successfully", nil)
+}
+
+// HideReview godoc
+// @Summary      Hide a review
+// @Description  Hide a review from public listing and remove its votes (requires reviews:moderate)
+// @Tags         Reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Review ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /reviews/{id}/hide [post]
+func (h *ReviewHandler) Hide(c *gin.Context) {
+	ratingID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.reviewService.Hide(middleware.GetOrganisationID(c), ratingID); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "review not found")
+			return
+		}
+		response.InternalServerError(c, "failed to hide review")
+		return
+	}
+
+	response.Success(c, "review hidden successfully", nil)
+}
+
+// ReplyReview godoc
+// @Summary      Reply to a review
+// @Description  Create or edit the public admin reply of a review (requires reviews:moderate). The reviewer is notified.
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                     true  "Review ID"
+// @Param        body  body      dto.ReviewReplyRequest  true  "Reply"
+// @Success      200   {object}  response.envelope{data=dto.ReviewResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Router       /reviews/{id}/reply [put]
+func (h *ReviewHandler) Reply(c *gin.Context) {
+	ratingID, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	adminID := middleware.GetUserID(c)
+
+	result, err := h.reviewService.Reply(middleware.GetOrganisationID(c), adminID, ratingID, req)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "review not found")
+			return
+		}
+		response.InternalServerError(c, "failed to save reply")
+		return
+	}
+
+	response.Success(c, "reply saved successfully", result)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService}
+}
+
+// GetPermissions godoc
+// @Summary      List permissions
+// @Description  Returns every permission that can be granted to a role, and which of them API keys may hold (requires roles:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.PermissionResponse}
+// @Failure      403  {object}  response.envelope
+// @Router       /admin/permissions [get]
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	response.Success(c, "permissions retrieved successfully", h.roleService.GetPermissions())
+}
+
+// GetRoles godoc
+// @Summary      List roles
+// @Description  Returns all roles with their permissions (requires roles:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.RoleResponse}
+// @Failure      403  {object}  response.envelope
+// @Router       /admin/roles [get]
+func (h *RoleHandler) GetAll(c *gin.Context) {
+	roles, err := h.roleService.GetAll()
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch roles")
+		return
+	}
+	response.Success(c, "roles retrieved successfully", roles)
+}
+
+// GetRole godoc
+// @Summary      Get role by ID
+// @Description  Returns a role with its permissions (requires roles:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Role ID"
+// @Success      200  {object}  response.envelope{data=dto.RoleResponse}
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /admin/roles/{id} [get]
+func (h *RoleHandler) GetByID(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	role, err := h.roleService.GetByID(id)
+	if err != nil {
+		h.handleError(c, err, "failed to fetch role")
+		return
+	}
+	response.Success(c, "role retrieved successfully", role)
+}
+
+// CreateRole godoc
+// @Summary      Create role
+// @Description  Creates a role with a set of permissions (requires roles:manage)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateRoleRequest  true  "Role name, description and permissions"
+// @Success      201   {object}  response.envelope{data=dto.RoleResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Invalid name or permission, or name already taken"
+// @Router       /admin/roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	role, err := h.roleService.Create(middleware.GetUserID(c), req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "failed to create role")
+		return
+	}
+	response.Created(c, "role created successfully", role)
+}
+
+// UpdateRole godoc
+// @Summary      Update role
+// @Description  Replaces a role's description and permissions. Takes effect for its users within 30 seconds. The admin role cannot be changed. (requires roles:manage)
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                    true  "Role ID"
+// @Param        body  body      dto.UpdateRoleRequest  true  "Description and permissions"
+// @Success      200   {object}  response.envelope{data=dto.RoleResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Invalid permission or built-in role"
+// @Router       /admin/roles/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	role, err := h.roleService.Update(middleware.GetUserID(c), id, req, c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "failed to update role")
+		return
+	}
+	response.Success(c, "role updated successfully", role)
+}
+
+// DeleteRole godoc
+// @Summary      Delete role
+// @Description  Deletes a custom role that is no longer assigned to any user (requires roles:manage)
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Role ID"
+// @Success      200  {object}  response.envelope
+// @Failure      403  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Failure      422  {object}  response.envelope  "Built-in role or role still assigned"
+// @Router       /admin/roles/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.roleService.Delete(middleware.GetUserID(c), id, c.ClientIP()); err != nil {
+		h.handleError(c, err, "failed to delete role")
+		return
+	}
+	response.Success(c, "role deleted successfully", nil)
+}
+
+func (h *RoleHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "role not found")
+	case errors.Is(err, apperror.ErrDuplicateEntry):
+		response.UnprocessableEntity(c, "role name already taken", nil)
+	case errors.Is(err, apperror.ErrInvalidPermission),
+		errors.Is(err, apperror.ErrInvalidRoleName),
+		errors.Is(err, apperror.ErrSystemRole),
+		errors.Is(err, apperror.ErrRoleInUse):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TierHandler struct {
+	tierService service.TierService
+}
+
+func NewTierHandler(tierService service.TierService) *TierHandler {
+	return &TierHandler{tierService}
+}
+
+// GetTiers godoc
+// @Summary      List loyalty tiers
+// @Description  Returns the organisation's tiers ordered from the lowest threshold up
+// @Tags         Tiers
+// @Produce      json
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200  {object}  response.envelope{data=[]dto.TierResponse}
+// @Router       /tiers [get]
+func (h *TierHandler) GetAll(c *gin.Context) {
+	tiers, err := h.tierService.GetAll(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch tiers")
+		return
+	}
+	response.Success(c, "tiers retrieved successfully", tiers)
+}
+
+// CreateTier godoc
+// @Summary      Create tier
+// @Description  Adds a loyalty tier. Users are placed in it at the next evaluation (requires tiers:manage)
+// @Tags         Tiers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.TierRequest  true  "Tier data"
+// @Success      201   {object}  response.envelope{data=dto.TierResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Name or threshold already used"
+// @Router       /tiers [post]
+func (h *TierHandler) Create(c *gin.Context) {
+	var req dto.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	tier, err := h.tierService.Create(middleware.GetOrganisationID(c), req)
+	if err != nil {
+		h.handleError(c, err, "failed to create tier")
+		return
+	}
+	response.Created(c, "tier created successfully", tier)
+}
+
+// UpdateTier godoc
+// @Summary      Update tier
+// @Description  Replaces a tier. A new discount applies to prices at once; users move between tiers at the next evaluation (requires tiers:manage)
+// @Tags         Tiers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int              true  "Tier ID"
+// @Param        body  body      dto.TierRequest  true  "Tier data"
+// @Success      200   {object}  response.envelope{data=dto.TierResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Name or threshold already used"
+// @Router       /tiers/{id} [put]
+func (h *TierHandler) Update(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req dto.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	tier, err := h.tierService.Update(middleware.GetOrganisationID(c), id, req)
+	if err != nil {
+		h.handleError(c, err, "failed to update tier")
+		return
+	}
+	response.Success(c, "tier updated successfully", tier)
+}
+
+// DeleteTier godoc
+// @Summary      Delete tier
+// @Description  Deletes a tier no gift requires. Its users drop to no tier until the next evaluation (requires tiers:manage)
+// @Tags         Tiers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Tier ID"
+// @Success      200  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Failure      422  {object}  response.envelope  "Tier still required by gifts"
+// @Router       /tiers/{id} [delete]
+func (h *TierHandler) Delete(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.tierService.Delete(middleware.GetOrganisationID(c), id); err != nil {
+		h.handleError(c, err, "failed to delete tier")
+		return
+	}
+	response.Success(c, "tier deleted successfully", nil)
+}
+
+// EvaluateTiers godoc
+// @Summary      Re-evaluate user tiers
+// @Description  Places every active user in the tier their qualifying points reach now instead of waiting for the scheduled run (requires tiers:manage)
+// @Tags         Tiers
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=dto.TierEvaluationResponse}
+// @Router       /tiers/evaluate [post]
+func (h *TierHandler) Evaluate(c *gin.Context) {
+	res, err := h.tierService.Evaluate(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to evaluate tiers")
+		return
+	}
+	response.Success(c, "tiers evaluated successfully", res)
+}
+
+func (h *TierHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "tier not found")
+	case errors.Is(err, apperror.ErrDuplicateEntry):
+		response.UnprocessableEntity(c, "a tier with this name or min_points already exists", nil)
+	case errors.Is(err, apperror.ErrTierInUse):
+		response.UnprocessableEntity(c, err.Error(), nil)
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
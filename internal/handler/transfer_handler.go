@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	transferService service.TransferService
+}
+
+func NewTransferHandler(transferService service.TransferService) *TransferHandler {
+	return &TransferHandler{transferService}
+}
+
+// SendPoints godoc
+// @Summary      Send points to a colleague
+// @Description  Moves points from the caller to a colleague in the same organisation. The points keep their original expiry. Subject to the organisation's transfer policy and the caller's tier
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.TransferRequest  true  "Transfer data"
+// @Success      201   {object}  response.envelope{data=dto.TransferResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope  "Transfers disabled for the organisation or tier"
+// @Failure      422   {object}  response.envelope  "Invalid recipient, insufficient points, minimum balance or daily limit"
+// @Router       /me/transfers [post]
+func (h *TransferHandler) Send(c *gin.Context) {
+	var req dto.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	transfer, err := h.transferService.Send(middleware.GetUserID(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrTransfersDisabled):
+			response.Forbidden(c, err.Error())
+		case errors.Is(err, apperror.ErrInvalidRecipient),
+			errors.Is(err, apperror.ErrTransferLimit),
+			errors.Is(err, apperror.ErrBelowMinBalance):
+			response.UnprocessableEntity(c, err.Error(), nil)
+		case errors.Is(err, apperror.ErrInsufficientPoints):
+			response.UnprocessableEntity(c, "insufficient points", nil)
+		case errors.Is(err, apperror.ErrNotFound):
+			response.NotFound(c, "user not found")
+		default:
+			response.InternalServerError(c, "failed to transfer points")
+		}
+		return
+	}
+	response.Created(c, "points transferred successfully", transfer)
+}
+
+// GetTransfers godoc
+// @Summary      List my transfers
+// @Description  Returns the latest transfers the caller sent or received, newest first
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=[]dto.TransferResponse}
+// @Failure      401  {object}  response.envelope
+// @Router       /me/transfers [get]
+func (h *TransferHandler) GetHistory(c *gin.Context) {
+	transfers, err := h.transferService.History(middleware.GetUserID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch transfers")
+		return
+	}
+	response.Success(c, "transfers retrieved successfully", transfers)
+}
+
+// GetTransferPolicy godoc
+// @Summary      Get transfer policy
+// @Description  Returns whether users may send each other points, the daily limit per sender and the balance a sender must keep (requires points:manage)
+// @Tags         Transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200  {object}  response.envelope{data=dto.TransferPolicyResponse}
+// @Router       /transfer-policy [get]
+func (h *TransferHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.transferService.GetPolicy(middleware.GetOrganisationID(c))
+	if err != nil {
+		response.InternalServerError(c, "failed to fetch transfer policy")
+		return
+	}
+	response.Success(c, "transfer policy retrieved successfully", policy)
+}
+
+// UpdateTransferPolicy godoc
+// @Summary      Update transfer policy
+// @Description  Replaces the organisation's transfer policy. A daily limit of 0 is unlimited. Tiers can still switch transfers off for their members (requires points:manage)
+// @Tags         Transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.TransferPolicyRequest  true  "Policy"
+// @Success      200   {object}  response.envelope{data=dto.TransferPolicyResponse}
+// @Failure      400   {object}  response.envelope
+// @Router       /transfer-policy [put]
+func (h *TransferHandler) UpdatePolicy(c *gin.Context) {
+	var req dto.TransferPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	policy, err := h.transferService.UpdatePolicy(middleware.GetOrganisationID(c), middleware.GetUserID(c), req, c.ClientIP())
+	if err != nil {
+		response.InternalServerError(c, "failed to update transfer policy")
+		return
+	}
+	response.Success(c, "transfer policy updated successfully", policy)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService}
+}
+
+// GetTwoFactorStatus godoc
+// @Summary      Two-factor authentication status
+// @Description  Whether 2FA is enabled or required for the current user and how many recovery codes are left
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=dto.TwoFactorStatusResponse}
+// @Router       /me/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.GetStatus(middleware.GetUserID(c))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "user not found")
+			return
+		}
+		response.InternalServerError(c, "failed to fetch two-factor status")
+		return
+	}
+	response.Success(c, "two-factor status retrieved successfully", status)
+}
+
+// SetupTwoFactor godoc
+// @Summary      Start two-factor enrolment
+// @Description  Generates a TOTP secret and otpauth URI to render as a QR code. 2FA is enabled once a code is confirmed.
+// @Tags         Me
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.envelope{data=dto.TwoFactorSetupResponse}
+// @Failure      422  {object}  response.envelope  "Two-factor authentication already enabled"
+// @Router       /me/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	result, err := h.twoFactorService.BeginSetup(middleware.GetUserID(c))
+	if err != nil {
+		h.handleError(c, err, "failed to start two-factor setup")
+		return
+	}
+	response.Success(c, "scan the QR code with your authenticator app", result)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary      Confirm two-factor enrolment
+// @Description  Enables 2FA with the first authenticator code and returns recovery codes. They are shown only once.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.TwoFactorCodeRequest  true  "Authenticator code"
+// @Success      200   {object}  response.envelope{data=dto.RecoveryCodesResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope
+// @Router       /me/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.twoFactorService.ConfirmSetup(middleware.GetUserID(c), req.Code)
+	if err != nil {
+		h.handleError(c, err, "failed to enable two-factor authentication")
+		return
+	}
+	response.Success(c, "two-factor authentication enabled, store your recovery codes safely", result)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces all recovery codes. Requires a current authenticator or recovery code.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.TwoFactorCodeRequest  true  "Authenticator or recovery code"
+// @Success      200   {object}  response.envelope{data=dto.RecoveryCodesResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      422   {object}  response.envelope
+// @Router       /me/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.twoFactorService.RegenerateRecoveryCodes(middleware.GetUserID(c), req.Code)
+	if err != nil {
+		h.handleError(c, err, "failed to regenerate recovery codes")
+		return
+	}
+	response.Success(c, "recovery codes regenerated", result)
+}
+
+// DisableTwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Requires the account password and a current authenticator or recovery code. Not allowed when 2FA is mandatory for the role.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.DisableTwoFactorRequest  true  "Password and code"
+// @Success      200   {object}  response.envelope
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope  "Two-factor authentication is mandatory"
+// @Failure      422   {object}  response.envelope
+// @Router       /me/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req dto.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.twoFactorService.Disable(middleware.GetUserID(c), req); err != nil {
+		h.handleError(c, err, "failed to disable two-factor authentication")
+		return
+	}
+	response.Success(c, "two-factor authentication disabled", nil)
+}
+
+func (h *TwoFactorHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, apperror.ErrInvalidOTP):
+		response.UnprocessableEntity(c, "invalid two-factor code", nil)
+	case errors.Is(err, apperror.ErrInvalidPassword):
+		response.UnprocessableEntity(c, "password is incorrect", nil)
+	case errors.Is(err, apperror.ErrTwoFactorEnabled):
+		response.UnprocessableEntity(c, "two-factor authentication already enabled", nil)
+	case errors.Is(err, apperror.ErrTwoFactorNotSetUp):
+		response.UnprocessableEntity(c, "two-factor authentication has not been set up", nil)
+	case errors.Is(err, apperror.ErrTwoFactorRequired):
+		response.Forbidden(c, "two-factor authentication is mandatory for your role")
+	case errors.Is(err, apperror.ErrNotFound):
+		response.NotFound(c, "user not found")
+	default:
+		response.InternalServerError(c, fallback)
+	}
+}
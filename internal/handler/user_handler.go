@@ -2,9 +2,14 @@ package handler
 
 import (
 	"errors"
+	"log"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gift-redemption/internal/service"
@@ -21,28 +26,83 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 
 // GetUsers godoc
 // @Summary      Get all users
-// @Description  Returns list of all users (admin only)
+// @Description  Returns a paginated list of users with search and filters (requires users:read)
 // @Tags         Users
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {object}  response.envelope{data=[]dto.UserResponse}
-// @Failure      403  {object}  response.envelope
+// @Security     APIKeyAuth
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Items per page (default: 10, max: 100)"
+// @Param        sort_by       query     string  false  "Sort field: created_at | name | email (default: created_at)"
+// @Param        sort_dir      query     string  false  "Sort direction: asc | desc (default: desc)"
+// @Param        search        query     string  false  "Search name or email"
+// @Param        role          query     string  false  "Role name"
+// @Param        verified      query     bool    false  "Email verification status"
+// @Param        created_from  query     string  false  "Created on or after (YYYY-MM-DD)"
+// @Param        created_to    query     string  false  "Created on or before (YYYY-MM-DD)"
+// @Param        deleted       query     string  false  "Deleted users: exclude | include | only (default: exclude)"
+// @Success      200           {object}  response.envelope{data=[]dto.UserResponse}
+// @Failure      400           {object}  response.envelope
+// @Failure      403           {object}  response.envelope
 // @Router       /users [get]
 func (h *UserHandler) GetAll(c *gin.Context) {
-	users, err := h.userService.GetAll()
+	var query dto.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "invalid query parameters", err.Error())
+		return
+	}
+
+	users, pagination, err := h.userService.GetAll(middleware.GetOrganisationID(c), query)
 	if err != nil {
 		response.InternalServerError(c, "failed to fetch users")
 		return
 	}
-	response.Success(c, "users retrieved successfully", users)
+	response.SuccessPaginated(c, "users retrieved successfully", users, pagination)
+}
+
+// ExportUsers godoc
+// @Summary      Export users as CSV
+// @Description  Streams every user matching the filters of GET /users as CSV, ignoring pagination. Each export is audited. (requires users:read)
+// @Tags         Users
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Param        search        query     string  false  "Search name or email"
+// @Param        role          query     string  false  "Role name"
+// @Param        verified      query     bool    false  "Email verification status"
+// @Param        created_from  query     string  false  "Created on or after (YYYY-MM-DD)"
+// @Param        created_to    query     string  false  "Created on or before (YYYY-MM-DD)"
+// @Param        deleted       query     string  false  "Deleted users: exclude | include | only (default: exclude)"
+// @Success      200           {file}    file
+// @Failure      400           {object}  response.envelope
+// @Failure      403           {object}  response.envelope
+// @Router       /users/export [get]
+func (h *UserHandler) Export(c *gin.Context) {
+	var query dto.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "invalid query parameters", err.Error())
+		return
+	}
+
+	filename := "users-" + time.Now().UTC().Format("20060102") + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// the status is sent with the first rows, so a later failure can only be logged
+	err := h.userService.Export(middleware.GetOrganisationID(c), middleware.GetActor(c), query, c.Writer, c.ClientIP())
+	if err != nil {
+		log.Printf("export users: %v", err)
+	}
 }
 
 // GetUser godoc
 // @Summary      Get user by ID
-// @Description  Returns a single user by ID (admin only)
+// @Description  Returns a single user by ID (requires users:read)
 // @Tags         Users
 // @Produce      json
 // @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  response.envelope{data=dto.UserResponse}
 // @Failure      404  {object}  response.envelope
@@ -53,7 +113,7 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(id)
+	user, err := h.userService.GetByID(middleware.GetOrganisationID(c), id)
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "user not found")
@@ -68,7 +128,7 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 
 // CreateUser godoc
 // @Summary      Create user
-// @Description  Create a new user (admin only)
+// @Description  Create a new user (requires users:write)
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -77,6 +137,7 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 // @Success      201   {object}  response.envelope{data=dto.UserResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      409   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Role does not exist"
 // @Router       /users [post]
 func (h *UserHandler) Create(c *gin.Context) {
 	var req dto.CreateUserRequest
@@ -85,12 +146,20 @@ func (h *UserHandler) Create(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Create(req)
+	user, err := h.userService.Create(middleware.GetOrganisationID(c), req, isPlatformAdmin(c))
 	if err != nil {
 		if errors.Is(err, apperror.ErrDuplicateEntry) {
 			response.UnprocessableEntity(c, "email already registered", nil)
 			return
 		}
+		if errors.Is(err, apperror.ErrInvalidRole) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			response.Forbidden(c, "only super admins can manage platform roles")
+			return
+		}
 		response.InternalServerError(c, "failed to create user")
 		return
 	}
@@ -100,7 +169,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary      Update user
-// @Description  Update user data (admin only)
+// @Description  Update user data (requires users:write)
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -110,6 +179,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 // @Success      200   {object}  response.envelope{data=dto.UserResponse}
 // @Failure      400   {object}  response.envelope
 // @Failure      404   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Role does not exist or email already registered"
 // @Router       /users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
 	id, err := parseID(c, "id")
@@ -123,12 +193,24 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Update(id, req)
+	user, err := h.userService.Update(middleware.GetOrganisationID(c), id, req, isPlatformAdmin(c))
 	if err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "user not found")
 			return
 		}
+		if errors.Is(err, apperror.ErrInvalidRole) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			response.Forbidden(c, "only super admins can manage platform roles")
+			return
+		}
+		if errors.Is(err, apperror.ErrDuplicateEntry) {
+			response.UnprocessableEntity(c, "email already registered", nil)
+			return
+		}
 		response.InternalServerError(c, "failed to update user")
 		return
 	}
@@ -138,12 +220,13 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary      Delete user
-// @Description  Soft delete a user (admin only)
+// @Description  Soft delete a user (requires users:write)
 // @Tags         Users
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  response.envelope
+// @Failure      403  {object}  response.envelope  "Target is a platform admin"
 // @Failure      404  {object}  response.envelope
 // @Router       /users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
@@ -152,11 +235,15 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.Delete(id); err != nil {
+	if err := h.userService.Delete(middleware.GetOrganisationID(c), id, isPlatformAdmin(c)); err != nil {
 		if errors.Is(err, apperror.ErrNotFound) {
 			response.NotFound(c, "user not found")
 			return
 		}
+		if errors.Is(err, apperror.ErrForbidden) {
+			response.Forbidden(c, "only super admins can manage platform roles")
+			return
+		}
 		response.InternalServerError(c, "failed to delete user")
 		return
 	}
@@ -172,3 +259,8 @@ func parseID(c *gin.Context, param string) (uint, error) {
 	}
 	return uint(raw), nil
 }
+
+// isPlatformAdmin reports whether the caller may manage platform-level roles
+func isPlatformAdmin(c *gin.Context) bool {
+	return middleware.HasPermission(c, model.PermOrgsManage)
+}
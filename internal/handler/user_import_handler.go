@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gift-redemption/internal/middleware"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type UserImportHandler struct {
+	importService service.UserImportService
+}
+
+func NewUserImportHandler(importService service.UserImportService) *UserImportHandler {
+	return &UserImportHandler{importService}
+}
+
+// StartUserImport godoc
+// @Summary      Import users from CSV
+// @Description  Upload a CSV with a header row naming the columns name, email and optionally role and points. Rows are processed in the background and each new user is emailed an invite link to choose a password. Poll the returned import for progress and per-row failures (requires users:write)
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  formData  file  true  "CSV file"
+// @Success      202   {object}  response.envelope{data=dto.UserImportResponse}
+// @Failure      400   {object}  response.envelope
+// @Failure      403   {object}  response.envelope
+// @Failure      413   {object}  response.envelope
+// @Failure      422   {object}  response.envelope  "Malformed file, unknown column or too many rows"
+// @Router       /users/imports [post]
+func (h *UserImportHandler) Start(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxImportBytes+1<<20)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.PayloadTooLarge(c, "request body too large")
+			return
+		}
+		response.BadRequest(c, "no file provided", nil)
+		return
+	}
+	if fh.Size > service.MaxImportBytes {
+		response.PayloadTooLarge(c, "file too large")
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		response.BadRequest(c, "failed to read file", nil)
+		return
+	}
+	defer f.Close()
+
+	job, err := h.importService.Start(middleware.GetOrganisationID(c), middleware.GetUserID(c), isPlatformAdmin(c), fh.Filename, f, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidImport) {
+			response.UnprocessableEntity(c, err.Error(), nil)
+			return
+		}
+		response.InternalServerError(c, "failed to start import")
+		return
+	}
+
+	response.Accepted(c, "import started", job)
+}
+
+// GetUserImport godoc
+// @Summary      Get user import
+// @Description  Returns the progress of a user import and the rows that failed (requires users:write)
+// @Tags         Users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Import ID"
+// @Success      200  {object}  response.envelope{data=dto.UserImportResponse}
+// @Failure      400  {object}  response.envelope
+// @Failure      404  {object}  response.envelope
+// @Router       /users/imports/{id} [get]
+func (h *UserImportHandler) Get(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return
+	}
+
+	job, err := h.importService.Get(middleware.GetOrganisationID(c), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.NotFound(c, "import not found")
+			return
+		}
+		response.InternalServerError(c, "failed to fetch import")
+		return
+	}
+
+	response.Success(c, "import retrieved successfully", job)
+}
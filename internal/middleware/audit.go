@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AuditAPIRequest          = "api.request"
+	AuditImpersonatedRequest = "impersonation.request"
+)
+
+type AuditWriter interface {
+	Create(entry *model.AuditLog) error
+}
+
+// AuditAPIKeyRequests records every state-changing request made with an API
+// key, including rejected ones. Register it before the routes so it observes
+// the principal set by Authenticate and the final response status.
+func AuditAPIKeyRequests(audit AuditWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		actor := GetActor(c)
+		if actor.Type != model.ActorAPIKey {
+			return
+		}
+
+		entry := &model.AuditLog{
+			ActorType:  actor.Type,
+			ActorID:    actor.ActorIDPtr(),
+			Action:     AuditAPIRequest,
+			TargetType: "route",
+			TargetID:   c.FullPath(),
+			IP:         c.ClientIP(),
+			Metadata: model.JSONMap{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"status": c.Writer.Status(),
+			},
+		}
+		if err := audit.Create(entry); err != nil {
+			log.Printf("audit %s: %v", entry.Action, err)
+		}
+	}
+}
+
+// AuditImpersonatedRequests records every request made with an impersonation
+// token, reads included, under the admin's ID. Register it like
+// AuditAPIKeyRequests.
+func AuditImpersonatedRequests(audit AuditWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		adminID := GetImpersonatorID(c)
+		if adminID == 0 {
+			return
+		}
+
+		entry := &model.AuditLog{
+			ActorType:  model.ActorUser,
+			ActorID:    &adminID,
+			Action:     AuditImpersonatedRequest,
+			TargetType: "user",
+			TargetID:   strconv.FormatUint(uint64(GetUserID(c)), 10),
+			IP:         c.ClientIP(),
+			Metadata: model.JSONMap{
+				"method":     c.Request.Method,
+				"path":       c.Request.URL.Path,
+				"route":      c.FullPath(),
+				"status":     c.Writer.Status(),
+				"session_id": GetSessionID(c),
+			},
+		}
+		if err := audit.Create(entry); err != nil {
+			log.Printf("audit %s: %v", entry.Action, err)
+		}
+	}
+}
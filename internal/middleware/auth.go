@@ -1,22 +1,65 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/authtoken"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
 	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
-	ContextUserID = "user_id"
-	ContextRole   = "role"
+	ContextUserID         = "user_id"
+	ContextRole           = "role"
+	ContextSessionID      = "session_id"
+	ContextTokenID        = "token_id"
+	ContextActorType      = "actor_type"
+	ContextAPIKeyID       = "api_key_id"
+	ContextPerms          = "permissions"
+	ContextOrgID          = "organisation_id"
+	ContextImpersonatorID = "impersonator_id"
+
+	APIKeyHeader = "X-API-Key"
 )
 
-func Authenticate(cfg *config.Config) gin.HandlerFunc {
+// SessionValidator checks that the token owner still exists, the session was
+// not revoked and the token is not older than the user's last credential change
+type SessionValidator interface {
+	ValidateSession(userID uint, sessionID string, issuedAt time.Time) error
+}
+
+// APIKeyAuthenticator resolves a raw service API key
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(rawKey, ip string) (*model.APIKey, error)
+}
+
+// PermissionResolver maps a role name to the permissions it currently grants
+type PermissionResolver interface {
+	PermissionsForRole(role string) ([]string, error)
+}
+
+// Authenticate accepts either a user access token (Authorization: Bearer) or a
+// service API key (X-API-Key or Authorization: ApiKey) and stores the caller's
+// permissions: those of the user's role, or the key's scopes.
+func Authenticate(keys *jwtkeys.KeySet, cfg config.JWTConfig, sessions SessionValidator, apiKeys APIKeyAuthenticator, perms PermissionResolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" && strings.HasPrefix(authHeader, "ApiKey ") {
+			rawKey = strings.TrimPrefix(authHeader, "ApiKey ")
+		}
+		if rawKey != "" {
+			authenticateAPIKey(c, apiKeys, rawKey)
+			return
+		}
+
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			response.Unauthorized(c, "missing or invalid authorization header")
 			c.Abort()
@@ -25,29 +68,73 @@ func Authenticate(cfg *config.Config) gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(cfg.JWT.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := authtoken.Parse(keys, cfg, tokenStr)
+		if err != nil {
 			response.Unauthorized(c, "invalid or expired token")
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			response.Unauthorized(c, "invalid token claims")
+		// Parse already rejected tokens with a malformed subject
+		userID, _ := claims.UserID()
+
+		err = sessions.ValidateSession(userID, claims.SessionID, claims.IssuedAtTime())
+		// an impersonation token lives on the admin's session, so it ends
+		// when the admin logs out or their own credentials change
+		impersonatorID := claims.ImpersonatorID()
+		if err == nil && impersonatorID != 0 {
+			err = sessions.ValidateSession(impersonatorID, claims.SessionID, claims.IssuedAtTime())
+		}
+		if err != nil {
+			if errors.Is(err, apperror.ErrInvalidToken) {
+				response.Unauthorized(c, "session revoked or token no longer valid")
+			} else {
+				response.InternalServerError(c, "failed to validate session")
+			}
+			c.Abort()
+			return
+		}
+
+		// resolved per request so role edits apply without re-login
+		granted, err := perms.PermissionsForRole(claims.Role)
+		if err != nil {
+			response.InternalServerError(c, "failed to resolve permissions")
 			c.Abort()
 			return
 		}
 
 		// store parsed claims into context for downstream handlers
-		c.Set(ContextUserID, uint(claims["user_id"].(float64)))
-		c.Set(ContextRole, claims["role"].(string))
+		c.Set(ContextUserID, userID)
+		c.Set(ContextRole, claims.Role)
+		c.Set(ContextSessionID, claims.SessionID)
+		c.Set(ContextTokenID, claims.ID)
+		c.Set(ContextActorType, model.ActorUser)
+		c.Set(ContextPerms, granted)
+		c.Set(ContextOrgID, claims.OrganisationID)
+		if impersonatorID != 0 {
+			c.Set(ContextImpersonatorID, impersonatorID)
+		}
 		c.Next()
 	}
 }
+
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, rawKey string) {
+	key, err := apiKeys.AuthenticateAPIKey(rawKey, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidToken) {
+			response.Unauthorized(c, "invalid, expired or revoked api key")
+		} else {
+			response.InternalServerError(c, "failed to validate api key")
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set(ContextActorType, model.ActorAPIKey)
+	c.Set(ContextAPIKeyID, key.ID)
+	c.Set(ContextPerms, []string(key.Scopes))
+	if key.OrganisationID != nil {
+		c.Set(ContextOrgID, *key.OrganisationID)
+	}
+	c.Next()
+}
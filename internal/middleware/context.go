@@ -1,6 +1,9 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/gin-gonic/gin"
+)
 
 func GetUserID(c *gin.Context) uint {
 	val, _ := c.Get(ContextUserID)
@@ -13,3 +16,63 @@ func GetRole(c *gin.Context) string {
 	role, _ := val.(string)
 	return role
 }
+
+func GetSessionID(c *gin.Context) string {
+	val, _ := c.Get(ContextSessionID)
+	sid, _ := val.(string)
+	return sid
+}
+
+// GetTokenID returns the jti of the access token used for the request
+func GetTokenID(c *gin.Context) string {
+	val, _ := c.Get(ContextTokenID)
+	jti, _ := val.(string)
+	return jti
+}
+
+// GetAPIKeyID returns the ID of the API key used for the request, 0 for users
+func GetAPIKeyID(c *gin.Context) uint {
+	val, _ := c.Get(ContextAPIKeyID)
+	id, _ := val.(uint)
+	return id
+}
+
+// GetPermissions returns the permissions of the user's role, or the API key's scopes
+func GetPermissions(c *gin.Context) []string {
+	val, _ := c.Get(ContextPerms)
+	perms, _ := val.([]string)
+	return perms
+}
+
+func HasPermission(c *gin.Context, perm string) bool {
+	for _, p := range GetPermissions(c) {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// GetImpersonatorID returns the admin acting as the user, 0 if the request is
+// not impersonated
+func GetImpersonatorID(c *gin.Context) uint {
+	val, _ := c.Get(ContextImpersonatorID)
+	id, _ := val.(uint)
+	return id
+}
+
+// GetActor returns who is making the request, for audit entries
+func GetActor(c *gin.Context) model.Actor {
+	if val, _ := c.Get(ContextActorType); val == model.ActorAPIKey {
+		return model.Actor{Type: model.ActorAPIKey, ID: GetAPIKeyID(c)}
+	}
+	return model.UserActor(GetUserID(c))
+}
+
+// GetOrganisationID returns the tenant the request acts on. Behind
+// RequireOrganisation it is always set; before it, it is the caller's own.
+func GetOrganisationID(c *gin.Context) uint {
+	val, _ := c.Get(ContextOrgID)
+	id, _ := val.(uint)
+	return id
+}
@@ -6,24 +6,52 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// RequireRole allows access only to users with one of the given roles.
-func RequireRole(roles ...model.UserRole) gin.HandlerFunc {
+// RequirePermission allows access only to callers granted perm, either through
+// their role or, for API keys, as a scope.
+func RequirePermission(perm string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get(ContextRole)
-		if !exists {
-			response.Forbidden(c, "access denied")
+		if !HasPermission(c, perm) {
+			response.Forbidden(c, "missing permission: "+perm)
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
+
+// ForbidImpersonation rejects actions an admin must not take on a user's
+// behalf, such as changing their credentials or spending their points.
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetImpersonatorID(c) != 0 {
+			response.Forbidden(c, "not allowed while impersonating a user")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
 
-		for _, r := range roles {
-			if model.UserRole(role.(string)) == r {
-				c.Next()
-				return
-			}
+// RequireUser rejects API keys on routes that act on the caller's own account.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetActor(c).Type != model.ActorUser {
+			response.Forbidden(c, "this endpoint requires a user account")
+			c.Abort()
+			return
 		}
+		c.Next()
+	}
+}
 
-		response.Forbidden(c, "you do not have permission to access this resource")
-		c.Abort()
+// RequireAPIKey limits a route to other services calling with an API key.
+func RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetActor(c).Type != model.ActorAPIKey {
+			response.Forbidden(c, "this endpoint requires an api key")
+			c.Abort()
+			return
+		}
+		c.Next()
 	}
 }
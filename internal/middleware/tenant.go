@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+const OrganisationHeader = "X-Organisation"
+
+// OrganisationResolver maps an organisation slug to its ID
+type OrganisationResolver interface {
+	ResolveOrganisation(slug string) (uint, error)
+}
+
+// RequireOrganisation settles the tenant a request acts on. Users act on their
+// own organisation and keys bound to one on theirs; super admins and unbound
+// platform keys name it in the X-Organisation header.
+func RequireOrganisation(orgs OrganisationResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID := GetOrganisationID(c)
+
+		if slug := c.GetHeader(OrganisationHeader); slug != "" {
+			requested, err := orgs.ResolveOrganisation(slug)
+			if err != nil {
+				if errors.Is(err, apperror.ErrUnknownOrg) {
+					response.BadRequest(c, "unknown organisation", nil)
+				} else {
+					response.InternalServerError(c, "failed to resolve organisation")
+				}
+				c.Abort()
+				return
+			}
+
+			switch {
+			case requested == orgID:
+			case orgID == 0 || HasPermission(c, model.PermOrgsManage):
+				orgID = requested
+			default:
+				response.Forbidden(c, "you cannot act on another organisation")
+				c.Abort()
+				return
+			}
+		}
+
+		if orgID == 0 {
+			response.BadRequest(c, "the "+OrganisationHeader+" header is required", nil)
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextOrgID, orgID)
+		c.Next()
+	}
+}
+
+// PublicOrganisation resolves the tenant of unauthenticated requests such as
+// sign-up from the X-Organisation header, falling back to the default one.
+func PublicOrganisation(orgs OrganisationResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.GetHeader(OrganisationHeader)
+		if slug == "" {
+			slug = model.DefaultOrganisationSlug
+		}
+
+		orgID, err := orgs.ResolveOrganisation(slug)
+		if err != nil {
+			if errors.Is(err, apperror.ErrUnknownOrg) {
+				response.BadRequest(c, "unknown organisation", nil)
+			} else {
+				response.InternalServerError(c, "failed to resolve organisation")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextOrgID, orgID)
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// StringList maps a JSONB array column
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for StringList")
+	}
+	return json.Unmarshal(b, l)
+}
+
+// APIKey authenticates another service. Its scopes are permissions (see
+// IsValidScope); only a hash of the secret is stored.
+type APIKey struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Prefix      string     `gorm:"not null;uniqueIndex" json:"prefix"`
+	SecretHash  string     `gorm:"not null" json:"-"`
+	Scopes      StringList `gorm:"type:jsonb;not null" json:"scopes"`
+	CreatedByID uint       `gorm:"not null" json:"created_by_id"`
+	// OrganisationID binds the key to one tenant; nil keys name it per request
+	OrganisationID *uint      `json:"organisation_id,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	ActorUser   = "user"
+	ActorAPIKey = "api_key"
+	ActorSystem = "system"
+)
+
+// Actor identifies who performed an action: a user, an API key or the system.
+type Actor struct {
+	Type string
+	ID   uint
+}
+
+func UserActor(id uint) Actor {
+	return Actor{Type: ActorUser, ID: id}
+}
+
+// ActorIDPtr returns the ID for AuditLog.ActorID, nil for the system actor.
+func (a Actor) ActorIDPtr() *uint {
+	if a.Type == ActorSystem {
+		return nil
+	}
+	id := a.ID
+	return &id
+}
+
+// JSONMap maps a JSONB column
+type JSONMap map[string]interface{}
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+func (m *JSONMap) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for JSONMap")
+	}
+	return json.Unmarshal(b, m)
+}
+
+// AuditLog is an append-only record of a security relevant action.
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActorType  string    `gorm:"not null" json:"actor_type"`
+	ActorID    *uint     `json:"actor_id,omitempty"`
+	Action     string    `gorm:"not null" json:"action"`
+	TargetType string    `json:"target_type,omitempty"`
+	TargetID   string    `json:"target_id,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	Metadata   JSONMap   `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Campaign discount types
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// UintList maps a JSONB array of IDs
+type UintList []uint
+
+func (l UintList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]uint(l))
+	return string(b), err
+}
+
+func (l *UintList) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for UintList")
+	}
+	return json.Unmarshal(b, l)
+}
+
+// Campaign discounts the point price of the gifts it targets, by ID or by
+// category, while it runs.
+type Campaign struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	OrganisationID uint       `gorm:"not null;index" json:"organisation_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	DiscountType   string     `gorm:"not null" json:"discount_type"`
+	DiscountValue  int        `gorm:"not null" json:"discount_value"`
+	GiftIDs        UintList   `gorm:"type:jsonb;not null" json:"gift_ids"`
+	Categories     StringList `gorm:"type:jsonb;not null" json:"categories"`
+	// StartsAt is inclusive and EndsAt exclusive
+	StartsAt  time.Time      `gorm:"not null" json:"starts_at"`
+	EndsAt    time.Time      `gorm:"not null" json:"ends_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Targets reports whether the campaign discounts the gift
+func (c *Campaign) Targets(gift *Gift) bool {
+	return slices.Contains(c.GiftIDs, gift.ID) ||
+		(gift.Category != "" && slices.Contains(c.Categories, gift.Category))
+}
+
+// RunningAt reports whether the campaign runs at t
+func (c *Campaign) RunningAt(t time.Time) bool {
+	return !t.Before(c.StartsAt) && t.Before(c.EndsAt)
+}
+
+// Price applies the campaign discount to a point price, never below zero;
+// a nil campaign leaves the price as is
+func (c *Campaign) Price(points int) int {
+	if c == nil {
+		return points
+	}
+	switch c.DiscountType {
+	case DiscountPercent:
+		return points - points*c.DiscountValue/100
+	case DiscountFixed:
+		return max(points-c.DiscountValue, 0)
+	}
+	return points
+}
+
+// BestCampaign returns the campaign running at t that prices the gift
+// lowest, nil when none targets it. Campaigns do not stack.
+func BestCampaign(campaigns []Campaign, gift *Gift, t time.Time) *Campaign {
+	var best *Campaign
+	for i := range campaigns {
+		c := &campaigns[i]
+		if !c.RunningAt(t) || !c.Targets(gift) {
+			continue
+		}
+		if best == nil || c.Price(gift.Point) < best.Price(gift.Point) {
+			best = c
+		}
+	}
+	return best
+}
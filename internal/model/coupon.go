@@ -0,0 +1,82 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DiscountFree is the coupon discount type that makes one unit free
+const DiscountFree = "free"
+
+// Coupon is a code users enter at redemption. It takes a percent or a fixed
+// number of points off the total, or makes one unit of the gift free.
+type Coupon struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	OrganisationID uint   `gorm:"not null;index" json:"organisation_id"`
+	Code           string `gorm:"not null" json:"code"`
+	DiscountType   string `gorm:"not null" json:"discount_type"`
+	DiscountValue  int    `gorm:"not null" json:"discount_value"`
+	// MaxUses and MaxUsesPerUser limit the uses; 0 is no limit
+	MaxUses        int `gorm:"not null" json:"max_uses"`
+	MaxUsesPerUser int `gorm:"not null" json:"max_uses_per_user"`
+	// Uses is only written by the redemption that uses the coupon
+	Uses int `gorm:"not null" json:"uses"`
+	// MinPoints is the least the redemption must cost before the coupon
+	MinPoints int `gorm:"not null" json:"min_points"`
+	// GiftIDs and Categories select the gifts; with neither every gift qualifies
+	GiftIDs    UintList   `gorm:"type:jsonb;not null" json:"gift_ids"`
+	Categories StringList `gorm:"type:jsonb;not null" json:"categories"`
+	// StartsAt is inclusive and EndsAt exclusive; nil leaves that side open
+	StartsAt  *time.Time     `json:"starts_at,omitempty"`
+	EndsAt    *time.Time     `json:"ends_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// NormalizeCouponCode makes codes case-insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidAt reports whether the coupon can be used at t
+func (c *Coupon) ValidAt(t time.Time) bool {
+	return (c.StartsAt == nil || !t.Before(*c.StartsAt)) && (c.EndsAt == nil || t.Before(*c.EndsAt))
+}
+
+// Targets reports whether the coupon applies to the gift
+func (c *Coupon) Targets(gift *Gift) bool {
+	if len(c.GiftIDs) == 0 && len(c.Categories) == 0 {
+		return true
+	}
+	return slices.Contains(c.GiftIDs, gift.ID) ||
+		(gift.Category != "" && slices.Contains(c.Categories, gift.Category))
+}
+
+// Discount returns the points the coupon takes off quantity units priced at
+// unitPrice, never more than their total
+func (c *Coupon) Discount(unitPrice, quantity int) int {
+	total := unitPrice * quantity
+	switch c.DiscountType {
+	case DiscountPercent:
+		return total * c.DiscountValue / 100
+	case DiscountFixed:
+		return min(c.DiscountValue, total)
+	case DiscountFree:
+		return min(unitPrice, total)
+	}
+	return 0
+}
+
+// CouponRedemption records one use of a coupon
+type CouponRedemption struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CouponID     uint      `gorm:"not null" json:"coupon_id"`
+	UserID       uint      `gorm:"not null" json:"user_id"`
+	RedemptionID uint      `gorm:"not null" json:"redemption_id"`
+	Discount     int       `gorm:"not null" json:"discount"`
+	CreatedAt    time.Time `json:"created_at"`
+}
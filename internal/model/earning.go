@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EarningRule turns business events of one type into points. An event earns
+// Points plus PointsPerUnit for every unit of its UnitAttribute, times the
+// Multiplier, within the rule's caps.
+type EarningRule struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	OrganisationID uint   `gorm:"not null;index" json:"organisation_id"`
+	Name           string `gorm:"not null" json:"name"`
+	EventType      string `gorm:"not null" json:"event_type"`
+	// Conditions are attribute values the event must carry, compared as text
+	Conditions    JSONMap `gorm:"type:jsonb;not null" json:"conditions"`
+	Points        int     `gorm:"not null" json:"points"`
+	UnitAttribute string  `gorm:"not null" json:"unit_attribute"`
+	PointsPerUnit float64 `gorm:"not null" json:"points_per_unit"`
+	Multiplier    float64 `gorm:"not null" json:"multiplier"`
+	// MaxPerEvent caps one award; 0 is no cap
+	MaxPerEvent int `gorm:"not null" json:"max_per_event"`
+	// UserCap caps what one user earns from the rule over UserCapDays, or the
+	// rule's lifetime when UserCapDays is 0; 0 is no cap
+	UserCap     int `gorm:"not null" json:"user_cap"`
+	UserCapDays int `gorm:"not null" json:"user_cap_days"`
+	// StartsAt and EndsAt bound when an event must have occurred; nil is open
+	StartsAt  *time.Time     `json:"starts_at,omitempty"`
+	EndsAt    *time.Time     `json:"ends_at,omitempty"`
+	Active    bool           `gorm:"not null" json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Matches reports whether the rule applies to the event
+func (r *EarningRule) Matches(event *PointEvent) bool {
+	if !r.Active || r.EventType != event.Type {
+		return false
+	}
+	if r.StartsAt != nil && event.OccurredAt.Before(*r.StartsAt) {
+		return false
+	}
+	if r.EndsAt != nil && !event.OccurredAt.Before(*r.EndsAt) {
+		return false
+	}
+	for name, want := range r.Conditions {
+		got, ok := event.Attributes[name]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Award returns the points the event earns before the user cap, rounded
+// down. A missing or non-numeric unit attribute counts as no units.
+func (r *EarningRule) Award(event *PointEvent) int {
+	points := float64(r.Points)
+	if r.UnitAttribute != "" {
+		if units, ok := event.Attributes[r.UnitAttribute].(float64); ok && units > 0 {
+			points += units * r.PointsPerUnit
+		}
+	}
+	award := int(math.Floor(points * r.Multiplier))
+	if r.MaxPerEvent > 0 {
+		award = min(award, r.MaxPerEvent)
+	}
+	return max(award, 0)
+}
+
+// UserCapSince returns when the user cap window that ends at now began; the
+// zero time counts every award of the rule
+func (r *EarningRule) UserCapSince(now time.Time) time.Time {
+	if r.UserCapDays == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -r.UserCapDays)
+}
+
+// PointEvent is a business event received from another system. ExternalID
+// is unique per organisation, so a redelivered event is not awarded twice.
+type PointEvent struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null" json:"organisation_id"`
+	ExternalID     string    `gorm:"not null" json:"external_id"`
+	Type           string    `gorm:"not null" json:"type"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Attributes     JSONMap   `gorm:"type:jsonb;not null" json:"attributes"`
+	OccurredAt     time.Time `gorm:"not null" json:"occurred_at"`
+	PointsAwarded  int       `gorm:"not null" json:"points_awarded"`
+	// APIKeyID is the key that sent the event
+	APIKeyID  *uint        `json:"api_key_id,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	Awards    []PointAward `gorm:"foreignKey:EventID" json:"awards,omitempty"`
+}
+
+// PointAward is what one rule awarded for an event, credited as LotID
+type PointAward struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventID   uint      `gorm:"not null" json:"event_id"`
+	RuleID    uint      `gorm:"not null" json:"rule_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	Points    int       `gorm:"not null" json:"points"`
+	LotID     uint      `gorm:"not null" json:"lot_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FlashSale sells a limited quantity of a gift, one unit per user, from
+// StartsAt. The quantity is reserved out of the gift's stock when the sale is
+// created and every unit is a FlashSaleUnit row.
+type FlashSale struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null;index" json:"organisation_id"`
+	GiftID         uint      `gorm:"not null" json:"gift_id"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	StartsAt       time.Time `gorm:"not null" json:"starts_at"`
+	// EndsAt is exclusive; nil runs the sale until it sells out
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+	// Claimed counts the units taken so far; it is only read
+	Claimed   int            `gorm:"->;-:migration" json:"claimed"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// RunningAt reports whether the sale hands out units at t
+func (s *FlashSale) RunningAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && (s.EndsAt == nil || t.Before(*s.EndsAt))
+}
+
+// EndedAt reports whether the sale is over at t, by its end time or because
+// every unit is claimed
+func (s *FlashSale) EndedAt(t time.Time) bool {
+	return s.Claimed >= s.Quantity || (s.EndsAt != nil && !t.Before(*s.EndsAt))
+}
+
+// Overlaps reports whether both sales run at some point in time
+func (s *FlashSale) Overlaps(o *FlashSale) bool {
+	return (o.EndsAt == nil || s.StartsAt.Before(*o.EndsAt)) &&
+		(s.EndsAt == nil || o.StartsAt.Before(*s.EndsAt))
+}
+
+// FlashSaleUnit is one unit on sale, free until UserID is set
+type FlashSaleUnit struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	FlashSaleID  uint       `gorm:"not null" json:"flash_sale_id"`
+	UserID       *uint      `json:"user_id,omitempty"`
+	RedemptionID *uint      `json:"redemption_id,omitempty"`
+	ClaimedAt    *time.Time `json:"claimed_at,omitempty"`
+}
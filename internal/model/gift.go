@@ -1,27 +1,39 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Gift struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Name         string         `gorm:"not null" json:"name"`
-	Description  string         `json:"description"`
-	Point        int            `gorm:"not null;default:0" json:"point"`
-	Stock        int            `gorm:"not null;default:0" json:"stock"`
-	ImageURL     string         `json:"image_url"`
-	IsNew        bool           `gorm:"default:false" json:"is_new"`
-	IsBestSeller bool           `gorm:"default:false" json:"is_best_seller"`
-	AvgRating    float64        `gorm:"default:0" json:"avg_rating"`
-	TotalReviews int            `gorm:"default:0" json:"total_reviews"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrganisationID uint           `gorm:"not null;index" json:"organisation_id"`
+	Name           string         `gorm:"not null" json:"name"`
+	Description    string         `json:"description"`
+	Point          int            `gorm:"not null;default:0" json:"point"`
+	Stock          int            `gorm:"not null;default:0" json:"stock"`
+	ImageURL       string         `json:"image_url"`
+	IsNew          bool           `gorm:"default:false" json:"is_new"`
+	IsBestSeller   bool           `gorm:"default:false" json:"is_best_seller"`
+	AvgRating      float64        `gorm:"default:0" json:"avg_rating"`
+	TotalReviews   int            `gorm:"default:0" json:"total_reviews"`
+	MinTierID      *uint          `json:"min_tier_id,omitempty"` // reserved for this tier and higher ones
+	Category       string         `gorm:"not null;default:''" json:"category"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Images []Image `gorm:"polymorphic:Owner;polymorphicValue:gifts" json:"images,omitempty"`
 }
 
 func (g *Gift) InStock() bool {
 	return g.Stock > 0
 }
+
+// NormalizeCategory trims and lowercases a category so "Electronics " and
+// "electronics" are the same
+func NormalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	ImageOwnerGift   = "gifts"
+	ImageOwnerRating = "ratings"
+)
+
+// Image is an uploaded picture attached to a gift gallery or a review.
+type Image struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	OwnerType    string    `gorm:"not null" json:"owner_type"`
+	OwnerID      uint      `gorm:"not null" json:"owner_id"`
+	StorageKey   string    `gorm:"not null" json:"-"`
+	ThumbnailKey string    `gorm:"not null" json:"-"`
+	URL          string    `gorm:"not null" json:"url"`
+	ThumbnailURL string    `gorm:"not null" json:"thumbnail_url"`
+	ContentType  string    `gorm:"not null" json:"content_type"`
+	SizeBytes    int64     `gorm:"not null" json:"size_bytes"`
+	Width        int       `gorm:"not null" json:"width"`
+	Height       int       `gorm:"not null" json:"height"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	UploadedBy   uint      `gorm:"not null" json:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	ThrottleScopeAccount = "account"
+	ThrottleScopeIP      = "ip"
+)
+
+// LoginThrottle counts consecutive failed logins for an account or a client IP.
+type LoginThrottle struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Scope        string     `gorm:"not null" json:"scope"`
+	Key          string     `gorm:"not null" json:"key"`
+	FailedCount  int        `gorm:"not null;default:0" json:"failed_count"`
+	LastFailedAt time.Time  `gorm:"not null" json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
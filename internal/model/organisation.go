@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Organisation is a tenant. Users, gifts, redemptions and ratings belong to
+// exactly one organisation and are never visible to another.
+type Organisation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Slug      string    `gorm:"uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultOrganisationSlug receives self-registrations that name no organisation
+const DefaultOrganisationSlug = "default"
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Ledger entry types
+const (
+	LedgerOpeningBalance = "opening_balance"
+	LedgerImport         = "import"
+	LedgerRedemption     = "redemption"
+	LedgerExpiry         = "expiry"
+	LedgerTransferOut    = "transfer_out"
+	LedgerTransferIn     = "transfer_in"
+	LedgerEarning        = "earning"
+)
+
+// PointLot is a batch of earned points. Debits consume the lots that expire
+// first; what is left of a lot when it expires is written off.
+type PointLot struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null;index" json:"organisation_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Source         string    `gorm:"not null" json:"source"` // ledger type of the credit
+	Points         int       `gorm:"not null" json:"points"`
+	Remaining      int       `gorm:"not null" json:"remaining"`
+	EarnedAt       time.Time `json:"earned_at"`
+	// ExpiresAt is nil for points that never expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewPointLot builds a lot of points earned at earnedAt that expire after
+// validMonths; validMonths <= 0 never expires.
+func NewPointLot(orgID, userID uint, source string, points int, earnedAt time.Time, validMonths int) *PointLot {
+	lot := &PointLot{
+		OrganisationID: orgID,
+		UserID:         userID,
+		Source:         source,
+		Points:         points,
+		Remaining:      points,
+		EarnedAt:       earnedAt,
+	}
+	if validMonths > 0 {
+		expiresAt := earnedAt.AddDate(0, validMonths, 0)
+		lot.ExpiresAt = &expiresAt
+	}
+	return lot
+}
+
+// PointLedgerEntry is one movement of a user's balance. Credits are positive
+// and debits negative, so a user's entries add up to their balance.
+type PointLedgerEntry struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null" json:"organisation_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Type           string    `gorm:"not null" json:"type"`
+	Points         int       `gorm:"not null" json:"points"`
+	LotID          *uint     `json:"lot_id,omitempty"`
+	RedemptionID   *uint     `json:"redemption_id,omitempty"`
+	TransferID     *uint     `json:"transfer_id,omitempty"`
+	EventID        *uint     `json:"event_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -3,28 +3,49 @@ package model
 import "time"
 
 type Redemption struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	UserID     uint      `gorm:"not null;index" json:"user_id"`
-	GiftID     uint      `gorm:"not null;index" json:"gift_id"`
-	Quantity   int       `gorm:"not null;default:1" json:"quantity"`
-	TotalPoint int       `gorm:"not null" json:"total_point"`
-	RedeemedAt time.Time `json:"redeemed_at"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null;index" json:"organisation_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	GiftID         uint      `gorm:"not null;index" json:"gift_id"`
+	Quantity       int       `gorm:"not null;default:1" json:"quantity"`
+	TotalPoint     int       `gorm:"not null" json:"total_point"`
+	RedeemedAt     time.Time `json:"redeemed_at"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// CampaignID is the campaign that priced the redemption and
+	// CampaignDiscount the points it took off TotalPoint
+	CampaignID       *uint `json:"campaign_id,omitempty"`
+	CampaignDiscount int   `gorm:"not null;default:0" json:"campaign_discount"`
+
+	// CouponID is the coupon the user entered and CouponDiscount the points
+	// it took off TotalPoint after the campaign and tier pricing
+	CouponID       *uint `json:"coupon_id,omitempty"`
+	CouponDiscount int   `gorm:"not null;default:0" json:"coupon_discount"`
 
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Gift *Gift `gorm:"foreignKey:GiftID" json:"gift,omitempty"`
 }
 
 type Rating struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	UserID       uint      `gorm:"not null;index" json:"user_id"`
-	GiftID       uint      `gorm:"not null;index" json:"gift_id"`
-	RedemptionID uint      `gorm:"not null" json:"redemption_id"`
-	Score        float64   `gorm:"not null" json:"score"` // 1–5
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-
-	User       *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Gift       *Gift       `gorm:"foreignKey:GiftID" json:"gift,omitempty"`
-	Redemption *Redemption `gorm:"foreignKey:RedemptionID" json:"redemption,omitempty"`
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	OrganisationID  uint       `gorm:"not null;index" json:"organisation_id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	GiftID          uint       `gorm:"not null;index" json:"gift_id"`
+	RedemptionID    uint       `gorm:"not null" json:"redemption_id"`
+	Score           float64    `gorm:"not null" json:"score"` // 1–5
+	HelpfulCount    int        `gorm:"not null;default:0" json:"helpful_count"`
+	NotHelpfulCount int        `gorm:"not null;default:0" json:"not_helpful_count"`
+	HiddenAt        *time.Time `json:"hidden_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	User       *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Gift       *Gift        `gorm:"foreignKey:GiftID" json:"gift,omitempty"`
+	Redemption *Redemption  `gorm:"foreignKey:RedemptionID" json:"redemption,omitempty"`
+	Reply      *ReviewReply `gorm:"foreignKey:RatingID" json:"reply,omitempty"`
+	Photos     []Image      `gorm:"polymorphic:Owner;polymorphicValue:ratings" json:"photos,omitempty"`
+}
+
+func (r *Rating) IsHidden() bool {
+	return r.HiddenAt != nil
 }
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// RefreshToken is one link of a rotating refresh token chain. Tokens created
+// from the same login share FamilyID, which also identifies the session.
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID     string     `gorm:"not null;index" json:"family_id"`
+	TokenHash    string     `gorm:"not null;uniqueIndex" json:"-"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return now.After(t.ExpiresAt)
+}
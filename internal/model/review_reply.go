@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ReviewReply is the public response of an admin to a rating. One reply per rating.
+type ReviewReply struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RatingID  uint      `gorm:"not null;uniqueIndex" json:"rating_id"`
+	AdminID   uint      `gorm:"not null" json:"admin_id"`
+	Body      string    `gorm:"not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Admin *User `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ReviewVote marks a rating as helpful or not helpful. One vote per user per rating.
+type ReviewVote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RatingID  uint      `gorm:"not null;index" json:"rating_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	IsHelpful bool      `gorm:"not null" json:"is_helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
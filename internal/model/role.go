@@ -0,0 +1,158 @@
+package model
+
+import "time"
+
+// Permissions are the unit of authorisation checked by the router. They are
+// defined in code; roles in the database map to a set of them.
+const (
+	PermGiftsRead        = "gifts:read"
+	PermGiftsWrite       = "gifts:write"
+	PermGiftsRedeem      = "gifts:redeem"
+	PermReviewsRead      = "reviews:read"
+	PermReviewsWrite     = "reviews:write"
+	PermReviewsModerate  = "reviews:moderate"
+	PermUsersRead        = "users:read"
+	PermUsersWrite       = "users:write"
+	PermUsersImpersonate = "users:impersonate"
+	PermTiersManage      = "tiers:manage"
+	PermCampaignsManage  = "campaigns:manage"
+	PermCouponsManage    = "coupons:manage"
+	PermPointsManage     = "points:manage"
+	PermEventsIngest     = "events:ingest"
+	PermRolesManage      = "roles:manage"
+	PermSecurityManage   = "security:manage"
+	PermOrgsManage       = "organisations:manage"
+)
+
+type Permission struct {
+	Name        string
+	Description string
+	// APIKeyGrantable permissions may also be given to API keys as scopes
+	APIKeyGrantable bool
+	// Platform permissions act across organisations and belong to super admins
+	Platform bool
+}
+
+var permissions = []Permission{
+	{PermGiftsRead, "List and view gifts", true, false},
+	{PermGiftsWrite, "Create, update and delete gifts and their images", true, false},
+	{PermGiftsRedeem, "Redeem gifts", false, false},
+	{PermReviewsRead, "List reviews of a gift", false, false},
+	{PermReviewsWrite, "Rate redeemed gifts, vote on reviews and upload review photos", false, false},
+	{PermReviewsModerate, "Hide reviews and reply to them", false, false},
+	{PermUsersRead, "List and view users", true, false},
+	{PermUsersWrite, "Create, update and delete users", false, false},
+	{PermUsersImpersonate, "Act as another user to troubleshoot their account", false, false},
+	{PermTiersManage, "Manage loyalty tiers and re-evaluate user tiers", false, false},
+	{PermCampaignsManage, "Manage promotional campaigns and flash sales and view their reports", false, false},
+	{PermCouponsManage, "Manage coupon codes users enter at redemption", false, false},
+	{PermPointsManage, "Configure point transfers and the rules that award points", false, false},
+	{PermEventsIngest, "Send business events that earn users points", true, false},
+	{PermRolesManage, "Manage roles and their permissions", false, true},
+	{PermSecurityManage, "Manage API keys and login lockouts", false, true},
+	{PermOrgsManage, "Create and manage organisations and act inside any of them", false, true},
+}
+
+func Permissions() []Permission {
+	return append([]Permission(nil), permissions...)
+}
+
+// AllPermissionNames returns every permission; the super_admin role always has all of them
+func AllPermissionNames() []string {
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// TenantPermissionNames returns every permission scoped to one organisation;
+// the admin role always has all of them
+func TenantPermissionNames() []string {
+	var names []string
+	for _, p := range permissions {
+		if !p.Platform {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+func IsPlatformPermission(name string) bool {
+	for _, p := range permissions {
+		if p.Name == name {
+			return p.Platform
+		}
+	}
+	return false
+}
+
+func IsValidPermission(name string) bool {
+	for _, p := range permissions {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope reports whether an API key may be granted the permission
+func IsValidScope(name string) bool {
+	for _, p := range permissions {
+		if p.Name == name {
+			return p.APIKeyGrantable
+		}
+	}
+	return false
+}
+
+// Role is a named set of permissions assigned to users by name.
+type Role struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"uniqueIndex;not null" json:"name"`
+	Description string `gorm:"not null" json:"description"`
+	// IsSystem roles (super_admin, admin, user) cannot be deleted
+	IsSystem    bool             `gorm:"not null;default:false" json:"is_system"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID" json:"permissions,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// PermissionNames returns the role's effective permissions
+func (r *Role) PermissionNames() []string {
+	if perms, ok := BuiltInPermissions(UserRole(r.Name)); ok {
+		return perms
+	}
+	names := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		names[i] = p.Permission
+	}
+	return names
+}
+
+// IsPlatform reports whether the role grants any platform permission
+func (r *Role) IsPlatform() bool {
+	for _, p := range r.PermissionNames() {
+		if IsPlatformPermission(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltInPermissions returns the fixed permissions of super_admin and admin,
+// which are defined in code so they can never be edited into a lockout.
+func BuiltInPermissions(role UserRole) ([]string, bool) {
+	switch role {
+	case RoleSuperAdmin:
+		return AllPermissionNames(), true
+	case RoleAdmin:
+		return TenantPermissionNames(), true
+	}
+	return nil, false
+}
+
+type RolePermission struct {
+	RoleID     uint   `gorm:"primaryKey" json:"role_id"`
+	Permission string `gorm:"primaryKey" json:"permission"`
+}
@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// Tier is a loyalty level of an organisation. Users reach the highest tier
+// whose MinPoints their qualifying points cover; tiers rank by MinPoints.
+type Tier struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	OrganisationID uint   `gorm:"not null;index" json:"organisation_id"`
+	Name           string `gorm:"not null" json:"name"`
+	// MinPoints is the qualifying points needed over the rolling window
+	MinPoints int `gorm:"not null;default:0" json:"min_points"`
+	// DiscountPercent is taken off the point price of every gift
+	DiscountPercent int    `gorm:"not null;default:0" json:"discount_percent"`
+	Benefits        string `gorm:"not null;default:''" json:"benefits"`
+	// TransfersEnabled lets members send points when the organisation allows it
+	TransfersEnabled bool      `gorm:"not null" json:"transfers_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Price applies the tier discount to a point price, rounding in the user's favour
+func (t *Tier) Price(points int) int {
+	if t == nil {
+		return points
+	}
+	return points - points*t.DiscountPercent/100
+}
+
+// TierChange records a user moving between tiers. Tier names are copied so
+// the history survives renamed or deleted tiers; nil IDs mean no tier.
+type TierChange struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID   uint      `gorm:"not null" json:"organisation_id"`
+	UserID           uint      `gorm:"not null;index" json:"user_id"`
+	FromTierID       *uint     `json:"from_tier_id,omitempty"`
+	FromTierName     string    `gorm:"not null;default:''" json:"from_tier_name"`
+	ToTierID         *uint     `json:"to_tier_id,omitempty"`
+	ToTierName       string    `gorm:"not null;default:''" json:"to_tier_name"`
+	QualifyingPoints int64     `gorm:"not null" json:"qualifying_points"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TierFor returns the highest tier the points qualify for, nil for none.
+// tiers must be ordered by MinPoints ascending.
+func TierFor(tiers []Tier, points int64) *Tier {
+	var reached *Tier
+	for i := range tiers {
+		if int64(tiers[i].MinPoints) <= points {
+			reached = &tiers[i]
+		}
+	}
+	return reached
+}
+
+// FindTier returns the tier with the given ID, nil when id is nil or unknown
+func FindTier(tiers []Tier, id *uint) *Tier {
+	if id == nil {
+		return nil
+	}
+	for i := range tiers {
+		if tiers[i].ID == *id {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+// TierAllows reports whether a user in tier viewer may see and redeem a gift
+// reserved for minTier and above. Gifts without a minimum tier are open to all.
+func TierAllows(tiers []Tier, viewer, minTier *uint) bool {
+	if minTier == nil {
+		return true
+	}
+	required := FindTier(tiers, minTier)
+	if required == nil {
+		return true
+	}
+	current := FindTier(tiers, viewer)
+	return current != nil && current.MinPoints >= required.MinPoints
+}
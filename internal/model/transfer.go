@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// TransferPolicy is an organisation's rules for sending points to colleagues.
+// Organisations without one do not allow transfers.
+type TransferPolicy struct {
+	OrganisationID uint `gorm:"primaryKey;autoIncrement:false" json:"organisation_id"`
+	Enabled        bool `gorm:"not null;default:false" json:"enabled"`
+	// DailyLimit caps the points one user sends per UTC day; 0 is unlimited
+	DailyLimit int `gorm:"not null;default:0" json:"daily_limit"`
+	// MinBalance is what a sender must keep after a transfer
+	MinBalance int       `gorm:"not null;default:0" json:"min_balance"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PointTransfer is points sent from one user to another
+type PointTransfer struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganisationID uint      `gorm:"not null" json:"organisation_id"`
+	SenderID       uint      `gorm:"not null;index" json:"sender_id"`
+	RecipientID    uint      `gorm:"not null;index" json:"recipient_id"`
+	Points         int       `gorm:"not null" json:"points"`
+	Message        string    `gorm:"not null;default:''" json:"message"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	Sender    *User `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
+	Recipient *User `gorm:"foreignKey:RecipientID" json:"recipient,omitempty"`
+}
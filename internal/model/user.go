@@ -1,28 +1,97 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// UserRole is the name of a Role. admin and user are built in; other roles are
+// created at runtime.
 type UserRole string
 
 const (
-	RoleAdmin UserRole = "admin"
-	RoleUser  UserRole = "user"
+	RoleSuperAdmin UserRole = "super_admin"
+	RoleAdmin      UserRole = "admin"
+	RoleUser       UserRole = "user"
 )
 
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"not null" json:"name"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"not null" json:"-"`
-	Role      UserRole       `gorm:"type:varchar(10);default:'user'" json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	OrganisationID  uint       `gorm:"not null;index" json:"organisation_id"`
+	Name            string     `gorm:"not null" json:"name"`
+	Email           string     `gorm:"uniqueIndex;not null" json:"email"`
+	Password        string     `gorm:"not null" json:"-"`
+	Role            UserRole   `gorm:"type:varchar(50);default:'user'" json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	TOTPSecret      string     `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabledAt   *time.Time `gorm:"column:totp_enabled_at" json:"-"`
+	TOTPLastStep    int64      `gorm:"column:totp_last_step" json:"-"`
+	AvatarURL       string     `gorm:"type:varchar(500);not null;default:''" json:"avatar_url"`
+	Locale          string     `gorm:"type:varchar(35);not null;default:'en'" json:"locale"`
+	// NotificationPreferences opts out of notifier events by type
+	NotificationPreferences NotificationPreferences `gorm:"type:jsonb;not null;default:'{}'" json:"notification_preferences"`
+	// PointBalance is spent by redemptions and never goes below zero
+	PointBalance int `gorm:"not null;default:0" json:"point_balance"`
+	// TierID is the loyalty tier from the last evaluation, nil for none
+	TierID *uint `json:"tier_id,omitempty"`
+	// ErasedAt is set once the user's personal data has been anonymised
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+	// TokensValidAfter is bumped on password and role changes to revoke older access tokens
+	TokensValidAfter *time.Time     `json:"-"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// NotificationPreferences maps a notifier event type to whether the user wants
+// it. Events without an entry are delivered.
+type NotificationPreferences map[string]bool
+
+func (p NotificationPreferences) Allows(eventType string) bool {
+	enabled, ok := p[eventType]
+	return !ok || enabled
+}
+
+func (p NotificationPreferences) Value() (driver.Value, error) {
+	if p == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]bool(p))
+	return string(b), err
+}
+
+func (p *NotificationPreferences) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for NotificationPreferences")
+	}
+	return json.Unmarshal(b, p)
+}
+
+// ErasedUserName replaces the name of an erased user
+const ErasedUserName = "Deleted user"
+
+// ErasedUserEmail is a unique, undeliverable address for an erased user
+func ErasedUserEmail(id uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", id)
+}
+
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
 }
 
 func (u *User) HashPassword(plain string) error {
@@ -37,4 +106,19 @@ func (u *User) HashPassword(plain string) error {
 func (u *User) CheckPassword(plain string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(plain))
 	return err == nil
-}
\ No newline at end of file
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// TokenIssuedBeforeRevocation reports whether an access token issued at
+// issuedAt predates the last password or role change. JWT timestamps have
+// second precision, so the comparison is too.
+func (u *User) TokenIssuedBeforeRevocation(issuedAt time.Time) bool {
+	return u.TokensValidAfter != nil && issuedAt.Before(u.TokensValidAfter.Truncate(time.Second))
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// ImportFailure explains why one CSV row was not imported. Row is the line
+// number in the file, the header being line 1.
+type ImportFailure struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportFailures maps a JSONB column
+type ImportFailures []ImportFailure
+
+func (f ImportFailures) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(f)
+	return string(b), err
+}
+
+func (f *ImportFailures) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for ImportFailures")
+	}
+	return json.Unmarshal(b, f)
+}
+
+// UserImport tracks a bulk CSV user import processed in the background.
+type UserImport struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrganisationID uint           `gorm:"not null;index" json:"organisation_id"`
+	CreatedByID    uint           `gorm:"not null" json:"created_by_id"`
+	Filename       string         `json:"filename"`
+	Status         string         `gorm:"not null" json:"status"`
+	TotalRows      int            `json:"total_rows"`
+	ProcessedRows  int            `json:"processed_rows"`
+	SucceededRows  int            `json:"succeeded_rows"`
+	FailedRows     int            `json:"failed_rows"`
+	Failures       ImportFailures `gorm:"type:jsonb" json:"failures"`
+	StartedAt      *time.Time     `json:"started_at,omitempty"`
+	FinishedAt     *time.Time     `json:"finished_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Finished reports whether the import stopped, successfully or not
+func (i *UserImport) Finished() bool {
+	return i.Status == ImportCompleted || i.Status == ImportFailed
+}
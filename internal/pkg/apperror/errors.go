@@ -1,11 +1,68 @@
 package apperror
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrNotFound          = errors.New("data not found")
-	ErrDuplicateEntry    = errors.New("data already exists")
-	ErrInsufficientStock = errors.New("insufficient stock")
-	ErrAlreadyRated      = errors.New("gift already rated")
-	ErrNotRedeemed       = errors.New("gift has not been redeemed by this user")
+	ErrNotFound           = errors.New("data not found")
+	ErrDuplicateEntry     = errors.New("data already exists")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrInsufficientPoints = errors.New("insufficient points")
+	ErrAlreadyRated       = errors.New("gift already rated")
+	ErrNotRedeemed        = errors.New("gift has not been redeemed by this user")
+	ErrOwnReview          = errors.New("cannot vote on your own review")
+	ErrForbidden          = errors.New("action not allowed")
+	ErrFileTooLarge       = errors.New("file too large")
+	ErrUnsupportedFile    = errors.New("unsupported file type")
+	ErrTooManyFiles       = errors.New("too many files")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenReused        = errors.New("refresh token reused")
+	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrInvalidPassword    = errors.New("current password is incorrect")
+	ErrTooManyAttempts    = errors.New("too many failed attempts")
+	ErrInvalidOTP         = errors.New("invalid two-factor code")
+	ErrTwoFactorEnabled   = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotSetUp  = errors.New("two-factor authentication not set up")
+	ErrTwoFactorRequired  = errors.New("two-factor authentication is required for this account")
+	ErrInvalidScope       = errors.New("invalid scope")
+	ErrInvalidPermission  = errors.New("invalid permission")
+	ErrInvalidRole        = errors.New("role does not exist")
+	ErrInvalidRoleName    = errors.New("role name must start with a letter and contain only lowercase letters, digits and underscores")
+	ErrRoleInUse          = errors.New("role is still assigned to users")
+	ErrSystemRole         = errors.New("operation not allowed on a built-in role")
+	ErrInvalidSlug        = errors.New("slug must contain only lowercase letters, digits and hyphens")
+	ErrUnknownOrg         = errors.New("organisation does not exist")
+	ErrCannotImpersonate  = errors.New("this user cannot be impersonated")
+	ErrUnknownEventType   = errors.New("unknown notification type")
+	ErrInvalidImport      = errors.New("invalid import file")
+	ErrTierInUse          = errors.New("tier is still required by gifts")
+	ErrUnknownTier        = errors.New("tier does not exist")
+	ErrTransfersDisabled  = errors.New("point transfers are not allowed")
+	ErrTransferLimit      = errors.New("daily transfer limit reached")
+	ErrBelowMinBalance    = errors.New("transfer would take the balance below the minimum")
+	ErrInvalidRecipient   = errors.New("invalid recipient")
+	ErrInvalidRule        = errors.New("invalid earning rule")
+	ErrInvalidEvent       = errors.New("invalid event")
+	ErrInvalidCampaign    = errors.New("invalid campaign")
+	ErrInvalidFlashSale   = errors.New("invalid flash sale")
+	ErrSoldOut            = errors.New("flash sale sold out")
+	ErrFlashSaleLimit     = errors.New("flash sales allow one unit per user")
+	ErrInvalidCoupon      = errors.New("invalid coupon")
+	ErrCouponNotValid     = errors.New("coupon cannot be used for this redemption")
+	ErrCouponUsedUp       = errors.New("coupon usage limit reached")
 )
+
+// LockedError is returned while a caller is locked out. It matches ErrTooManyAttempts with errors.Is.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
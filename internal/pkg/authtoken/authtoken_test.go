@@ -0,0 +1,134 @@
+package authtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKeys = jwtkeys.NewHMAC("test-secret")
+	testCfg  = config.JWTConfig{
+		Issuer:    "https://gifts.example.com",
+		Audience:  "gift-redemption-api",
+		AccessTTL: 15 * time.Minute,
+	}
+)
+
+func TestNewAndParse(t *testing.T) {
+	claims, err := New(testCfg, 42, 3, "admin", "family-1", time.Now())
+	require.NoError(t, err)
+	signed, err := testKeys.Sign(claims)
+	require.NoError(t, err)
+
+	parsed, err := Parse(testKeys, testCfg, signed)
+
+	require.NoError(t, err)
+	userID, err := parsed.UserID()
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), userID)
+	assert.Equal(t, "admin", parsed.Role)
+	assert.Equal(t, "family-1", parsed.SessionID)
+	assert.Equal(t, uint(3), parsed.OrganisationID)
+	assert.Len(t, parsed.ID, 22)
+	assert.Equal(t, claims.IssuedAt.Unix(), parsed.IssuedAtTime().Unix())
+}
+
+func TestNew_UniqueTokenIDs(t *testing.T) {
+	a, err := New(testCfg, 1, 1, "user", "s", time.Now())
+	require.NoError(t, err)
+	b, err := New(testCfg, 1, 1, "user", "s", time.Now())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a.ID, b.ID)
+}
+
+func TestImpersonate(t *testing.T) {
+	now := time.Now()
+	claims, err := New(testCfg, 42, 3, "user", "family-1", now)
+	require.NoError(t, err)
+	claims.Impersonate(7, 5*time.Minute)
+	signed, err := testKeys.Sign(claims)
+	require.NoError(t, err)
+
+	parsed, err := Parse(testKeys, testCfg, signed)
+
+	require.NoError(t, err)
+	userID, _ := parsed.UserID()
+	assert.Equal(t, uint(42), userID)
+	assert.Equal(t, uint(7), parsed.ImpersonatorID())
+	assert.Equal(t, now.Add(5*time.Minute).Unix(), parsed.ExpiresAt.Unix())
+
+	// the impersonation TTL never extends a token
+	claims, err = New(testCfg, 42, 3, "user", "family-1", now)
+	require.NoError(t, err)
+	claims.Impersonate(7, time.Hour)
+	assert.Equal(t, now.Add(testCfg.AccessTTL).Unix(), claims.ExpiresAt.Unix())
+}
+
+func TestParse_Rejects(t *testing.T) {
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"jti":  "token-1",
+			"iss":  testCfg.Issuer,
+			"aud":  testCfg.Audience,
+			"sub":  "1",
+			"iat":  now.Unix(),
+			"nbf":  now.Unix(),
+			"exp":  now.Add(time.Minute).Unix(),
+			"role": "user",
+			"sid":  "family-1",
+			"org":  1,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(jwt.MapClaims)
+	}{
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "verify_email" }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }},
+		{"missing expiry", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"not yet valid", func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() }},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = now.Add(time.Hour).Unix() }},
+		{"missing issued at", func(c jwt.MapClaims) { delete(c, "iat") }},
+		{"missing token id", func(c jwt.MapClaims) { delete(c, "jti") }},
+		{"missing session", func(c jwt.MapClaims) { delete(c, "sid") }},
+		{"missing role", func(c jwt.MapClaims) { delete(c, "role") }},
+		{"missing organisation", func(c jwt.MapClaims) { delete(c, "org") }},
+		{"numeric subject", func(c jwt.MapClaims) { c["sub"] = 1 }},
+		{"non-numeric subject", func(c jwt.MapClaims) { c["sub"] = "admin" }},
+		{"zero subject", func(c jwt.MapClaims) { c["sub"] = "0" }},
+		{"legacy user_id claim only", func(c jwt.MapClaims) { delete(c, "sub"); c["user_id"] = "1" }},
+		{"role of wrong type", func(c jwt.MapClaims) { c["role"] = []string{"admin"} }},
+		{"actor without subject", func(c jwt.MapClaims) { c["act"] = map[string]any{} }},
+		{"actor acting as itself", func(c jwt.MapClaims) { c["act"] = map[string]any{"sub": "1"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			signed, err := testKeys.Sign(claims)
+			require.NoError(t, err)
+
+			assert.NotPanics(t, func() {
+				_, err = Parse(testKeys, testCfg, signed)
+			})
+			assert.Error(t, err)
+		})
+	}
+
+	// sanity check: the unmodified claims are accepted
+	signed, err := testKeys.Sign(valid())
+	require.NoError(t, err)
+	_, err = Parse(testKeys, testCfg, signed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,118 @@
+package imageutil
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image dimensions too large")
+)
+
+// maxPixels guards against decompression bombs: small files that decode
+// into huge bitmaps
+const maxPixels = 40_000_000
+
+// extensions of the image types we accept, keyed by sniffed content type
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Info describes an accepted image.
+type Info struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Sniff detects the content type from the file bytes and reads the image
+// dimensions; the client-supplied filename and Content-Type are never trusted.
+func Sniff(data []byte) (Info, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return Info{}, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Info{}, ErrUnsupportedType
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return Info{}, ErrTooManyPixels
+	}
+
+	return Info{ContentType: contentType, Ext: ext, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnail scales an image that already passed Sniff down so that its longest
+// side is at most maxSide pixels. The result is a JPEG, except PNG input which
+// stays PNG to keep transparency.
+func Thumbnail(data []byte, maxSide int) (out []byte, contentType string, err error) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedType
+	}
+
+	dst := resize(src, maxSide)
+
+	var buf bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&buf, dst)
+		contentType = "image/png"
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+		contentType = "image/jpeg"
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
+
+// resize does box-filter downscaling, averaging all source pixels covered by
+// each destination pixel. Images already within bounds are returned unchanged.
+func resize(src image.Image, maxSide int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,62 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestSniff_PNG(t *testing.T) {
+	info, err := Sniff(encodePNG(t, 40, 20))
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.Equal(t, ".png", info.Ext)
+	assert.Equal(t, 40, info.Width)
+	assert.Equal(t, 20, info.Height)
+}
+
+func TestSniff_RejectsNonImage(t *testing.T) {
+	_, err := Sniff([]byte("<html><script>alert(1)</script></html>"))
+
+	assert.Equal(t, ErrUnsupportedType, err)
+}
+
+func TestThumbnail_KeepsAspectRatio(t *testing.T) {
+	out, contentType, err := Thumbnail(encodePNG(t, 400, 200), 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.Width)
+	assert.Equal(t, 50, cfg.Height)
+}
+
+func TestThumbnail_SmallImageUnchanged(t *testing.T) {
+	out, _, err := Thumbnail(encodePNG(t, 30, 60), 100)
+
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, 30, cfg.Width)
+	assert.Equal(t, 60, cfg.Height)
+}
@@ -0,0 +1,331 @@
+// Package jwtkeys holds the keys used to sign and verify access tokens.
+//
+// With RS256 or EdDSA the current private key signs and every token carries
+// its key ID (kid). Older public keys stay listed as verification keys until
+// the tokens they signed have expired, which allows rotation without logging
+// anyone out. Public keys are published at /.well-known/jwks.json so other
+// services can verify tokens without a shared secret. HS256 remains
+// available for single-service deployments.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	minRSABits = 2048
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+type verificationKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+}
+
+// KeySet signs tokens with one key and verifies them against all configured keys.
+type KeySet struct {
+	method     jwt.SigningMethod
+	signingKID string
+	signingKey interface{}
+	verify     map[string]verificationKey
+	secret     []byte // HS256 only
+}
+
+// Load builds the key set described by cfg, reading PEM files from disk.
+func Load(cfg config.JWTConfig) (*KeySet, error) {
+	switch cfg.Algorithm {
+	case "", AlgHS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256")
+		}
+		return NewHMAC(cfg.Secret), nil
+	case AlgRS256, AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	if cfg.SigningKeyFile == "" {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE is required for %s", cfg.Algorithm)
+	}
+	signer, err := readPrivateKey(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var extra []crypto.PublicKey
+	for _, path := range cfg.VerificationKeyFiles {
+		pub, err := readPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, pub)
+	}
+
+	keys, err := New(signer, extra...)
+	if err != nil {
+		return nil, err
+	}
+	if keys.method.Alg() != cfg.Algorithm {
+		return nil, fmt.Errorf("signing key is %s but JWT_ALGORITHM is %s", keys.method.Alg(), cfg.Algorithm)
+	}
+	return keys, nil
+}
+
+// NewHMAC returns a key set that signs and verifies with a shared secret.
+func NewHMAC(secret string) *KeySet {
+	return &KeySet{method: jwt.SigningMethodHS256, secret: []byte(secret)}
+}
+
+// New returns an asymmetric key set. The signer's public key is always
+// accepted; verificationKeys are previous keys kept alive during rotation.
+func New(signer crypto.Signer, verificationKeys ...crypto.PublicKey) (*KeySet, error) {
+	signing, err := newVerificationKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{
+		method:     signing.method,
+		signingKID: signing.kid,
+		signingKey: signer,
+		verify:     map[string]verificationKey{signing.kid: signing},
+	}
+	for _, pub := range verificationKeys {
+		vk, err := newVerificationKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		ks.verify[vk.kid] = vk
+	}
+	return ks, nil
+}
+
+// Algorithm returns the algorithm new tokens are signed with.
+func (k *KeySet) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sign signs claims with the current key and sets the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.secret != nil {
+		return token.SignedString(k.secret)
+	}
+	token.Header["kid"] = k.signingKID
+	return token.SignedString(k.signingKey)
+}
+
+// Parse verifies tokenStr with the key named by its kid and decodes it into claims.
+func (k *KeySet) Parse(tokenStr string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods(k.validMethods()))
+	return jwt.ParseWithClaims(tokenStr, claims, k.keyFunc, opts...)
+}
+
+func (k *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	if k.secret != nil {
+		return k.secret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	vk, ok := k.verify[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	// a token must use the algorithm of the key it names
+	if t.Method.Alg() != vk.method.Alg() {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return vk.key, nil
+}
+
+func (k *KeySet) validMethods() []string {
+	if k.secret != nil {
+		return []string{AlgHS256}
+	}
+	seen := map[string]bool{}
+	var methods []string
+	for _, vk := range k.verify {
+		if alg := vk.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWK is a public key in RFC 7517 JSON form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. It is empty for HS256 since a
+// shared secret must never be published.
+func (k *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, vk := range k.verify {
+		jwk := toJWK(vk.key)
+		jwk.Kid = vk.kid
+		jwk.Use = "sig"
+		jwk.Alg = vk.method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	// signing key first, the rest in a stable order
+	sort.Slice(set.Keys, func(i, j int) bool {
+		if (set.Keys[i].Kid == k.signingKID) != (set.Keys[j].Kid == k.signingKID) {
+			return set.Keys[i].Kid == k.signingKID
+		}
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
+}
+
+func newVerificationKey(pub crypto.PublicKey) (verificationKey, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSABits {
+			return verificationKey{}, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		return verificationKey{kid: thumbprint(key), method: jwt.SigningMethodRS256, key: key}, nil
+	case ed25519.PublicKey:
+		return verificationKey{kid: thumbprint(key), method: jwt.SigningMethodEdDSA, key: key}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+func toJWK(pub crypto.PublicKey) JWK {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   b64(key.N.Bytes()),
+			E:   b64(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: b64(key)}
+	}
+	return JWK{}
+}
+
+// thumbprint derives the key ID as the RFC 7638 JWK thumbprint, so the same
+// key always gets the same kid on every replica.
+func thumbprint(pub crypto.PublicKey) string {
+	jwk := toJWK(pub)
+
+	// members in lexicographic order, as the RFC requires
+	var members map[string]string
+	if jwk.Kty == "RSA" {
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	} else {
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+	canonical, _ := json.Marshal(members) // encoding/json sorts map keys
+
+	sum := sha256.Sum256(canonical)
+	return b64(sum[:])
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported private key type %T", path, key)
+	}
+	return signer, nil
+}
+
+// readPublicKey also accepts a private key file and uses its public half.
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return key, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return key, nil
+	case "RSA PRIVATE KEY", "PRIVATE KEY":
+		signer, err := readPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+}
@@ -0,0 +1,224 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func newEdKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	rsaKeys, err := New(newRSAKey(t))
+	require.NoError(t, err)
+	edKeys, err := New(newEdKey(t))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		keys *KeySet
+		alg  string
+	}{
+		{"HS256", NewHMAC("secret"), AlgHS256},
+		{"RS256", rsaKeys, AlgRS256},
+		{"EdDSA", edKeys, AlgEdDSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := tt.keys.Sign(testClaims())
+			require.NoError(t, err)
+
+			claims := jwt.MapClaims{}
+			token, err := tt.keys.Parse(signed, claims)
+
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, tt.alg, token.Method.Alg())
+			assert.Equal(t, float64(1), claims["user_id"])
+			if tt.alg != AlgHS256 {
+				assert.NotEmpty(t, token.Header["kid"])
+			}
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey := newRSAKey(t)
+	newKey := newEdKey(t)
+
+	oldKeys, err := New(oldKey)
+	require.NoError(t, err)
+	issuedBeforeRotation, err := oldKeys.Sign(testClaims())
+	require.NoError(t, err)
+
+	// after rotation the old public key is kept for verification only
+	rotated, err := New(newKey, oldKey.Public())
+	require.NoError(t, err)
+	assert.Equal(t, AlgEdDSA, rotated.Algorithm())
+
+	_, err = rotated.Parse(issuedBeforeRotation, jwt.MapClaims{})
+	assert.NoError(t, err)
+
+	// once the old key is dropped its tokens stop working
+	retired, err := New(newKey)
+	require.NoError(t, err)
+	_, err = retired.Parse(issuedBeforeRotation, jwt.MapClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_UnknownKid(t *testing.T) {
+	keys, err := New(newRSAKey(t))
+	require.NoError(t, err)
+	other, err := New(newRSAKey(t))
+	require.NoError(t, err)
+
+	signed, err := other.Sign(testClaims())
+	require.NoError(t, err)
+
+	_, err = keys.Parse(signed, jwt.MapClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeySet_RejectsAlgorithmConfusion(t *testing.T) {
+	key := newRSAKey(t)
+	keys, err := New(key)
+	require.NoError(t, err)
+
+	// an HS256 token "signed" with the public key and naming the RSA kid
+	pubDER := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = keys.JWKS().Keys[0].Kid
+	signed, err := forged.SignedString(pubDER)
+	require.NoError(t, err)
+
+	_, err = keys.Parse(signed, jwt.MapClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_HMACRejectsAsymmetricTokens(t *testing.T) {
+	rsaKeys, err := New(newRSAKey(t))
+	require.NoError(t, err)
+	signed, err := rsaKeys.Sign(testClaims())
+	require.NoError(t, err)
+
+	_, err = NewHMAC("secret").Parse(signed, jwt.MapClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	assert.Empty(t, NewHMAC("secret").JWKS().Keys)
+
+	signer := newEdKey(t)
+	previous := newRSAKey(t)
+	keys, err := New(signer, previous.Public())
+	require.NoError(t, err)
+
+	set := keys.JWKS()
+
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "OKP", set.Keys[0].Kty, "signing key is listed first")
+	assert.Equal(t, "Ed25519", set.Keys[0].Crv)
+	assert.Equal(t, AlgEdDSA, set.Keys[0].Alg)
+	assert.Equal(t, "RSA", set.Keys[1].Kty)
+	assert.Equal(t, "AQAB", set.Keys[1].E)
+	assert.Equal(t, "sig", set.Keys[1].Use)
+}
+
+func TestThumbprint_RFC7638Example(t *testing.T) {
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint(key))
+}
+
+func TestNew_RejectsWeakRSA(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = New(weak)
+	assert.Error(t, err)
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestLoad_FromFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	current := newRSAKey(t)
+	currentFile := writePEM(t, dir, "current.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(current))
+
+	previous := newEdKey(t)
+	pubDER, err := x509.MarshalPKIXPublicKey(previous.Public())
+	require.NoError(t, err)
+	previousFile := writePEM(t, dir, "previous.pub.pem", "PUBLIC KEY", pubDER)
+
+	keys, err := Load(config.JWTConfig{
+		Algorithm:            AlgRS256,
+		SigningKeyFile:       currentFile,
+		VerificationKeyFiles: []string{previousFile},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, AlgRS256, keys.Algorithm())
+	assert.Len(t, keys.JWKS().Keys, 2)
+
+	// the configured algorithm must match the key
+	_, err = Load(config.JWTConfig{Algorithm: AlgEdDSA, SigningKeyFile: currentFile})
+	assert.Error(t, err)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.JWTConfig
+	}{
+		{"HS256 without secret", config.JWTConfig{Algorithm: AlgHS256}},
+		{"unknown algorithm", config.JWTConfig{Algorithm: "none", Secret: "x"}},
+		{"RS256 without key file", config.JWTConfig{Algorithm: AlgRS256}},
+		{"missing key file", config.JWTConfig{Algorithm: AlgRS256, SigningKeyFile: "/does/not/exist.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends transactional email. Production deployments plug in an SMTP or
+// provider-backed implementation; the log and file mailers are for local dev.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New builds the Mailer selected by MAIL_DRIVER.
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogMailer(cfg.From), nil
+	case "file":
+		return NewFileMailer(cfg.From, cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+type logMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) Mailer {
+	return &logMailer{from}
+}
+
+func (m *logMailer) Send(msg Message) error {
+	log.Printf("mail from=%s to=%s subject=%q\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer writes every message as an .eml file into dir.
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail dir: %w", err)
+	}
+	return &fileMailer{from, dir}, nil
+}
+
+func (m *fileMailer) Send(msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405.000000000"), sanitize(msg.To))
+
+	content := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		m.from, msg.To, msg.Subject, now.Format(time.RFC1123Z), msg.Body)
+
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o644)
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '@' || r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const (
+	EventReviewReplied  = "review.replied"
+	EventPointsReceived = "points.received"
+)
+
+// EventTypes lists every event users can opt out of
+var EventTypes = []string{EventReviewReplied, EventPointsReceived}
+
+func IsEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a message addressed to a single user, e.g. "an admin replied to your review".
+type Event struct {
+	Type       string                 `json:"type"`
+	UserID     uint                   `json:"user_id"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// Notifier delivers events to users. Implementations may push, email or queue them.
+type Notifier interface {
+	Notify(event Event) error
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that only writes events to the application log.
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(event Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("notify: %s", body)
+	return nil
+}
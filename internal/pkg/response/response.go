@@ -40,6 +40,14 @@ func Created(c *gin.Context, message string, data interface{}) {
 	})
 }
 
+// Accepted acknowledges work that continues in the background
+func Accepted(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusAccepted, envelope{
+		Meta: Meta{Code: http.StatusAccepted, Status: "success", Message: message},
+		Data: data,
+	})
+}
+
 func SuccessPaginated(c *gin.Context, message string, data interface{}, pagination *Pagination) {
 	c.JSON(http.StatusOK, envelope{
 		Meta:       Meta{Code: http.StatusOK, Status: "success", Message: message},
@@ -73,6 +81,18 @@ func NotFound(c *gin.Context, message string) {
 	})
 }
 
+func PayloadTooLarge(c *gin.Context, message string) {
+	c.JSON(http.StatusRequestEntityTooLarge, envelope{
+		Meta: Meta{Code: http.StatusRequestEntityTooLarge, Status: "error", Message: message},
+	})
+}
+
+func TooManyRequests(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, envelope{
+		Meta: Meta{Code: http.StatusTooManyRequests, Status: "error", Message: message},
+	})
+}
+
 func UnprocessableEntity(c *gin.Context, message string, errs interface{}) {
 	c.JSON(http.StatusUnprocessableEntity, envelope{
 		Meta:   Meta{Code: http.StatusUnprocessableEntity, Status: "error", Message: message},
@@ -0,0 +1,40 @@
+// Package scheduler runs periodic background jobs inside the API process.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is one run of a periodic task
+type Job func(ctx context.Context) error
+
+// Every runs job once immediately and then every interval until ctx is
+// cancelled. Failures are logged and retried at the next tick. Every replica
+// runs its own schedule, so jobs must be safe to run concurrently.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	if interval <= 0 {
+		log.Printf("scheduler: %s disabled", name)
+		return
+	}
+
+	run := func() {
+		start := time.Now()
+		if err := job(ctx); err != nil {
+			log.Printf("scheduler: %s failed after %s: %v", name, time.Since(start).Round(time.Millisecond), err)
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, "test", 5*time.Millisecond, func(context.Context) error {
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("keeps going")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+	if got := runs.Load(); got < 3 {
+		t.Fatalf("runs = %d, want at least 3", got)
+	}
+}
+
+func TestEvery_DisabledInterval(t *testing.T) {
+	called := false
+	Every(context.Background(), "test", 0, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Fatal("job ran with a disabled interval")
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStorage stores objects under dir. publicURL is the prefix the router serves dir from.
+func NewLocalStorage(dir, publicURL string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &localStorage{dir: dir, publicURL: strings.TrimRight(publicURL, "/")}, nil
+}
+
+func (s *localStorage) Put(_ context.Context, key string, data []byte, _ string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+func (s *localStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path resolves key inside dir and rejects keys that escape it
+func (s *localStorage) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return path, nil
+}
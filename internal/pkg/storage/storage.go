@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gift-redemption/internal/config"
+)
+
+// Storage persists uploaded objects and returns a URL clients can fetch them from.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the Storage selected by STORAGE_DRIVER.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL)
+	case "s3":
+		return NewS3Storage(cfg.S3, cfg.PublicURL, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") ||
+		r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Storage_PutAndDelete(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := NewS3Storage(config.S3Config{
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		Bucket:    "gifts",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	}, "", server.Client())
+	require.NoError(t, err)
+
+	url, err := s.Put(context.Background(), "gifts/1/a.png", []byte("png-bytes"), "image/png")
+
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/gifts/gifts/1/a.png", url)
+	assert.Equal(t, []byte("png-bytes"), fake.objects["/gifts/gifts/1/a.png"])
+	assert.Equal(t, "image/png", fake.types["/gifts/gifts/1/a.png"])
+
+	require.NoError(t, s.Delete(context.Background(), "gifts/1/a.png"))
+	assert.Empty(t, fake.objects)
+}
+
+func TestS3Storage_PutRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	s, err := NewS3Storage(config.S3Config{Endpoint: server.URL, Bucket: "gifts"}, "", server.Client())
+	require.NoError(t, err)
+
+	_, err = s.Put(context.Background(), "a.png", []byte("x"), "image/png")
+
+	assert.Error(t, err)
+}
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir, "/uploads/")
+	require.NoError(t, err)
+
+	url, err := s.Put(context.Background(), "ratings/3/b.jpg", []byte("jpg"), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/ratings/3/b.jpg", url)
+	data, err := os.ReadFile(filepath.Join(dir, "ratings", "3", "b.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("jpg"), data)
+
+	require.NoError(t, s.Delete(context.Background(), "ratings/3/b.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "ratings", "3", "b.jpg"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalStorage_RejectsTraversal(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "/uploads")
+	require.NoError(t, err)
+
+	_, err = s.Put(context.Background(), "../escape.txt", []byte("x"), "text/plain")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// base32 of the ASCII secret "12345678901234567890" from RFC 6238 appendix B
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// the RFC lists 8 digit codes; the 6 digit code is their suffix
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, code, "time %d", tt.unix)
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, err := Code(rfcSecret, Step(now)-1)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, previous, now, 0)
+	assert.False(t, ok)
+}
+
+func TestValidate_RejectsMalformed(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok := Validate(rfcSecret, code, now, 1)
+		assert.False(t, ok, code)
+	}
+}
+
+func TestGenerateSecret_RoundTrips(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := Code(secret, Step(time.Now()))
+	require.NoError(t, err)
+	_, ok := Validate(secret, code, time.Now(), 1)
+	assert.True(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Gift Redemption", "admin@example.com", rfcSecret)
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Gift%20Redemption:admin@example.com?"))
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "Gift Redemption", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(key *model.APIKey) error
+	FindByID(id uint) (*model.APIKey, error)
+	FindByPrefix(prefix string) (*model.APIKey, error)
+	FindAll() ([]model.APIKey, error)
+	Revoke(id uint) error
+	// TouchLastUsed records usage at most once a minute to keep hot keys from writing on every request
+	TouchLastUsed(id uint, ip string) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db}
+}
+
+func (r *apiKeyRepository) Create(key *model.APIKey) error {
+	err := r.db.Create(key).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *apiKeyRepository) FindByID(id uint) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.First(&key, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &key, err
+}
+
+func (r *apiKeyRepository) FindByPrefix(prefix string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &key, err
+}
+
+func (r *apiKeyRepository) FindAll() ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := r.db.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) Revoke(id uint) error {
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uint, ip string) error {
+	return r.db.Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')", id).
+		Updates(map[string]interface{}{
+			"last_used_at": gorm.Expr("NOW()"),
+			"last_used_ip": ip,
+		}).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"strconv"
+
+	"github.com/gift-redemption/internal/model"
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(entry *model.AuditLog) error
+	// FindByUser lists entries the user performed or that targeted them
+	FindByUser(userID uint) ([]model.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) Create(entry *model.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *auditRepository) FindByUser(userID uint) ([]model.AuditLog, error) {
+	var entries []model.AuditLog
+	err := r.db.
+		Where("(actor_type = ? AND actor_id = ?) OR (target_type = ? AND target_id = ?)",
+			model.ActorUser, userID, "user", strconv.FormatUint(uint64(userID), 10)).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// CampaignStats totals the redemptions a campaign priced
+type CampaignStats struct {
+	Redemptions      int64
+	Quantity         int64
+	Users            int64
+	PointsSpent      int64
+	PointsDiscounted int64
+}
+
+type CampaignRepository interface {
+	// FindAll lists the organisation's campaigns, latest start first
+	FindAll(orgID uint) ([]model.Campaign, error)
+	FindByID(orgID, id uint) (*model.Campaign, error)
+	Create(campaign *model.Campaign) error
+	Update(campaign *model.Campaign) error
+	// Delete soft deletes the campaign so redemptions keep their reference
+	Delete(orgID, id uint) error
+	// FindRunning lists the organisation's campaigns running at t
+	FindRunning(orgID uint, t time.Time) ([]model.Campaign, error)
+	Stats(campaignID uint) (*CampaignStats, error)
+}
+
+type campaignRepository struct {
+	db *gorm.DB
+}
+
+func NewCampaignRepository(db *gorm.DB) CampaignRepository {
+	return &campaignRepository{db}
+}
+
+func (r *campaignRepository) FindAll(orgID uint) ([]model.Campaign, error) {
+	var campaigns []model.Campaign
+	err := r.db.Scopes(inOrganisation("campaigns", orgID)).Order("starts_at DESC, id DESC").Find(&campaigns).Error
+	return campaigns, err
+}
+
+func (r *campaignRepository) FindByID(orgID, id uint) (*model.Campaign, error) {
+	var campaign model.Campaign
+	err := r.db.Scopes(inOrganisation("campaigns", orgID)).First(&campaign, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &campaign, err
+}
+
+func (r *campaignRepository) Create(campaign *model.Campaign) error {
+	return r.db.Create(campaign).Error
+}
+
+// Update saves a campaign loaded through FindByID, so it stays in its organisation
+func (r *campaignRepository) Update(campaign *model.Campaign) error {
+	return r.db.Save(campaign).Error
+}
+
+func (r *campaignRepository) Delete(orgID, id uint) error {
+	result := r.db.Scopes(inOrganisation("campaigns", orgID)).Delete(&model.Campaign{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *campaignRepository) FindRunning(orgID uint, t time.Time) ([]model.Campaign, error) {
+	var campaigns []model.Campaign
+	err := r.db.Scopes(inOrganisation("campaigns", orgID)).
+		Where("starts_at <= ? AND ends_at > ?", t, t).
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+func (r *campaignRepository) Stats(campaignID uint) (*CampaignStats, error) {
+	var stats CampaignStats
+	err := r.db.Model(&model.Redemption{}).
+		Select("COUNT(*) AS redemptions, COALESCE(SUM(quantity), 0) AS quantity, "+
+			"COUNT(DISTINCT user_id) AS users, COALESCE(SUM(total_point), 0) AS points_spent, "+
+			"COALESCE(SUM(campaign_discount), 0) AS points_discounted").
+		Where("campaign_id = ?", campaignID).
+		Scan(&stats).Error
+	return &stats, err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type CouponRepository interface {
+	// FindAll lists the organisation's coupons by code
+	FindAll(orgID uint) ([]model.Coupon, error)
+	FindByID(orgID, id uint) (*model.Coupon, error)
+	// FindByCode looks up a normalised code
+	FindByCode(orgID uint, code string) (*model.Coupon, error)
+	// Create and Update return apperror.ErrDuplicateEntry when the code is taken
+	Create(coupon *model.Coupon) error
+	Update(coupon *model.Coupon) error
+	// Delete soft deletes the coupon so redemptions keep their reference
+	Delete(orgID, id uint) error
+	// Use counts a use of the coupon by the user inside the redemption
+	// transaction. Counting locks the coupon row until the transaction ends,
+	// so concurrent uses are checked against both limits one at a time. It
+	// returns apperror.ErrCouponUsedUp when either limit is reached.
+	Use(tx *gorm.DB, coupon *model.Coupon, userID uint) error
+	// RecordUse links a use counted by Use to its redemption
+	RecordUse(tx *gorm.DB, use *model.CouponRedemption) error
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db}
+}
+
+func (r *couponRepository) FindAll(orgID uint) ([]model.Coupon, error) {
+	var coupons []model.Coupon
+	err := r.db.Scopes(inOrganisation("coupons", orgID)).Order("code ASC").Find(&coupons).Error
+	return coupons, err
+}
+
+func (r *couponRepository) FindByID(orgID, id uint) (*model.Coupon, error) {
+	var coupon model.Coupon
+	err := r.db.Scopes(inOrganisation("coupons", orgID)).First(&coupon, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &coupon, err
+}
+
+func (r *couponRepository) FindByCode(orgID uint, code string) (*model.Coupon, error) {
+	var coupon model.Coupon
+	err := r.db.Scopes(inOrganisation("coupons", orgID)).Where("code = ?", code).First(&coupon).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &coupon, err
+}
+
+func (r *couponRepository) Create(coupon *model.Coupon) error {
+	err := r.db.Create(coupon).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+// Update saves a coupon loaded through FindByID, so it stays in its
+// organisation. Uses is left to Use.
+func (r *couponRepository) Update(coupon *model.Coupon) error {
+	err := r.db.Omit("uses").Save(coupon).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *couponRepository) Delete(orgID, id uint) error {
+	result := r.db.Scopes(inOrganisation("coupons", orgID)).Delete(&model.Coupon{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *couponRepository) Use(tx *gorm.DB, coupon *model.Coupon, userID uint) error {
+	result := tx.Exec(`
+		UPDATE coupons SET uses = uses + 1, updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL AND (max_uses = 0 OR uses < max_uses)
+	`, coupon.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrCouponUsedUp
+	}
+	if coupon.MaxUsesPerUser == 0 {
+		return nil
+	}
+
+	// the row lock taken above holds back other uses until this one commits
+	var used int64
+	err := tx.Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", coupon.ID, userID).
+		Count(&used).Error
+	if err != nil {
+		return err
+	}
+	if used >= int64(coupon.MaxUsesPerUser) {
+		return apperror.ErrCouponUsedUp
+	}
+	return nil
+}
+
+func (r *couponRepository) RecordUse(tx *gorm.DB, use *model.CouponRedemption) error {
+	return tx.Create(use).Error
+}
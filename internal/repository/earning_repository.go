@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type EarningRepository interface {
+	FindAllRules(orgID uint) ([]model.EarningRule, error)
+	FindRuleByID(orgID, id uint) (*model.EarningRule, error)
+	CreateRule(rule *model.EarningRule) error
+	UpdateRule(rule *model.EarningRule) error
+	// DeleteRule soft deletes the rule so past awards keep their reference
+	DeleteRule(orgID, id uint) error
+	// FindActiveRules lists the organisation's active rules for an event type
+	FindActiveRules(orgID uint, eventType string) ([]model.EarningRule, error)
+	// FindEventByExternalID returns a received event with its awards
+	FindEventByExternalID(orgID uint, externalID string) (*model.PointEvent, error)
+	// CreateEvent returns apperror.ErrDuplicateEntry when the external ID was
+	// already received
+	CreateEvent(tx *gorm.DB, event *model.PointEvent) error
+	CreateAward(tx *gorm.DB, award *model.PointAward) error
+	// AwardedSince totals what the rule awarded the user from since on,
+	// inside the transaction holding the user's lock
+	AwardedSince(tx *gorm.DB, ruleID, userID uint, since time.Time) (int, error)
+}
+
+type earningRepository struct {
+	db *gorm.DB
+}
+
+func NewEarningRepository(db *gorm.DB) EarningRepository {
+	return &earningRepository{db}
+}
+
+func (r *earningRepository) FindAllRules(orgID uint) ([]model.EarningRule, error) {
+	var rules []model.EarningRule
+	err := r.db.Scopes(inOrganisation("earning_rules", orgID)).Order("event_type ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *earningRepository) FindRuleByID(orgID, id uint) (*model.EarningRule, error) {
+	var rule model.EarningRule
+	err := r.db.Scopes(inOrganisation("earning_rules", orgID)).First(&rule, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &rule, err
+}
+
+func (r *earningRepository) CreateRule(rule *model.EarningRule) error {
+	return r.db.Create(rule).Error
+}
+
+// UpdateRule saves a rule loaded through FindRuleByID, so it stays in its organisation
+func (r *earningRepository) UpdateRule(rule *model.EarningRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *earningRepository) DeleteRule(orgID, id uint) error {
+	result := r.db.Scopes(inOrganisation("earning_rules", orgID)).Delete(&model.EarningRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *earningRepository) FindActiveRules(orgID uint, eventType string) ([]model.EarningRule, error) {
+	var rules []model.EarningRule
+	err := r.db.Scopes(inOrganisation("earning_rules", orgID)).
+		Where("event_type = ? AND active", eventType).
+		Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *earningRepository) FindEventByExternalID(orgID uint, externalID string) (*model.PointEvent, error) {
+	var event model.PointEvent
+	err := r.db.Preload("Awards", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("organisation_id = ? AND external_id = ?", orgID, externalID).
+		First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &event, err
+}
+
+func (r *earningRepository) CreateEvent(tx *gorm.DB, event *model.PointEvent) error {
+	err := tx.Omit("Awards").Create(event).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *earningRepository) CreateAward(tx *gorm.DB, award *model.PointAward) error {
+	return tx.Create(award).Error
+}
+
+func (r *earningRepository) AwardedSince(tx *gorm.DB, ruleID, userID uint, since time.Time) (int, error) {
+	var total int
+	err := tx.Model(&model.PointAward{}).
+		Where("rule_id = ? AND user_id = ? AND created_at >= ?", ruleID, userID, since).
+		Select("COALESCE(SUM(points), 0)").Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type FlashSaleRepository interface {
+	// FindAll lists the organisation's flash sales, latest start first
+	FindAll(orgID uint) ([]model.FlashSale, error)
+	FindByID(orgID, id uint) (*model.FlashSale, error)
+	// FindByGift lists the gift's flash sales within tx
+	FindByGift(tx *gorm.DB, orgID, giftID uint) ([]model.FlashSale, error)
+	// FindRunning returns the gift's flash sale running at t, apperror.ErrNotFound
+	// when there is none
+	FindRunning(orgID, giftID uint, t time.Time) (*model.FlashSale, error)
+	// Create inserts the sale with one free unit per quantity. The caller
+	// reserves the quantity from the gift's stock in the same transaction.
+	Create(tx *gorm.DB, sale *model.FlashSale) error
+	// Delete soft deletes the sale and returns its free units to the gift's
+	// stock; claimed units stay with their redemptions
+	Delete(tx *gorm.DB, sale *model.FlashSale) error
+	// FindEndedUnsold lists sales that ended by now with units still free
+	FindEndedUnsold(now time.Time) ([]model.FlashSale, error)
+	// ReleaseUnsold returns the sale's free units to the gift's stock and
+	// returns how many there were
+	ReleaseUnsold(sale *model.FlashSale) (int, error)
+	// ClaimUnit gives the user a free unit of the sale and returns its ID.
+	// Units locked by other claims in flight are skipped rather than waited
+	// for. It returns apperror.ErrSoldOut when no unit is free and
+	// apperror.ErrFlashSaleLimit when the user already holds one.
+	ClaimUnit(tx *gorm.DB, saleID, userID uint) (uint, error)
+	// AttachRedemption links a claimed unit to the redemption it paid for
+	AttachRedemption(tx *gorm.DB, unitID, redemptionID uint) error
+}
+
+type flashSaleRepository struct {
+	db *gorm.DB
+}
+
+func NewFlashSaleRepository(db *gorm.DB) FlashSaleRepository {
+	return &flashSaleRepository{db}
+}
+
+// withClaimed fills FlashSale.Claimed
+func withClaimed(db *gorm.DB) *gorm.DB {
+	return db.Select("flash_sales.*, (SELECT COUNT(*) FROM flash_sale_units u " +
+		"WHERE u.flash_sale_id = flash_sales.id AND u.user_id IS NOT NULL) AS claimed")
+}
+
+func (r *flashSaleRepository) FindAll(orgID uint) ([]model.FlashSale, error) {
+	var sales []model.FlashSale
+	err := r.db.Scopes(inOrganisation("flash_sales", orgID), withClaimed).
+		Order("starts_at DESC, id DESC").Find(&sales).Error
+	return sales, err
+}
+
+func (r *flashSaleRepository) FindByID(orgID, id uint) (*model.FlashSale, error) {
+	var sale model.FlashSale
+	err := r.db.Scopes(inOrganisation("flash_sales", orgID), withClaimed).First(&sale, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &sale, err
+}
+
+func (r *flashSaleRepository) FindByGift(tx *gorm.DB, orgID, giftID uint) ([]model.FlashSale, error) {
+	var sales []model.FlashSale
+	err := tx.Scopes(inOrganisation("flash_sales", orgID), withClaimed).
+		Where("gift_id = ?", giftID).
+		Order("starts_at ASC, id ASC").Find(&sales).Error
+	return sales, err
+}
+
+func (r *flashSaleRepository) FindRunning(orgID, giftID uint, t time.Time) (*model.FlashSale, error) {
+	var sale model.FlashSale
+	err := r.db.Scopes(inOrganisation("flash_sales", orgID)).
+		Where("gift_id = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", giftID, t, t).
+		Order("starts_at ASC, id ASC").First(&sale).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &sale, err
+}
+
+func (r *flashSaleRepository) Create(tx *gorm.DB, sale *model.FlashSale) error {
+	if err := tx.Create(sale).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO flash_sale_units (flash_sale_id)
+		SELECT ? FROM generate_series(1, ?)
+	`, sale.ID, sale.Quantity).Error
+}
+
+func (r *flashSaleRepository) Delete(tx *gorm.DB, sale *model.FlashSale) error {
+	if _, err := releaseFreeUnits(tx, sale); err != nil {
+		return err
+	}
+	return tx.Delete(sale).Error
+}
+
+func (r *flashSaleRepository) FindEndedUnsold(now time.Time) ([]model.FlashSale, error) {
+	var sales []model.FlashSale
+	err := r.db.Where("ends_at <= ? AND EXISTS (SELECT 1 FROM flash_sale_units u "+
+		"WHERE u.flash_sale_id = flash_sales.id AND u.user_id IS NULL)", now).
+		Order("id ASC").Find(&sales).Error
+	return sales, err
+}
+
+func (r *flashSaleRepository) ReleaseUnsold(sale *model.FlashSale) (int, error) {
+	var released int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseFreeUnits(tx, sale)
+		return err
+	})
+	return released, err
+}
+
+// releaseFreeUnits deletes the sale's free units and puts them back in the
+// gift's stock. A unit a redemption is claiming right now is locked, so the
+// delete waits for it and skips it once claimed.
+func releaseFreeUnits(tx *gorm.DB, sale *model.FlashSale) (int, error) {
+	freed := tx.Exec("DELETE FROM flash_sale_units WHERE flash_sale_id = ? AND user_id IS NULL", sale.ID)
+	if freed.Error != nil {
+		return 0, freed.Error
+	}
+	if freed.RowsAffected > 0 {
+		err := tx.Exec("UPDATE gifts SET stock = stock + ?, updated_at = NOW() WHERE id = ?",
+			freed.RowsAffected, sale.GiftID).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int(freed.RowsAffected), nil
+}
+
+func (r *flashSaleRepository) ClaimUnit(tx *gorm.DB, saleID, userID uint) (uint, error) {
+	var unitIDs []uint
+	err := tx.Raw(`
+		UPDATE flash_sale_units SET user_id = ?, claimed_at = NOW()
+		WHERE id = (
+			SELECT id FROM flash_sale_units
+			WHERE flash_sale_id = ? AND user_id IS NULL
+			LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`, userID, saleID).Scan(&unitIDs).Error
+	if err != nil {
+		if isDuplicateError(err) {
+			return 0, apperror.ErrFlashSaleLimit
+		}
+		return 0, err
+	}
+	if len(unitIDs) == 0 {
+		return 0, apperror.ErrSoldOut
+	}
+	return unitIDs[0], nil
+}
+
+func (r *flashSaleRepository) AttachRedemption(tx *gorm.DB, unitID, redemptionID uint) error {
+	return tx.Model(&model.FlashSaleUnit{}).Where("id = ?", unitID).
+		Update("redemption_id", redemptionID).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlashSaleRepository_FindEndedUnsold_OnlyEndedWithFreeUnits(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	_, err := repo.FindEndedUnsold(time.Now())
+
+	assert.NoError(t, err)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "ends_at <=")
+		assert.Contains(t, (*statements)[0], "user_id IS NULL")
+		assert.Contains(t, (*statements)[0], `"deleted_at" IS NULL`)
+	}
+}
+
+func TestFlashSaleRepository_Delete_FreesUnclaimedUnits(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	err := repo.Delete(db, &model.FlashSale{ID: 1, GiftID: 7})
+
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, *statements) {
+		assert.Contains(t, (*statements)[0], "DELETE FROM flash_sale_units")
+		assert.Contains(t, (*statements)[0], "user_id IS NULL")
+	}
+}
@@ -6,22 +6,32 @@ import (
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GiftFilter struct {
-	Page    int
-	Limit   int
-	SortBy  string // "created_at" | "avg_rating"
-	SortDir string // "asc" | "desc"
+	OrganisationID uint
+	Page           int
+	Limit          int
+	SortBy         string // "created_at" | "avg_rating"
+	SortDir        string // "asc" | "desc"
+	// AllTiers lists tier-exclusive gifts regardless of ViewerTierID
+	AllTiers bool
+	// ViewerTierID is the browsing user's tier; nil sees only gifts open to all
+	ViewerTierID *uint
 }
 
 type GiftRepository interface {
 	FindAll(filter GiftFilter) ([]model.Gift, int64, error)
-	FindByID(id uint) (*model.Gift, error)
+	FindByID(orgID, id uint) (*model.Gift, error)
 	Create(gift *model.Gift) error
 	Update(gift *model.Gift) error
-	Delete(id uint) error
-	// DeductStock reduces stock atomically inside an existing transaction
+	// UpdateImageURL sets only the cover image, leaving stock and rating stats
+	// that may have moved since the gift was loaded untouched
+	UpdateImageURL(id uint, url string) error
+	Delete(orgID, id uint) error
+	// DeductStock reduces stock atomically inside an existing transaction.
+	// giftID must come from a gift loaded through FindByID.
 	DeductStock(tx *gorm.DB, giftID uint, qty int) error
 	UpdateRatingStats(tx *gorm.DB, giftID uint) error
 }
@@ -38,7 +48,17 @@ func (r *giftRepository) FindAll(filter GiftFilter) ([]model.Gift, int64, error)
 	var gifts []model.Gift
 	var total int64
 
-	query := r.db.Model(&model.Gift{})
+	query := r.db.Model(&model.Gift{}).Scopes(inOrganisation("gifts", filter.OrganisationID))
+
+	if !filter.AllTiers {
+		if filter.ViewerTierID == nil {
+			query = query.Where("gifts.min_tier_id IS NULL")
+		} else {
+			query = query.Where("(gifts.min_tier_id IS NULL OR gifts.min_tier_id IN ("+
+				"SELECT t.id FROM tiers t JOIN tiers v ON v.organisation_id = t.organisation_id "+
+				"WHERE v.id = ? AND t.min_points <= v.min_points))", *filter.ViewerTierID)
+		}
+	}
 
 	// count before pagination
 	if err := query.Count(&total).Error; err != nil {
@@ -66,9 +86,12 @@ func (r *giftRepository) FindAll(filter GiftFilter) ([]model.Gift, int64, error)
 	return gifts, total, err
 }
 
-func (r *giftRepository) FindByID(id uint) (*model.Gift, error) {
+func (r *giftRepository) FindByID(orgID, id uint) (*model.Gift, error) {
 	var gift model.Gift
-	err := r.db.First(&gift, id).Error
+	err := r.db.
+		Scopes(inOrganisation("gifts", orgID)).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+		First(&gift, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, apperror.ErrNotFound
 	}
@@ -79,12 +102,17 @@ func (r *giftRepository) Create(gift *model.Gift) error {
 	return r.db.Create(gift).Error
 }
 
+// Update saves a gift loaded through FindByID, so it stays in its organisation
 func (r *giftRepository) Update(gift *model.Gift) error {
 	return r.db.Save(gift).Error
 }
 
-func (r *giftRepository) Delete(id uint) error {
-	result := r.db.Delete(&model.Gift{}, id)
+func (r *giftRepository) UpdateImageURL(id uint, url string) error {
+	return r.db.Model(&model.Gift{}).Where("id = ?", id).Update("image_url", url).Error
+}
+
+func (r *giftRepository) Delete(orgID, id uint) error {
+	result := r.db.Scopes(inOrganisation("gifts", orgID)).Delete(&model.Gift{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -98,7 +126,7 @@ func (r *giftRepository) Delete(id uint) error {
 func (r *giftRepository) DeductStock(tx *gorm.DB, giftID uint, qty int) error {
 	var gift model.Gift
 
-	err := tx.Set("gorm:query_option", "FOR UPDATE").
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 		First(&gift, giftID).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.ErrNotFound
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"strings"
+
+	"gorm.io/gorm"
 )
 
 // Checks for PostgreSQL unique constraint violation (code 23505)
@@ -9,3 +11,18 @@ func isDuplicateError(err error) bool {
 	return strings.Contains(err.Error(), "23505") ||
 		strings.Contains(err.Error(), "duplicate key")
 }
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern builds an ILIKE pattern matching s literally anywhere
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// inOrganisation restricts a query to one tenant. Every query reachable with
+// an ID supplied by a client must go through it.
+func inOrganisation(table string, orgID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".organisation_id = ?", orgID)
+	}
+}
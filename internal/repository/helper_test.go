@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds Postgres SQL without a server and records every statement.
+// Nothing is executed, so every query finds no rows and every write affects none.
+func dryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=dry_run"), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open dry run db: %v", err)
+	}
+
+	var statements []string
+	record := func(db *gorm.DB) { statements = append(statements, db.Statement.SQL.String()) }
+	callbacks := db.Callback()
+	_ = callbacks.Query().After("gorm:query").Register("test:record", record)
+	_ = callbacks.Create().After("gorm:create").Register("test:record", record)
+	_ = callbacks.Update().After("gorm:update").Register("test:record", record)
+	_ = callbacks.Delete().After("gorm:delete").Register("test:record", record)
+	_ = callbacks.Raw().After("gorm:raw").Register("test:record", record)
+	return db, &statements
+}
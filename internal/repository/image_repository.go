@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type ImageRepository interface {
+	Create(image *model.Image) error
+	FindByID(id uint) (*model.Image, error)
+	FindByOwner(ownerType string, ownerID uint) ([]model.Image, error)
+	CountByOwner(ownerType string, ownerID uint) (int64, error)
+	Delete(id uint) error
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db}
+}
+
+func (r *imageRepository) Create(image *model.Image) error {
+	return r.db.Create(image).Error
+}
+
+func (r *imageRepository) FindByID(id uint) (*model.Image, error) {
+	var image model.Image
+	err := r.db.First(&image, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &image, err
+}
+
+func (r *imageRepository) FindByOwner(ownerType string, ownerID uint) ([]model.Image, error) {
+	var images []model.Image
+	err := r.db.
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("position ASC, id ASC").
+		Find(&images).Error
+	return images, err
+}
+
+func (r *imageRepository) CountByOwner(ownerType string, ownerID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Image{}).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *imageRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.Image{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
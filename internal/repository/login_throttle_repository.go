@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepository interface {
+	// FindLocked returns the entries among scope/key pairs that are locked right now
+	FindLocked(accountKey, ip string) ([]model.LoginThrottle, error)
+	// RecordFailure atomically increments the counter, restarting it when the
+	// previous failure is older than resetAfter
+	RecordFailure(scope, key string, resetAfter time.Duration) (*model.LoginThrottle, error)
+	Lock(id uint, until time.Time) error
+	Reset(scope, key string) error
+	FindAllLocked() ([]model.LoginThrottle, error)
+	FindByID(id uint) (*model.LoginThrottle, error)
+	Delete(id uint) error
+}
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db}
+}
+
+func (r *loginThrottleRepository) FindLocked(accountKey, ip string) ([]model.LoginThrottle, error) {
+	var throttles []model.LoginThrottle
+	err := r.db.
+		Where("(scope = ? AND key = ?) OR (scope = ? AND key = ?)",
+			model.ThrottleScopeAccount, accountKey, model.ThrottleScopeIP, ip).
+		Where("locked_until > NOW()").
+		Find(&throttles).Error
+	return throttles, err
+}
+
+func (r *loginThrottleRepository) RecordFailure(scope, key string, resetAfter time.Duration) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	err := r.db.Raw(`
+		INSERT INTO login_throttles (scope, key, failed_count, last_failed_at, created_at, updated_at)
+		VALUES (?, ?, 1, NOW(), NOW(), NOW())
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failed_count   = CASE
+		                         WHEN login_throttles.last_failed_at < NOW() - make_interval(secs => ?) THEN 1
+		                         ELSE login_throttles.failed_count + 1
+		                     END,
+		    last_failed_at = NOW(),
+		    updated_at     = NOW()
+		RETURNING *
+	`, scope, key, resetAfter.Seconds()).Scan(&throttle).Error
+	return &throttle, err
+}
+
+func (r *loginThrottleRepository) Lock(id uint, until time.Time) error {
+	// never shorten a lock another replica already extended
+	return r.db.Model(&model.LoginThrottle{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, until).
+		Updates(map[string]interface{}{"locked_until": until, "updated_at": gorm.Expr("NOW()")}).Error
+}
+
+func (r *loginThrottleRepository) Reset(scope, key string) error {
+	return r.db.
+		Where("scope = ? AND key = ?", scope, key).
+		Delete(&model.LoginThrottle{}).Error
+}
+
+func (r *loginThrottleRepository) FindAllLocked() ([]model.LoginThrottle, error) {
+	var throttles []model.LoginThrottle
+	err := r.db.
+		Where("locked_until > NOW()").
+		Order("locked_until DESC").
+		Find(&throttles).Error
+	return throttles, err
+}
+
+func (r *loginThrottleRepository) FindByID(id uint) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	err := r.db.First(&throttle, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &throttle, err
+}
+
+func (r *loginThrottleRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.LoginThrottle{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
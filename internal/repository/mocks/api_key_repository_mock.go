@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(key *model.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByID(id uint) (*model.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(prefix string) (*model.APIKey, error) {
+	args := m.Called(prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAll() ([]model.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id uint, ip string) error {
+	args := m.Called(id, ip)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(entry *model.AuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) FindByUser(userID uint) ([]model.AuditLog, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.AuditLog), args.Error(1)
+}
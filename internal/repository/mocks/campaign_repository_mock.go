@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCampaignRepository struct {
+	mock.Mock
+}
+
+func (m *MockCampaignRepository) FindAll(orgID uint) ([]model.Campaign, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) FindByID(orgID, id uint) (*model.Campaign, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) Create(campaign *model.Campaign) error {
+	args := m.Called(campaign)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Update(campaign *model.Campaign) error {
+	args := m.Called(campaign)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Delete(orgID, id uint) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) FindRunning(orgID uint, t time.Time) ([]model.Campaign, error) {
+	args := m.Called(orgID, t)
+	return args.Get(0).([]model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) Stats(campaignID uint) (*repository.CampaignStats, error) {
+	args := m.Called(campaignID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.CampaignStats), args.Error(1)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockCouponRepository struct {
+	mock.Mock
+}
+
+func (m *MockCouponRepository) FindAll(orgID uint) ([]model.Coupon, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]model.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) FindByID(orgID, id uint) (*model.Coupon, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) FindByCode(orgID uint, code string) (*model.Coupon, error) {
+	args := m.Called(orgID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) Create(coupon *model.Coupon) error {
+	args := m.Called(coupon)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) Update(coupon *model.Coupon) error {
+	args := m.Called(coupon)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) Delete(orgID, id uint) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) Use(tx *gorm.DB, coupon *model.Coupon, userID uint) error {
+	args := m.Called(tx, coupon, userID)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepository) RecordUse(tx *gorm.DB, use *model.CouponRedemption) error {
+	args := m.Called(tx, use)
+	return args.Error(0)
+}
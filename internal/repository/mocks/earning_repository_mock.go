@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockEarningRepository struct {
+	mock.Mock
+}
+
+func (m *MockEarningRepository) FindAllRules(orgID uint) ([]model.EarningRule, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]model.EarningRule), args.Error(1)
+}
+
+func (m *MockEarningRepository) FindRuleByID(orgID, id uint) (*model.EarningRule, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EarningRule), args.Error(1)
+}
+
+func (m *MockEarningRepository) CreateRule(rule *model.EarningRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockEarningRepository) UpdateRule(rule *model.EarningRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockEarningRepository) DeleteRule(orgID, id uint) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockEarningRepository) FindActiveRules(orgID uint, eventType string) ([]model.EarningRule, error) {
+	args := m.Called(orgID, eventType)
+	return args.Get(0).([]model.EarningRule), args.Error(1)
+}
+
+func (m *MockEarningRepository) FindEventByExternalID(orgID uint, externalID string) (*model.PointEvent, error) {
+	args := m.Called(orgID, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PointEvent), args.Error(1)
+}
+
+func (m *MockEarningRepository) CreateEvent(tx *gorm.DB, event *model.PointEvent) error {
+	args := m.Called(tx, event)
+	return args.Error(0)
+}
+
+func (m *MockEarningRepository) CreateAward(tx *gorm.DB, award *model.PointAward) error {
+	args := m.Called(tx, award)
+	return args.Error(0)
+}
+
+func (m *MockEarningRepository) AwardedSince(tx *gorm.DB, ruleID, userID uint, since time.Time) (int, error) {
+	args := m.Called(tx, ruleID, userID, since)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockFlashSaleRepository struct {
+	mock.Mock
+}
+
+func (m *MockFlashSaleRepository) FindAll(orgID uint) ([]model.FlashSale, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) FindByID(orgID, id uint) (*model.FlashSale, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) FindByGift(tx *gorm.DB, orgID, giftID uint) ([]model.FlashSale, error) {
+	args := m.Called(tx, orgID, giftID)
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) FindRunning(orgID, giftID uint, t time.Time) (*model.FlashSale, error) {
+	args := m.Called(orgID, giftID, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) Create(tx *gorm.DB, sale *model.FlashSale) error {
+	args := m.Called(tx, sale)
+	return args.Error(0)
+}
+
+func (m *MockFlashSaleRepository) Delete(tx *gorm.DB, sale *model.FlashSale) error {
+	args := m.Called(tx, sale)
+	return args.Error(0)
+}
+
+func (m *MockFlashSaleRepository) FindEndedUnsold(now time.Time) ([]model.FlashSale, error) {
+	args := m.Called(now)
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) ReleaseUnsold(sale *model.FlashSale) (int, error) {
+	args := m.Called(sale)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) ClaimUnit(tx *gorm.DB, saleID, userID uint) (uint, error) {
+	args := m.Called(tx, saleID, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) AttachRedemption(tx *gorm.DB, unitID, redemptionID uint) error {
+	args := m.Called(tx, unitID, redemptionID)
+	return args.Error(0)
+}
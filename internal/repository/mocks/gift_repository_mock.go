@@ -16,8 +16,8 @@ func (m *MockGiftRepository) FindAll(filter repository.GiftFilter) ([]model.Gift
 	return args.Get(0).([]model.Gift), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockGiftRepository) FindByID(id uint) (*model.Gift, error) {
-	args := m.Called(id)
+func (m *MockGiftRepository) FindByID(orgID, id uint) (*model.Gift, error) {
+	args := m.Called(orgID, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -34,8 +34,13 @@ func (m *MockGiftRepository) Update(gift *model.Gift) error {
 	return args.Error(0)
 }
 
-func (m *MockGiftRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockGiftRepository) UpdateImageURL(id uint, url string) error {
+	args := m.Called(id, url)
+	return args.Error(0)
+}
+
+func (m *MockGiftRepository) Delete(orgID, id uint) error {
+	args := m.Called(orgID, id)
 	return args.Error(0)
 }
 
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) Create(image *model.Image) error {
+	args := m.Called(image)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) FindByID(id uint) (*model.Image, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) FindByOwner(ownerType string, ownerID uint) ([]model.Image, error) {
+	args := m.Called(ownerType, ownerID)
+	return args.Get(0).([]model.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) CountByOwner(ownerType string, ownerID uint) (int64, error) {
+	args := m.Called(ownerType, ownerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockImageRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
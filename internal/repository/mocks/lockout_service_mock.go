@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLockoutService struct {
+	mock.Mock
+}
+
+func (m *MockLockoutService) Check(email, ip string) error {
+	args := m.Called(email, ip)
+	return args.Error(0)
+}
+
+func (m *MockLockoutService) RecordFailure(email, ip string) error {
+	args := m.Called(email, ip)
+	return args.Error(0)
+}
+
+func (m *MockLockoutService) RecordSuccess(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockLockoutService) GetLocked() ([]dto.LockoutResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.LockoutResponse), args.Error(1)
+}
+
+func (m *MockLockoutService) Clear(adminID, id uint, ip string) error {
+	args := m.Called(adminID, id, ip)
+	return args.Error(0)
+}
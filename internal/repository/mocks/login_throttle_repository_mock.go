@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) FindLocked(accountKey, ip string) ([]model.LoginThrottle, error) {
+	args := m.Called(accountKey, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(scope, key string, resetAfter time.Duration) (*model.LoginThrottle, error) {
+	args := m.Called(scope, key, resetAfter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Lock(id uint, until time.Time) error {
+	args := m.Called(id, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) Reset(scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) FindAllLocked() ([]model.LoginThrottle, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) FindByID(id uint) (*model.LoginThrottle, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
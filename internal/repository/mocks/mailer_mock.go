@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(msg mailer.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
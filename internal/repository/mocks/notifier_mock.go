@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(event notifier.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrganisationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganisationRepository) FindAll() ([]model.Organisation, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Organisation), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) FindByID(id uint) (*model.Organisation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Organisation), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) FindBySlug(slug string) (*model.Organisation, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Organisation), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) Create(org *model.Organisation) error {
+	args := m.Called(org)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) Update(org *model.Organisation) error {
+	args := m.Called(org)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(token *model.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) FindUsableByHashForUpdate(tx *gorm.DB, hash string) (*model.PasswordResetToken, error) {
+	args := m.Called(tx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) Consume(tx *gorm.DB, tokenID uint) error {
+	args := m.Called(tx, tokenID)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) MarkAllUsed(tx *gorm.DB, userID uint) error {
+	args := m.Called(tx, userID)
+	return args.Error(0)
+}
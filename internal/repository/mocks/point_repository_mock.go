@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockPointRepository struct {
+	mock.Mock
+}
+
+func (m *MockPointRepository) Credit(lot *model.PointLot) error {
+	args := m.Called(lot)
+	return args.Error(0)
+}
+
+func (m *MockPointRepository) CreditLots(tx *gorm.DB, userID uint, lots []*model.PointLot) error {
+	args := m.Called(tx, userID, lots)
+	return args.Error(0)
+}
+
+func (m *MockPointRepository) LockBalances(tx *gorm.DB, userIDs ...uint) (map[uint]int, error) {
+	args := m.Called(tx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+
+func (m *MockPointRepository) ConsumeLots(tx *gorm.DB, userID uint, points int, now time.Time) ([]repository.ConsumedLot, error) {
+	args := m.Called(tx, userID, points, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ConsumedLot), args.Error(1)
+}
+
+func (m *MockPointRepository) CreateEntry(tx *gorm.DB, entry *model.PointLedgerEntry) error {
+	args := m.Called(tx, entry)
+	return args.Error(0)
+}
+
+func (m *MockPointRepository) FindDueLots(now time.Time, limit int) ([]model.PointLot, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]model.PointLot), args.Error(1)
+}
+
+func (m *MockPointRepository) ExpireLot(lot model.PointLot, now time.Time) (int, error) {
+	args := m.Called(lot, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPointRepository) FindExpiringLots(userID uint, from, until time.Time) ([]model.PointLot, error) {
+	args := m.Called(userID, from, until)
+	return args.Get(0).([]model.PointLot), args.Error(1)
+}
+
+func (m *MockPointRepository) FindEntriesByUser(userID uint, limit int) ([]model.PointLedgerEntry, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]model.PointLedgerEntry), args.Error(1)
+}
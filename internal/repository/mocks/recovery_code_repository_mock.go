@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(tx *gorm.DB, userID uint, hashes []string) error {
+	args := m.Called(tx, userID, hashes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Use(userID uint, hash string) (bool, error) {
+	args := m.Called(userID, hash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) CountUnused(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) DeleteByUser(tx *gorm.DB, userID uint) error {
+	args := m.Called(tx, userID)
+	return args.Error(0)
+}
@@ -1,7 +1,10 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
@@ -15,22 +18,30 @@ func (m *MockRedemptionRepository) Create(tx *gorm.DB, redemption *model.Redempt
 	return args.Error(0)
 }
 
-func (m *MockRedemptionRepository) FindByUserAndGift(userID, giftID uint) (*model.Redemption, error) {
-	args := m.Called(userID, giftID)
+func (m *MockRedemptionRepository) FindByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error) {
+	args := m.Called(orgID, userID, giftID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Redemption), args.Error(1)
 }
 
-func (m *MockRedemptionRepository) FindUnratedByUserAndGift(userID, giftID uint) (*model.Redemption, error) {
-	args := m.Called(userID, giftID)
+func (m *MockRedemptionRepository) FindUnratedByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error) {
+	args := m.Called(orgID, userID, giftID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Redemption), args.Error(1)
 }
 
+func (m *MockRedemptionRepository) StatsByUsers(userIDs []uint) (map[uint]repository.RedemptionStats, error) {
+	args := m.Called(userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]repository.RedemptionStats), args.Error(1)
+}
+
 type MockRatingRepository struct {
 	mock.Mock
 }
@@ -44,3 +55,49 @@ func (m *MockRatingRepository) ExistsByRedemption(redemptionID uint) (bool, erro
 	args := m.Called(redemptionID)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *MockRatingRepository) FindByID(orgID, id uint) (*model.Rating, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Rating), args.Error(1)
+}
+
+func (m *MockRatingRepository) FindVisibleByGift(filter repository.ReviewFilter) ([]model.Rating, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.Rating), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRatingRepository) FindVisibleForUpdate(tx *gorm.DB, id uint) (*model.Rating, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Rating), args.Error(1)
+}
+
+func (m *MockRatingRepository) Hide(tx *gorm.DB, id uint) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
+func (m *MockRatingRepository) UpdateVoteStats(tx *gorm.DB, ratingID uint) error {
+	args := m.Called(tx, ratingID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionRepository) FindByUser(userID uint) ([]model.Redemption, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Redemption), args.Error(1)
+}
+
+func (m *MockRatingRepository) FindByUser(userID uint) ([]model.Rating, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Rating), args.Error(1)
+}
+
+func (m *MockRedemptionRepository) PointsRedeemedSince(orgID uint, since time.Time) (map[uint]int64, error) {
+	args := m.Called(orgID, since)
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(token *model.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHashForUpdate(tx *gorm.DB, hash string) (*model.RefreshToken, error) {
+	args := m.Called(tx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(hash string) (*model.RefreshToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(tx *gorm.DB, current, next *model.RefreshToken) error {
+	args := m.Called(tx, current, next)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(userID uint, keepFamilyID string) error {
+	args := m.Called(userID, keepFamilyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) IsFamilyActive(familyID string) (bool, error) {
+	args := m.Called(familyID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) FindByUser(userID uint) ([]model.RefreshToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.RefreshToken), args.Error(1)
+}
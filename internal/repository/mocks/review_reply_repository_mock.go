@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewReplyRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewReplyRepository) Upsert(reply *model.ReviewReply) error {
+	args := m.Called(reply)
+	return args.Error(0)
+}
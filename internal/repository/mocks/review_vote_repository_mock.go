@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockReviewVoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewVoteRepository) Upsert(tx *gorm.DB, vote *model.ReviewVote) error {
+	args := m.Called(tx, vote)
+	return args.Error(0)
+}
+
+func (m *MockReviewVoteRepository) Delete(tx *gorm.DB, ratingID, userID uint) (bool, error) {
+	args := m.Called(tx, ratingID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewVoteRepository) DeleteByRating(tx *gorm.DB, ratingID uint) error {
+	args := m.Called(tx, ratingID)
+	return args.Error(0)
+}
+
+func (m *MockReviewVoteRepository) FindByUser(userID uint) ([]model.ReviewVote, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.ReviewVote), args.Error(1)
+}
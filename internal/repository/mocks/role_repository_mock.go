@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) FindAll() ([]model.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByID(id uint) (*model.Role, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(name string) (*model.Role, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Create(tx *gorm.DB, role *model.Role) error {
+	args := m.Called(tx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Update(tx *gorm.DB, role *model.Role) error {
+	args := m.Called(tx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ReplacePermissions(tx *gorm.DB, roleID uint, permissions []string) error {
+	args := m.Called(tx, roleID, permissions)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CountUsers(name string) (int64, error) {
+	args := m.Called(name)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	args := m.Called(key, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTierRepository struct {
+	mock.Mock
+}
+
+func (m *MockTierRepository) FindAll(orgID uint) ([]model.Tier, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]model.Tier), args.Error(1)
+}
+
+func (m *MockTierRepository) FindByID(orgID, id uint) (*model.Tier, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tier), args.Error(1)
+}
+
+func (m *MockTierRepository) Create(tier *model.Tier) error {
+	args := m.Called(tier)
+	return args.Error(0)
+}
+
+func (m *MockTierRepository) Update(tier *model.Tier) error {
+	args := m.Called(tier)
+	return args.Error(0)
+}
+
+func (m *MockTierRepository) Delete(orgID, id uint) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockTierRepository) ApplyChange(change *model.TierChange) (bool, error) {
+	args := m.Called(change)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTierRepository) FindChangesByUser(userID uint, limit int) ([]model.TierChange, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]model.TierChange), args.Error(1)
+}
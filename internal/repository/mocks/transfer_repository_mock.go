@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) FindPolicy(orgID uint) (*model.TransferPolicy, error) {
+	args := m.Called(orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TransferPolicy), args.Error(1)
+}
+
+func (m *MockTransferRepository) SavePolicy(policy *model.TransferPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) Create(tx *gorm.DB, transfer *model.PointTransfer) error {
+	args := m.Called(tx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) SentSince(tx *gorm.DB, senderID uint, since time.Time) (int, error) {
+	args := m.Called(tx, senderID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransferRepository) FindByUser(userID uint, limit int) ([]model.PointTransfer, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]model.PointTransfer), args.Error(1)
+}
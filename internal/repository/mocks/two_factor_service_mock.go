@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) GetStatus(userID uint) (*dto.TwoFactorStatusResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TwoFactorStatusResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) BeginSetup(userID uint) (*dto.TwoFactorSetupResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TwoFactorSetupResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) ConfirmSetup(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	args := m.Called(userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Disable(userID uint, req dto.DisableTwoFactorRequest) error {
+	args := m.Called(userID, req)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) RegenerateRecoveryCodes(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	args := m.Called(userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Verify(user *model.User, code string) error {
+	args := m.Called(user, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) IsRequired(user *model.User) bool {
+	args := m.Called(user)
+	return args.Bool(0)
+}
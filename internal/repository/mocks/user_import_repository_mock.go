@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserImportRepository) Create(job *model.UserImport) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *MockUserImportRepository) FindByID(orgID, id uint) (*model.UserImport, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserImport), args.Error(1)
+}
+
+func (m *MockUserImportRepository) Update(job *model.UserImport) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *MockUserImportRepository) FailUnfinished() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
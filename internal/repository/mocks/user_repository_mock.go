@@ -1,8 +1,12 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockUserRepository struct {
@@ -25,9 +29,28 @@ func (m *MockUserRepository) FindByEmail(email string) (*model.User, error) {
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) FindAll() ([]model.User, error) {
-	args := m.Called()
-	return args.Get(0).([]model.User), args.Error(1)
+func (m *MockUserRepository) FindInOrganisation(orgID, id uint) (*model.User, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(filter repository.UserFilter) ([]model.User, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+// FindInBatches passes the users given to Return to fn as a single batch
+func (m *MockUserRepository) FindInBatches(filter repository.UserFilter, batchSize int, fn func([]model.User) error) error {
+	args := m.Called(filter, batchSize)
+	if users, ok := args.Get(0).([]model.User); ok && len(users) > 0 {
+		if err := fn(users); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
 func (m *MockUserRepository) Create(user *model.User) error {
@@ -35,12 +58,65 @@ func (m *MockUserRepository) Create(user *model.User) error {
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Update(user *model.User) error {
+func (m *MockUserRepository) MarkEmailVerified(userID uint, email string, at time.Time) error {
+	args := m.Called(userID, email, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateProfile(user *model.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockUserRepository) UpdateAccount(user *model.User, revokeTokens bool) error {
+	args := m.Called(user, revokeTokens)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(tx *gorm.DB, userID uint, hashed string) error {
+	args := m.Called(tx, userID, hashed)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(orgID, id uint) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetTOTPSecret(userID uint, secret string) error {
+	args := m.Called(userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableTOTP(tx *gorm.DB, userID uint, step int64) error {
+	args := m.Called(tx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DisableTOTP(tx *gorm.DB, userID uint) error {
+	args := m.Called(tx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdvanceTOTPStep(userID uint, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) DebitPoints(tx *gorm.DB, userID uint, points int) error {
+	args := m.Called(tx, userID, points)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindInOrganisationWithDeleted(orgID, id uint) (*model.User, error) {
+	args := m.Called(orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Anonymise(tx *gorm.DB, userID uint, erasedAt time.Time) error {
+	args := m.Called(tx, userID, erasedAt)
 	return args.Error(0)
 }
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type OrganisationRepository interface {
+	FindAll() ([]model.Organisation, error)
+	FindByID(id uint) (*model.Organisation, error)
+	FindBySlug(slug string) (*model.Organisation, error)
+	Create(org *model.Organisation) error
+	Update(org *model.Organisation) error
+}
+
+type organisationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganisationRepository(db *gorm.DB) OrganisationRepository {
+	return &organisationRepository{db}
+}
+
+func (r *organisationRepository) FindAll() ([]model.Organisation, error) {
+	var orgs []model.Organisation
+	err := r.db.Order("name ASC").Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *organisationRepository) FindByID(id uint) (*model.Organisation, error) {
+	var org model.Organisation
+	err := r.db.First(&org, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &org, err
+}
+
+func (r *organisationRepository) FindBySlug(slug string) (*model.Organisation, error) {
+	var org model.Organisation
+	err := r.db.Where("slug = ?", slug).First(&org).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &org, err
+}
+
+func (r *organisationRepository) Create(org *model.Organisation) error {
+	err := r.db.Create(org).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *organisationRepository) Update(org *model.Organisation) error {
+	return r.db.Save(org).Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository interface {
+	Create(token *model.PasswordResetToken) error
+	// FindUsableByHashForUpdate returns an unused, unexpired token and locks it
+	FindUsableByHashForUpdate(tx *gorm.DB, hash string) (*model.PasswordResetToken, error)
+	// Consume marks the token used, apperror.ErrNotFound when it already was
+	Consume(tx *gorm.DB, tokenID uint) error
+	// MarkAllUsed consumes every outstanding token of the user
+	MarkAllUsed(tx *gorm.DB, userID uint) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordResetRepository) FindUsableByHashForUpdate(tx *gorm.DB, hash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > NOW()", hash).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *passwordResetRepository) Consume(tx *gorm.DB, tokenID uint) error {
+	result := tx.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) MarkAllUsed(tx *gorm.DB, userID uint) error {
+	return tx.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_FindUsableByHashForUpdate_LocksRow(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewPasswordResetRepository(db)
+
+	_, err := repo.FindUsableByHashForUpdate(db, "hash")
+
+	assert.NoError(t, err)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "FOR UPDATE")
+	}
+}
+
+func TestPasswordResetRepository_Consume_AlreadyUsed(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewPasswordResetRepository(db)
+
+	// a dry run affects no rows, as when a concurrent reset spent the token first
+	err := repo.Consume(db, 1)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "used_at IS NULL")
+	}
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConsumedLot is the part of a lot taken by a debit
+type ConsumedLot struct {
+	LotID     uint
+	Points    int
+	ExpiresAt *time.Time
+}
+
+// PointRepository keeps a user's point lots, balance and ledger in step.
+// Writers lock the user row before any of its lots so they cannot deadlock.
+type PointRepository interface {
+	// Credit stores the lot, adds it to the user's balance and records the
+	// credit with the lot's source as its type
+	Credit(lot *model.PointLot) error
+	// CreditLots stores lots of one user and adds them to their balance inside
+	// a transaction that already holds the user's lock
+	CreditLots(tx *gorm.DB, userID uint, lots []*model.PointLot) error
+	// LockBalances locks the users' rows in ID order, so concurrent writers
+	// touching the same users cannot deadlock, and returns their balances
+	LockBalances(tx *gorm.DB, userIDs ...uint) (map[uint]int, error)
+	// ConsumeLots takes points from the user's unexpired lots, soonest to
+	// expire first, inside the transaction that debited the balance. It
+	// returns apperror.ErrInsufficientPoints if the lots do not cover points.
+	ConsumeLots(tx *gorm.DB, userID uint, points int, now time.Time) ([]ConsumedLot, error)
+	CreateEntry(tx *gorm.DB, entry *model.PointLedgerEntry) error
+	// FindDueLots returns up to limit expired lots that still hold points
+	FindDueLots(now time.Time, limit int) ([]model.PointLot, error)
+	// ExpireLot writes off what is left of a due lot and returns the points
+	// expired, 0 when a concurrent run or debit got there first
+	ExpireLot(lot model.PointLot, now time.Time) (int, error)
+	// FindExpiringLots returns the user's lots with points left that expire
+	// after from and no later than until, soonest first
+	FindExpiringLots(userID uint, from, until time.Time) ([]model.PointLot, error)
+	// FindEntriesByUser lists the user's ledger, newest first; limit 0 lists all
+	FindEntriesByUser(userID uint, limit int) ([]model.PointLedgerEntry, error)
+}
+
+type pointRepository struct {
+	db *gorm.DB
+}
+
+func NewPointRepository(db *gorm.DB) PointRepository {
+	return &pointRepository{db}
+}
+
+func (r *pointRepository) Credit(lot *model.PointLot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, lot.UserID); err != nil {
+			return err
+		}
+		if err := r.CreditLots(tx, lot.UserID, []*model.PointLot{lot}); err != nil {
+			return err
+		}
+		return tx.Create(&model.PointLedgerEntry{
+			OrganisationID: lot.OrganisationID,
+			UserID:         lot.UserID,
+			Type:           lot.Source,
+			Points:         lot.Points,
+			LotID:          &lot.ID,
+		}).Error
+	})
+}
+
+func (r *pointRepository) CreditLots(tx *gorm.DB, userID uint, lots []*model.PointLot) error {
+	total := 0
+	for _, lot := range lots {
+		if err := tx.Create(lot).Error; err != nil {
+			return err
+		}
+		total += lot.Points
+	}
+	return tx.Model(&model.User{}).Unscoped().Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"point_balance": gorm.Expr("point_balance + ?", total),
+			"updated_at":    gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *pointRepository) LockBalances(tx *gorm.DB, userIDs ...uint) (map[uint]int, error) {
+	ids := slices.Clone(userIDs)
+	slices.Sort(ids)
+
+	balances := make(map[uint]int, len(ids))
+	for _, id := range slices.Compact(ids) {
+		var user model.User
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "point_balance").First(&user, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		balances[id] = user.PointBalance
+	}
+	return balances, nil
+}
+
+func (r *pointRepository) ConsumeLots(tx *gorm.DB, userID uint, points int, now time.Time) ([]ConsumedLot, error) {
+	var lots []model.PointLot
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND remaining > 0 AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Order("expires_at ASC NULLS LAST, id ASC").
+		Find(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var consumed []ConsumedLot
+	for _, lot := range lots {
+		if points == 0 {
+			break
+		}
+		take := min(points, lot.Remaining)
+		err := tx.Model(&model.PointLot{}).Where("id = ?", lot.ID).
+			Update("remaining", gorm.Expr("remaining - ?", take)).Error
+		if err != nil {
+			return nil, err
+		}
+		consumed = append(consumed, ConsumedLot{LotID: lot.ID, Points: take, ExpiresAt: lot.ExpiresAt})
+		points -= take
+	}
+	// the balance still counts lots the expiry job has not reached yet
+	if points > 0 {
+		return nil, apperror.ErrInsufficientPoints
+	}
+	return consumed, nil
+}
+
+func (r *pointRepository) CreateEntry(tx *gorm.DB, entry *model.PointLedgerEntry) error {
+	return tx.Create(entry).Error
+}
+
+func (r *pointRepository) FindDueLots(now time.Time, limit int) ([]model.PointLot, error) {
+	var lots []model.PointLot
+	err := r.db.Where("remaining > 0 AND expires_at <= ?", now).
+		Order("expires_at ASC, id ASC").Limit(limit).Find(&lots).Error
+	return lots, err
+}
+
+func (r *pointRepository) ExpireLot(lot model.PointLot, now time.Time) (int, error) {
+	expired := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, lot.UserID); err != nil {
+			return err
+		}
+
+		var current model.PointLot
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("remaining > 0 AND expires_at <= ?", now).
+			First(&current, lot.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		remaining := current.Remaining
+		if err := tx.Model(&model.PointLot{}).Where("id = ?", current.ID).Update("remaining", 0).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&model.User{}).Unscoped().Where("id = ?", current.UserID).
+			Updates(map[string]interface{}{
+				"point_balance": gorm.Expr("point_balance - ?", remaining),
+				"updated_at":    gorm.Expr("NOW()"),
+			}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&model.PointLedgerEntry{
+			OrganisationID: current.OrganisationID,
+			UserID:         current.UserID,
+			Type:           model.LedgerExpiry,
+			Points:         -remaining,
+			LotID:          &current.ID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		expired = remaining
+		return nil
+	})
+	return expired, err
+}
+
+func (r *pointRepository) FindExpiringLots(userID uint, from, until time.Time) ([]model.PointLot, error) {
+	var lots []model.PointLot
+	err := r.db.Where("user_id = ? AND remaining > 0 AND expires_at > ? AND expires_at <= ?", userID, from, until).
+		Order("expires_at ASC, id ASC").Find(&lots).Error
+	return lots, err
+}
+
+func (r *pointRepository) FindEntriesByUser(userID uint, limit int) ([]model.PointLedgerEntry, error) {
+	var entries []model.PointLedgerEntry
+	query := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&entries).Error
+	return entries, err
+}
+
+// lockUser takes the user's row lock, which orders every balance writer;
+// deactivated and erased users keep their points until they expire
+func lockUser(tx *gorm.DB, userID uint) error {
+	var user model.User
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.ErrNotFound
+	}
+	return err
+}
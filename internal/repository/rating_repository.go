@@ -1,14 +1,38 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+type ReviewFilter struct {
+	OrganisationID uint
+	GiftID         uint
+	Page           int
+	Limit          int
+	SortBy         string // "created_at" | "score" | "most_helpful"
+	SortDir        string // "asc" | "desc"
+}
+
 type RatingRepository interface {
 	Create(tx *gorm.DB, rating *model.Rating) error
 	ExistsByRedemption(redemptionID uint) (bool, error)
+	FindByID(orgID, id uint) (*model.Rating, error)
+	// FindVisibleForUpdate locks a rating that has not been hidden, so it
+	// cannot be hidden until tx ends; apperror.ErrNotFound otherwise
+	FindVisibleForUpdate(tx *gorm.DB, id uint) (*model.Rating, error)
+	// FindVisibleByGift lists ratings of a gift that have not been hidden
+	FindVisibleByGift(filter ReviewFilter) ([]model.Rating, int64, error)
+	Hide(tx *gorm.DB, id uint) error
+	// UpdateVoteStats recalculates helpful counters from review_votes
+	UpdateVoteStats(tx *gorm.DB, ratingID uint) error
+	// FindByUser lists every rating of the user, hidden ones included, with
+	// gift, photos and reply
+	FindByUser(userID uint) ([]model.Rating, error)
 }
 
 type ratingRepository struct {
@@ -34,3 +58,102 @@ func (r *ratingRepository) ExistsByRedemption(redemptionID uint) (bool, error) {
 		Count(&count).Error
 	return count > 0, err
 }
+
+func (r *ratingRepository) FindByID(orgID, id uint) (*model.Rating, error) {
+	var rating model.Rating
+	err := r.db.Scopes(inOrganisation("ratings", orgID)).Preload("Reply").First(&rating, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &rating, err
+}
+
+func (r *ratingRepository) FindVisibleForUpdate(tx *gorm.DB, id uint) (*model.Rating, error) {
+	var rating model.Rating
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("hidden_at IS NULL").
+		First(&rating, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &rating, err
+}
+
+func (r *ratingRepository) FindVisibleByGift(filter ReviewFilter) ([]model.Rating, int64, error) {
+	var ratings []model.Rating
+	var total int64
+
+	query := r.db.Model(&model.Rating{}).
+		Scopes(inOrganisation("ratings", filter.OrganisationID)).
+		Where("gift_id = ? AND hidden_at IS NULL", filter.GiftID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortDir := "DESC"
+	if filter.SortDir == "asc" {
+		sortDir = "ASC"
+	}
+
+	order := "created_at " + sortDir
+	switch filter.SortBy {
+	case "score":
+		order = "score " + sortDir + ", created_at DESC"
+	case "most_helpful":
+		// net helpfulness first, raw helpful votes break ties
+		order = "(helpful_count - not_helpful_count) " + sortDir + ", helpful_count " + sortDir + ", created_at DESC"
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+
+	err := query.
+		Preload("User").
+		Preload("Reply").
+		Preload("Photos").
+		Order(order).
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&ratings).Error
+
+	return ratings, total, err
+}
+
+func (r *ratingRepository) Hide(tx *gorm.DB, id uint) error {
+	result := tx.Model(&model.Rating{}).
+		Where("id = ? AND hidden_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"hidden_at":         gorm.Expr("NOW()"),
+			"helpful_count":     0,
+			"not_helpful_count": 0,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ratingRepository) UpdateVoteStats(tx *gorm.DB, ratingID uint) error {
+	return tx.Exec(`
+		UPDATE ratings
+		SET helpful_count     = (SELECT COUNT(*) FROM review_votes WHERE rating_id = ? AND is_helpful),
+		    not_helpful_count = (SELECT COUNT(*) FROM review_votes WHERE rating_id = ? AND NOT is_helpful),
+		    updated_at        = NOW()
+		WHERE id = ?
+	`, ratingID, ratingID, ratingID).Error
+}
+
+func (r *ratingRepository) FindByUser(userID uint) ([]model.Rating, error) {
+	var ratings []model.Rating
+	err := r.db.
+		Preload("Gift", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Photos").
+		Preload("Reply").
+		Where("user_id = ?", userID).
+		Order("created_at ASC, id ASC").
+		Find(&ratings).Error
+	return ratings, err
+}
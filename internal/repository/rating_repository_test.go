@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingRepository_FindVisibleForUpdate_LocksVisibleRow(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewRatingRepository(db)
+
+	_, err := repo.FindVisibleForUpdate(db, 1)
+
+	assert.NoError(t, err)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "hidden_at IS NULL")
+		assert.Contains(t, (*statements)[0], "FOR UPDATE")
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	// Replace discards all previous codes of the user and stores the new hashes
+	Replace(tx *gorm.DB, userID uint, hashes []string) error
+	// Use atomically marks an unused code as used and reports whether one matched
+	Use(userID uint, hash string) (bool, error)
+	CountUnused(userID uint) (int64, error)
+	DeleteByUser(tx *gorm.DB, userID uint) error
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db}
+}
+
+func (r *recoveryCodeRepository) Replace(tx *gorm.DB, userID uint, hashes []string) error {
+	if err := r.DeleteByUser(tx, userID); err != nil {
+		return err
+	}
+
+	codes := make([]model.RecoveryCode, len(hashes))
+	for i, h := range hashes {
+		codes[i] = model.RecoveryCode{UserID: userID, CodeHash: h}
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *recoveryCodeRepository) Use(userID uint, hash string) (bool, error) {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", gorm.Expr("NOW()"))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *recoveryCodeRepository) CountUnused(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) DeleteByUser(tx *gorm.DB, userID uint) error {
+	return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
@@ -11,8 +11,22 @@ import (
 
 type RedemptionRepository interface {
 	Create(tx *gorm.DB, redemption *model.Redemption) error
-	FindByUserAndGift(userID, giftID uint) (*model.Redemption, error)
-	FindUnratedByUserAndGift(userID, giftID uint) (*model.Redemption, error)
+	FindByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error)
+	FindUnratedByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error)
+	// StatsByUsers aggregates the redemptions of each user; users without any are absent
+	StatsByUsers(userIDs []uint) (map[uint]RedemptionStats, error)
+	// FindByUser lists every redemption of the user, oldest first, with the
+	// gift even when it has since been deleted
+	FindByUser(userID uint) ([]model.Redemption, error)
+	// PointsRedeemedSince sums the points each user of the organisation spent
+	// since the given time; users who spent none are absent
+	PointsRedeemedSince(orgID uint, since time.Time) (map[uint]int64, error)
+}
+
+type RedemptionStats struct {
+	UserID         uint
+	Count          int64
+	PointsRedeemed int64
 }
 
 type redemptionRepository struct {
@@ -28,9 +42,10 @@ func (r *redemptionRepository) Create(tx *gorm.DB, redemption *model.Redemption)
 	return tx.Create(redemption).Error
 }
 
-func (r *redemptionRepository) FindByUserAndGift(userID, giftID uint) (*model.Redemption, error) {
+func (r *redemptionRepository) FindByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error) {
 	var redemption model.Redemption
 	err := r.db.
+		Scopes(inOrganisation("redemptions", orgID)).
 		Where("user_id = ? AND gift_id = ?", userID, giftID).
 		First(&redemption).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -40,9 +55,10 @@ func (r *redemptionRepository) FindByUserAndGift(userID, giftID uint) (*model.Re
 }
 
 // FindUnratedByUserAndGift returns a redemption that has not been rated yet
-func (r *redemptionRepository) FindUnratedByUserAndGift(userID, giftID uint) (*model.Redemption, error) {
+func (r *redemptionRepository) FindUnratedByUserAndGift(orgID, userID, giftID uint) (*model.Redemption, error) {
 	var redemption model.Redemption
 	err := r.db.
+		Scopes(inOrganisation("redemptions", orgID)).
 		Where("user_id = ? AND gift_id = ?", userID, giftID).
 		Where("id NOT IN (SELECT redemption_id FROM ratings WHERE user_id = ? AND gift_id = ?)", userID, giftID).
 		First(&redemption).Error
@@ -51,3 +67,53 @@ func (r *redemptionRepository) FindUnratedByUserAndGift(userID, giftID uint) (*m
 	}
 	return &redemption, err
 }
+
+func (r *redemptionRepository) StatsByUsers(userIDs []uint) (map[uint]RedemptionStats, error) {
+	stats := make(map[uint]RedemptionStats, len(userIDs))
+	if len(userIDs) == 0 {
+		return stats, nil
+	}
+
+	var rows []RedemptionStats
+	err := r.db.Model(&model.Redemption{}).
+		Select("user_id, COUNT(*) AS count, COALESCE(SUM(total_point), 0) AS points_redeemed").
+		Where("user_id IN ?", userIDs).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		stats[row.UserID] = row
+	}
+	return stats, nil
+}
+
+func (r *redemptionRepository) FindByUser(userID uint) ([]model.Redemption, error) {
+	var redemptions []model.Redemption
+	err := r.db.
+		Preload("Gift", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("user_id = ?", userID).
+		Order("redeemed_at ASC, id ASC").
+		Find(&redemptions).Error
+	return redemptions, err
+}
+
+func (r *redemptionRepository) PointsRedeemedSince(orgID uint, since time.Time) (map[uint]int64, error) {
+	var rows []RedemptionStats
+	err := r.db.Model(&model.Redemption{}).
+		Scopes(inOrganisation("redemptions", orgID)).
+		Select("user_id, COALESCE(SUM(total_point), 0) AS points_redeemed").
+		Where("redeemed_at >= ?", since).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		points[row.UserID] = row.PointsRedeemed
+	}
+	return points, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	// FindByHashForUpdate locks the token row so concurrent refreshes of the same token serialise
+	FindByHashForUpdate(tx *gorm.DB, hash string) (*model.RefreshToken, error)
+	FindByHash(hash string) (*model.RefreshToken, error)
+	// Rotate revokes current and stores next as its replacement. It returns
+	// apperror.ErrTokenReused when current was revoked in the meantime.
+	Rotate(tx *gorm.DB, current, next *model.RefreshToken) error
+	RevokeFamily(familyID string) error
+	// RevokeAllForUser ends every session of the user except keepFamilyID (pass "" to end all)
+	RevokeAllForUser(userID uint, keepFamilyID string) error
+	IsFamilyActive(familyID string) (bool, error)
+	FindByUser(userID uint) ([]model.RefreshToken, error)
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHashForUpdate(tx *gorm.DB, hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *refreshTokenRepository) FindByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *refreshTokenRepository) Rotate(tx *gorm.DB, current, next *model.RefreshToken) error {
+	if err := tx.Create(next).Error; err != nil {
+		return err
+	}
+	// only the refresh that revokes current may hand out its successor
+	result := tx.Model(&model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", current.ID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": next.ID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrTokenReused
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID uint, keepFamilyID string) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userID, keepFamilyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// IsFamilyActive reports whether the session still holds an unrevoked, unexpired token.
+// A rotated chain always has exactly one such token at its head.
+func (r *refreshTokenRepository) IsFamilyActive(familyID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL AND expires_at > NOW()", familyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *refreshTokenRepository) FindByUser(userID uint) ([]model.RefreshToken, error) {
+	var tokens []model.RefreshToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&tokens).Error
+	return tokens, err
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository_FindByHashForUpdate_LocksRow(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewRefreshTokenRepository(db)
+
+	_, err := repo.FindByHashForUpdate(db, "hash")
+
+	assert.NoError(t, err)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "FOR UPDATE")
+	}
+}
+
+func TestRefreshTokenRepository_Rotate_AlreadyRevoked(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewRefreshTokenRepository(db)
+
+	// a dry run affects no rows, as when a concurrent refresh revoked current first
+	err := repo.Rotate(db, &model.RefreshToken{ID: 1}, &model.RefreshToken{ID: 2})
+
+	assert.ErrorIs(t, err, apperror.ErrTokenReused)
+	if assert.Len(t, *statements, 2) {
+		assert.Contains(t, (*statements)[1], "revoked_at IS NULL")
+	}
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewReplyRepository interface {
+	// Upsert creates the reply of a rating or replaces its body when one already exists
+	Upsert(reply *model.ReviewReply) error
+}
+
+type reviewReplyRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewReplyRepository(db *gorm.DB) ReviewReplyRepository {
+	return &reviewReplyRepository{db}
+}
+
+func (r *reviewReplyRepository) Upsert(reply *model.ReviewReply) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "rating_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"admin_id":   reply.AdminID,
+			"body":       reply.Body,
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}, clause.Returning{}).Create(reply).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/gift-redemption/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewVoteRepository interface {
+	// Upsert creates the user's vote or flips it when one already exists
+	Upsert(tx *gorm.DB, vote *model.ReviewVote) error
+	Delete(tx *gorm.DB, ratingID, userID uint) (bool, error)
+	DeleteByRating(tx *gorm.DB, ratingID uint) error
+	FindByUser(userID uint) ([]model.ReviewVote, error)
+}
+
+type reviewVoteRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewVoteRepository(db *gorm.DB) ReviewVoteRepository {
+	return &reviewVoteRepository{db}
+}
+
+func (r *reviewVoteRepository) Upsert(tx *gorm.DB, vote *model.ReviewVote) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rating_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"is_helpful": vote.IsHelpful, "updated_at": gorm.Expr("NOW()")}),
+	}).Create(vote).Error
+}
+
+func (r *reviewVoteRepository) Delete(tx *gorm.DB, ratingID, userID uint) (bool, error) {
+	result := tx.Where("rating_id = ? AND user_id = ?", ratingID, userID).Delete(&model.ReviewVote{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *reviewVoteRepository) DeleteByRating(tx *gorm.DB, ratingID uint) error {
+	return tx.Where("rating_id = ?", ratingID).Delete(&model.ReviewVote{}).Error
+}
+
+func (r *reviewVoteRepository) FindByUser(userID uint) ([]model.ReviewVote, error) {
+	var votes []model.ReviewVote
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&votes).Error
+	return votes, err
+}
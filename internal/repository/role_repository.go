@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	FindAll() ([]model.Role, error)
+	FindByID(id uint) (*model.Role, error)
+	FindByName(name string) (*model.Role, error)
+	Create(tx *gorm.DB, role *model.Role) error
+	Update(tx *gorm.DB, role *model.Role) error
+	ReplacePermissions(tx *gorm.DB, roleID uint, permissions []string) error
+	Delete(id uint) error
+	// CountUsers includes soft-deleted users, which still reference the role
+	CountUsers(name string) (int64, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db}
+}
+
+func (r *roleRepository) FindAll() ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindByID(id uint) (*model.Role, error) {
+	var role model.Role
+	err := r.db.Preload("Permissions").First(&role, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &role, err
+}
+
+func (r *roleRepository) FindByName(name string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &role, err
+}
+
+func (r *roleRepository) Create(tx *gorm.DB, role *model.Role) error {
+	err := tx.Omit("Permissions").Create(role).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *roleRepository) Update(tx *gorm.DB, role *model.Role) error {
+	return tx.Model(role).Updates(map[string]interface{}{
+		"description": role.Description,
+		"updated_at":  gorm.Expr("NOW()"),
+	}).Error
+}
+
+func (r *roleRepository) ReplacePermissions(tx *gorm.DB, roleID uint, permissions []string) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]model.RolePermission, len(permissions))
+	for i, p := range permissions {
+		rows[i] = model.RolePermission{RoleID: roleID, Permission: p}
+	}
+	return tx.Create(&rows).Error
+}
+
+func (r *roleRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.Role{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
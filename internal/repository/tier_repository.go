@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type TierRepository interface {
+	// FindAll lists the organisation's tiers from lowest to highest
+	FindAll(orgID uint) ([]model.Tier, error)
+	FindByID(orgID, id uint) (*model.Tier, error)
+	Create(tier *model.Tier) error
+	Update(tier *model.Tier) error
+	// Delete returns apperror.ErrTierInUse while a live gift requires the tier
+	Delete(orgID, id uint) error
+	// ApplyChange moves the user from change.FromTierID to change.ToTierID and
+	// records it. It reports false without writing when the user is no longer
+	// in FromTierID, so concurrent evaluations record a change only once.
+	ApplyChange(change *model.TierChange) (bool, error)
+	// FindChangesByUser lists the user's latest tier changes, newest first
+	FindChangesByUser(userID uint, limit int) ([]model.TierChange, error)
+}
+
+type tierRepository struct {
+	db *gorm.DB
+}
+
+func NewTierRepository(db *gorm.DB) TierRepository {
+	return &tierRepository{db}
+}
+
+func (r *tierRepository) FindAll(orgID uint) ([]model.Tier, error) {
+	var tiers []model.Tier
+	err := r.db.Scopes(inOrganisation("tiers", orgID)).Order("min_points ASC").Find(&tiers).Error
+	return tiers, err
+}
+
+func (r *tierRepository) FindByID(orgID, id uint) (*model.Tier, error) {
+	var tier model.Tier
+	err := r.db.Scopes(inOrganisation("tiers", orgID)).First(&tier, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &tier, err
+}
+
+func (r *tierRepository) Create(tier *model.Tier) error {
+	err := r.db.Create(tier).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+// Update saves a tier loaded through FindByID, so it stays in its organisation
+func (r *tierRepository) Update(tier *model.Tier) error {
+	err := r.db.Save(tier).Error
+	if err != nil && isDuplicateError(err) {
+		return apperror.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (r *tierRepository) Delete(orgID, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var inUse int64
+		err := tx.Model(&model.Gift{}).Where("min_tier_id = ?", id).Count(&inUse).Error
+		if err != nil {
+			return err
+		}
+		if inUse > 0 {
+			return apperror.ErrTierInUse
+		}
+
+		// deleted gifts keep the reference until now
+		err = tx.Unscoped().Model(&model.Gift{}).Where("min_tier_id = ?", id).Update("min_tier_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Scopes(inOrganisation("tiers", orgID)).Delete(&model.Tier{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *tierRepository) ApplyChange(change *model.TierChange) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND tier_id IS NOT DISTINCT FROM ?", change.UserID, change.FromTierID).
+			Update("tier_id", change.ToTierID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		applied = true
+		return tx.Create(change).Error
+	})
+	return applied, err
+}
+
+func (r *tierRepository) FindChangesByUser(userID uint, limit int) ([]model.TierChange, error) {
+	var changes []model.TierChange
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransferRepository interface {
+	// FindPolicy returns the organisation's transfer policy, a disabled one
+	// when none was saved
+	FindPolicy(orgID uint) (*model.TransferPolicy, error)
+	SavePolicy(policy *model.TransferPolicy) error
+	Create(tx *gorm.DB, transfer *model.PointTransfer) error
+	// SentSince totals the points the user sent from since on, inside the
+	// transaction holding the sender's lock
+	SentSince(tx *gorm.DB, senderID uint, since time.Time) (int, error)
+	// FindByUser lists transfers the user sent or received, newest first,
+	// with both parties, deactivated ones included
+	FindByUser(userID uint, limit int) ([]model.PointTransfer, error)
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db}
+}
+
+func (r *transferRepository) FindPolicy(orgID uint) (*model.TransferPolicy, error) {
+	var policy model.TransferPolicy
+	err := r.db.Where("organisation_id = ?", orgID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.TransferPolicy{OrganisationID: orgID}, nil
+	}
+	return &policy, err
+}
+
+func (r *transferRepository) SavePolicy(policy *model.TransferPolicy) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(policy).Error
+}
+
+func (r *transferRepository) Create(tx *gorm.DB, transfer *model.PointTransfer) error {
+	return tx.Create(transfer).Error
+}
+
+func (r *transferRepository) SentSince(tx *gorm.DB, senderID uint, since time.Time) (int, error) {
+	var total int
+	err := tx.Model(&model.PointTransfer{}).
+		Where("sender_id = ? AND created_at >= ?", senderID, since).
+		Select("COALESCE(SUM(points), 0)").Scan(&total).Error
+	return total, err
+}
+
+func (r *transferRepository) FindByUser(userID uint, limit int) ([]model.PointTransfer, error) {
+	var transfers []model.PointTransfer
+	err := r.db.
+		Preload("Sender", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Recipient", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("sender_id = ? OR recipient_id = ?", userID, userID).
+		Order("created_at DESC, id DESC").Limit(limit).
+		Find(&transfers).Error
+	return transfers, err
+}
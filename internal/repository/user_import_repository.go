@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+type UserImportRepository interface {
+	Create(job *model.UserImport) error
+	FindByID(orgID, id uint) (*model.UserImport, error)
+	Update(job *model.UserImport) error
+	// FailUnfinished marks imports interrupted by a restart as failed
+	FailUnfinished() (int64, error)
+}
+
+type userImportRepository struct {
+	db *gorm.DB
+}
+
+func NewUserImportRepository(db *gorm.DB) UserImportRepository {
+	return &userImportRepository{db}
+}
+
+func (r *userImportRepository) Create(job *model.UserImport) error {
+	return r.db.Create(job).Error
+}
+
+func (r *userImportRepository) FindByID(orgID, id uint) (*model.UserImport, error) {
+	var job model.UserImport
+	err := r.db.Where("organisation_id = ?", orgID).First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &job, err
+}
+
+func (r *userImportRepository) Update(job *model.UserImport) error {
+	return r.db.Save(job).Error
+}
+
+func (r *userImportRepository) FailUnfinished() (int64, error) {
+	res := r.db.Model(&model.UserImport{}).
+		Where("status IN ?", []string{model.ImportPending, model.ImportRunning}).
+		Updates(map[string]interface{}{"status": model.ImportFailed, "finished_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
@@ -2,19 +2,69 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"gorm.io/gorm"
 )
 
+// Values of UserFilter.Deleted
+const (
+	DeletedExclude = "exclude"
+	DeletedInclude = "include"
+	DeletedOnly    = "only"
+)
+
+type UserFilter struct {
+	OrganisationID uint
+	Page           int
+	Limit          int
+	SortBy         string // "created_at" | "name" | "email"
+	SortDir        string // "asc" | "desc"
+	// Search matches name or email, case-insensitively
+	Search   string
+	Role     string
+	Verified *bool
+	// CreatedFrom and CreatedTo bound created_at; zero values are open ends
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Deleted     string // "exclude" (default) | "include" | "only"
+}
+
 type UserRepository interface {
+	// FindByID and FindByEmail are not tenant scoped: they serve authentication
+	// and the caller's own account. Admin lookups use FindInOrganisation.
 	FindByID(id uint) (*model.User, error)
 	FindByEmail(email string) (*model.User, error)
-	FindAll() ([]model.User, error)
+	FindInOrganisation(orgID, id uint) (*model.User, error)
+	// FindInOrganisationWithDeleted also returns deactivated users
+	FindInOrganisationWithDeleted(orgID, id uint) (*model.User, error)
+	FindAll(filter UserFilter) ([]model.User, int64, error)
+	// FindInBatches calls fn with every user matching filter, ignoring its
+	// pagination, batchSize users at a time
+	FindInBatches(filter UserFilter, batchSize int, fn func([]model.User) error) error
 	Create(user *model.User) error
-	Update(user *model.User) error
-	Delete(id uint) error
+	// MarkEmailVerified verifies the email once; it leaves an already verified
+	// user, or one whose email has changed since, as it is
+	MarkEmailVerified(userID uint, email string, at time.Time) error
+	// UpdateProfile writes only the fields users edit on their own profile
+	UpdateProfile(user *model.User) error
+	// UpdateAccount writes only the name, email and role an admin edits, and
+	// tokens_valid_after when revokeTokens is set
+	UpdateAccount(user *model.User, revokeTokens bool) error
+	UpdatePassword(tx *gorm.DB, userID uint, hashed string) error
+	// SetTOTPSecret stores a pending secret; it is not enforced until EnableTOTP
+	SetTOTPSecret(userID uint, secret string) error
+	EnableTOTP(tx *gorm.DB, userID uint, step int64) error
+	DisableTOTP(tx *gorm.DB, userID uint) error
+	// AdvanceTOTPStep records an accepted code and reports false when the step was already used
+	AdvanceTOTPStep(userID uint, step int64) (bool, error)
+	// DebitPoints returns apperror.ErrInsufficientPoints if the balance is too low
+	DebitPoints(tx *gorm.DB, userID uint, points int) error
+	// Anonymise erases the user's personal data in place
+	Anonymise(tx *gorm.DB, userID uint, erasedAt time.Time) error
+	Delete(orgID, id uint) error
 }
 
 type userRepository struct {
@@ -43,10 +93,94 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	return &user, err
 }
 
-func (r *userRepository) FindAll() ([]model.User, error) {
+func (r *userRepository) FindInOrganisation(orgID, id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.Scopes(inOrganisation("users", orgID)).First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &user, err
+}
+
+func (r *userRepository) FindAll(filter UserFilter) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := r.filtered(filter)
+
+	// count before pagination
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+
+	err := query.
+		Order(userOrder(filter)).
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&users).Error
+
+	return users, total, err
+}
+
+func (r *userRepository) FindInBatches(filter UserFilter, batchSize int, fn func([]model.User) error) error {
 	var users []model.User
-	err := r.db.Find(&users).Error
-	return users, err
+	// FindInBatches pages by primary key, so the requested order is not kept
+	return r.filtered(filter).FindInBatches(&users, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(users)
+	}).Error
+}
+
+func (r *userRepository) filtered(filter UserFilter) *gorm.DB {
+	query := r.db.Model(&model.User{})
+
+	switch filter.Deleted {
+	case DeletedInclude:
+		query = query.Unscoped()
+	case DeletedOnly:
+		query = query.Unscoped().Where("users.deleted_at IS NOT NULL")
+	}
+
+	query = query.Scopes(inOrganisation("users", filter.OrganisationID))
+
+	if filter.Search != "" {
+		pattern := containsPattern(filter.Search)
+		query = query.Where("(users.name ILIKE ? OR users.email ILIKE ?)", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+	if filter.Verified != nil {
+		if *filter.Verified {
+			query = query.Where("users.email_verified_at IS NOT NULL")
+		} else {
+			query = query.Where("users.email_verified_at IS NULL")
+		}
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("users.created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("users.created_at < ?", filter.CreatedTo)
+	}
+	return query
+}
+
+func userOrder(filter UserFilter) string {
+	sortBy := "created_at"
+	switch filter.SortBy {
+	case "name", "email":
+		sortBy = filter.SortBy
+	}
+
+	sortDir := "DESC"
+	if filter.SortDir == "asc" {
+		sortDir = "ASC"
+	}
+
+	// id keeps pages stable when the sort column has duplicates
+	return "users." + sortBy + " " + sortDir + ", users.id " + sortDir
 }
 
 func (r *userRepository) Create(user *model.User) error {
@@ -57,13 +191,42 @@ func (r *userRepository) Create(user *model.User) error {
 	return err
 }
 
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) MarkEmailVerified(userID uint, email string, at time.Time) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", userID, email).
+		Update("email_verified_at", at).Error
 }
 
-func (r *userRepository) Delete(id uint) error {
-	result := r.db.Delete(&model.User{}, id)
+func (r *userRepository) UpdateProfile(user *model.User) error {
+	return r.updateColumns(user.ID, map[string]interface{}{
+		"name":                     user.Name,
+		"avatar_url":               user.AvatarURL,
+		"locale":                   user.Locale,
+		"notification_preferences": user.NotificationPreferences,
+	})
+}
+
+func (r *userRepository) UpdateAccount(user *model.User, revokeTokens bool) error {
+	columns := map[string]interface{}{
+		"name":  user.Name,
+		"email": user.Email,
+		"role":  user.Role,
+	}
+	if revokeTokens {
+		columns["tokens_valid_after"] = user.TokensValidAfter
+	}
+	return r.updateColumns(user.ID, columns)
+}
+
+// updateColumns writes only the given columns, so a copy loaded before a
+// concurrent password change, 2FA change or erasure cannot undo it. A user
+// deactivated in the meantime is apperror.ErrNotFound.
+func (r *userRepository) updateColumns(userID uint, columns map[string]interface{}) error {
+	result := r.db.Model(&model.User{}).Where("id = ?", userID).Updates(columns)
 	if result.Error != nil {
+		if isDuplicateError(result.Error) {
+			return apperror.ErrDuplicateEntry
+		}
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
@@ -71,3 +234,102 @@ func (r *userRepository) Delete(id uint) error {
 	}
 	return nil
 }
+
+// UpdatePassword also revokes access tokens issued before the change
+func (r *userRepository) UpdatePassword(tx *gorm.DB, userID uint, hashed string) error {
+	return tx.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":           hashed,
+			"tokens_valid_after": gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *userRepository) SetTOTPSecret(userID uint, secret string) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("totp_secret", secret).Error
+}
+
+func (r *userRepository) EnableTOTP(tx *gorm.DB, userID uint, step int64) error {
+	return tx.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"totp_enabled_at": gorm.Expr("NOW()"),
+			"totp_last_step":  step,
+		}).Error
+}
+
+func (r *userRepository) DisableTOTP(tx *gorm.DB, userID uint) error {
+	return tx.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error
+}
+
+func (r *userRepository) AdvanceTOTPStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) DebitPoints(tx *gorm.DB, userID uint, points int) error {
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND point_balance >= ?", userID, points).
+		Updates(map[string]interface{}{
+			"point_balance": gorm.Expr("point_balance - ?", points),
+			"updated_at":    gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrInsufficientPoints
+	}
+	return nil
+}
+
+func (r *userRepository) Delete(orgID, id uint) error {
+	result := r.db.Scopes(inOrganisation("users", orgID)).Delete(&model.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) FindInOrganisationWithDeleted(orgID, id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.Unscoped().Scopes(inOrganisation("users", orgID)).First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotFound
+	}
+	return &user, err
+}
+
+// Anonymise overwrites every personal field of the user and deactivates the
+// account. The row stays so redemptions and ratings keep their owner.
+func (r *userRepository) Anonymise(tx *gorm.DB, userID uint, erasedAt time.Time) error {
+	return tx.Unscoped().Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"name":                     model.ErasedUserName,
+			"email":                    model.ErasedUserEmail(userID),
+			"password":                 "",
+			"avatar_url":               "",
+			"locale":                   "en",
+			"notification_preferences": model.NotificationPreferences{},
+			"totp_secret":              "",
+			"totp_enabled_at":          nil,
+			"totp_last_step":           0,
+			"tokens_valid_after":       erasedAt,
+			"erased_at":                erasedAt,
+			"deleted_at":               gorm.Expr("COALESCE(deleted_at, ?)", erasedAt),
+		}).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepository_UpdateProfile_WritesOnlyProfileColumns(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewUserRepository(db)
+
+	// a dry run affects no rows, as when the user was deactivated meanwhile
+	err := repo.UpdateProfile(&model.User{ID: 1, Name: "New", Password: "stale"})
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	if assert.Len(t, *statements, 1) {
+		sql := (*statements)[0]
+		assert.Contains(t, sql, `"name"=`)
+		assert.Contains(t, sql, `"deleted_at" IS NULL`)
+		for _, column := range []string{"password", "tokens_valid_after", "totp_secret", "email_verified_at", "erased_at", "point_balance"} {
+			assert.NotContains(t, sql, `"`+column+`"`)
+		}
+	}
+}
+
+func TestUserRepository_UpdateAccount_RevokesOnlyWhenAsked(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewUserRepository(db)
+
+	_ = repo.UpdateAccount(&model.User{ID: 1, Name: "New", Email: "new@example.com", Role: model.RoleAdmin}, false)
+	_ = repo.UpdateAccount(&model.User{ID: 1, Name: "New", Email: "new@example.com", Role: model.RoleAdmin}, true)
+
+	if assert.Len(t, *statements, 2) {
+		assert.NotContains(t, (*statements)[0], "tokens_valid_after")
+		assert.NotContains(t, (*statements)[0], `"password"`)
+		assert.Contains(t, (*statements)[1], "tokens_valid_after")
+	}
+}
+
+func TestUserRepository_MarkEmailVerified_OnlyOnce(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewUserRepository(db)
+
+	err := repo.MarkEmailVerified(1, "a@example.com", time.Now())
+
+	assert.NoError(t, err)
+	if assert.Len(t, *statements, 1) {
+		assert.Contains(t, (*statements)[0], "email_verified_at IS NULL")
+		assert.NotContains(t, (*statements)[0], `"password"`)
+	}
+}
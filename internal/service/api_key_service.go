@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	AuditAPIKeyCreate = "api_key.created"
+	AuditAPIKeyRevoke = "api_key.revoked"
+
+	// keys look like gr_<prefix>_<secret>; the prefix is stored in clear to look the key up
+	apiKeyMarker = "gr"
+)
+
+type APIKeyService interface {
+	Create(adminID uint, req dto.CreateAPIKeyRequest, ip string) (*dto.APIKeyCreatedResponse, error)
+	GetAll() ([]dto.APIKeyResponse, error)
+	Revoke(adminID, id uint, ip string) error
+	// AuthenticateAPIKey returns apperror.ErrInvalidToken for unknown, revoked or expired keys
+	AuthenticateAPIKey(rawKey, ip string) (*model.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	orgRepo    repository.OrganisationRepository
+	auditRepo  repository.AuditRepository
+	now        func() time.Time
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, orgRepo repository.OrganisationRepository, auditRepo repository.AuditRepository) APIKeyService {
+	return &apiKeyService{apiKeyRepo, orgRepo, auditRepo, time.Now}
+}
+
+func (s *apiKeyService) Create(adminID uint, req dto.CreateAPIKeyRequest, ip string) (*dto.APIKeyCreatedResponse, error) {
+	scopes := make(model.StringList, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !model.IsValidScope(scope) {
+			return nil, fmt.Errorf("%w: %s", apperror.ErrInvalidScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if req.OrganisationID != nil {
+		if _, err := s.orgRepo.FindByID(*req.OrganisationID); err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, apperror.ErrUnknownOrg
+			}
+			return nil, err
+		}
+	}
+
+	prefix, err := newAPIKeyPrefix()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.APIKey{
+		Name:           strings.TrimSpace(req.Name),
+		Prefix:         prefix,
+		SecretHash:     securetoken.Hash(secret),
+		Scopes:         scopes,
+		CreatedByID:    adminID,
+		OrganisationID: req.OrganisationID,
+	}
+	now := s.now()
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	s.audit(&model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     AuditAPIKeyCreate,
+		TargetType: "api_key",
+		TargetID:   strconv.FormatUint(uint64(key.ID), 10),
+		IP:         ip,
+		Metadata:   model.JSONMap{"name": key.Name, "prefix": key.Prefix, "scopes": []string(key.Scopes), "organisation_id": key.OrganisationID},
+	})
+
+	return &dto.APIKeyCreatedResponse{
+		APIKeyResponse: dto.ToAPIKeyResponse(*key, now),
+		Key:            fmt.Sprintf("%s_%s_%s", apiKeyMarker, prefix, secret),
+	}, nil
+}
+
+func (s *apiKeyService) GetAll() ([]dto.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	result := make([]dto.APIKeyResponse, len(keys))
+	for i, k := range keys {
+		result[i] = dto.ToAPIKeyResponse(k, now)
+	}
+	return result, nil
+}
+
+func (s *apiKeyService) Revoke(adminID, id uint, ip string) error {
+	key, err := s.apiKeyRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	// revoking twice is a no-op
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.apiKeyRepo.Revoke(id); err != nil {
+		return err
+	}
+
+	s.audit(&model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     AuditAPIKeyRevoke,
+		TargetType: "api_key",
+		TargetID:   strconv.FormatUint(uint64(id), 10),
+		IP:         ip,
+		Metadata:   model.JSONMap{"name": key.Name, "prefix": key.Prefix},
+	})
+	return nil
+}
+
+func (s *apiKeyService) AuthenticateAPIKey(rawKey, ip string) (*model.APIKey, error) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyMarker || parts[1] == "" || parts[2] == "" {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	key, err := s.apiKeyRepo.FindByPrefix(parts[1])
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, apperror.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(securetoken.Hash(parts[2])), []byte(key.SecretHash)) != 1 {
+		return nil, apperror.ErrInvalidToken
+	}
+	if !key.IsActive(s.now()) {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	// usage tracking is best effort and must not fail the request
+	if err := s.apiKeyRepo.TouchLastUsed(key.ID, ip); err != nil {
+		log.Printf("touch api key %d: %v", key.ID, err)
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) audit(entry *model.AuditLog) {
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
+
+// newAPIKeyPrefix returns 8 lowercase base32 characters (40 bits)
+func newAPIKeyPrefix() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate api key prefix: %w", err)
+	}
+	return strings.ToLower(base32NoPad.EncodeToString(b)), nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPIKeyService(apiKeyRepo *mocks.MockAPIKeyRepository, auditRepo *mocks.MockAuditRepository, now time.Time) APIKeyService {
+	svc := NewAPIKeyService(apiKeyRepo, new(mocks.MockOrganisationRepository), auditRepo)
+	svc.(*apiKeyService).now = func() time.Time { return now }
+	return svc
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	apiKeyService := newTestAPIKeyService(mockAPIKeyRepo, mockAuditRepo, now)
+
+	var stored *model.APIKey
+	mockAPIKeyRepo.On("Create", mock.AnythingOfType("*model.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.APIKey)
+		stored.ID = 7
+		stored.CreatedAt = now
+	}).Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditAPIKeyCreate && e.TargetID == "7" && *e.ActorID == 1
+	})).Return(nil)
+
+	result, err := apiKeyService.Create(1, dto.CreateAPIKeyRequest{
+		Name:          "warehouse",
+		Scopes:        []string{model.PermGiftsWrite, model.PermGiftsWrite},
+		ExpiresInDays: 30,
+	}, "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), result.ID)
+	assert.Equal(t, []string{model.PermGiftsWrite}, result.Scopes)
+	assert.Equal(t, "active", result.Status)
+	assert.Equal(t, now.AddDate(0, 0, 30), *stored.ExpiresAt)
+
+	parts := strings.SplitN(result.Key, "_", 3)
+	require.Len(t, parts, 3)
+	assert.Equal(t, "gr", parts[0])
+	assert.Equal(t, stored.Prefix, parts[1])
+	assert.Equal(t, securetoken.Hash(parts[2]), stored.SecretHash)
+	assert.NotContains(t, stored.SecretHash, parts[2])
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Create_InvalidScope(t *testing.T) {
+	mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	apiKeyService := newTestAPIKeyService(mockAPIKeyRepo, mockAuditRepo, time.Now())
+
+	_, err := apiKeyService.Create(1, dto.CreateAPIKeyRequest{
+		Name:   "warehouse",
+		Scopes: []string{"gifts:delete-everything"},
+	}, "10.0.0.1")
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidScope)
+
+	// user-only permissions cannot be granted to keys
+	_, err = apiKeyService.Create(1, dto.CreateAPIKeyRequest{
+		Name:   "warehouse",
+		Scopes: []string{model.PermGiftsRedeem},
+	}, "10.0.0.1")
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidScope)
+	mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAPIKeyService_Create_UnknownOrganisation(t *testing.T) {
+	mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+	mockOrgRepo := new(mocks.MockOrganisationRepository)
+	apiKeyService := NewAPIKeyService(mockAPIKeyRepo, mockOrgRepo, new(mocks.MockAuditRepository))
+
+	orgID := uint(42)
+	mockOrgRepo.On("FindByID", orgID).Return(nil, apperror.ErrNotFound)
+
+	result, err := apiKeyService.Create(1, dto.CreateAPIKeyRequest{
+		Name:           "warehouse",
+		Scopes:         []string{model.PermGiftsRead},
+		OrganisationID: &orgID,
+	}, "10.0.0.1")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrUnknownOrg)
+	mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		rawKey  string
+		key     *model.APIKey
+		wantErr bool
+	}{
+		{"valid", "gr_abcdefgh_secret", &model.APIKey{ID: 1, SecretHash: securetoken.Hash("secret"), ExpiresAt: &future}, false},
+		{"secret containing underscores", "gr_abcdefgh_sec_ret", &model.APIKey{ID: 1, SecretHash: securetoken.Hash("sec_ret")}, false},
+		{"wrong secret", "gr_abcdefgh_guess", &model.APIKey{ID: 1, SecretHash: securetoken.Hash("secret")}, true},
+		{"revoked", "gr_abcdefgh_secret", &model.APIKey{ID: 1, SecretHash: securetoken.Hash("secret"), RevokedAt: &past}, true},
+		{"expired", "gr_abcdefgh_secret", &model.APIKey{ID: 1, SecretHash: securetoken.Hash("secret"), ExpiresAt: &past}, true},
+		{"unknown prefix", "gr_abcdefgh_secret", nil, true},
+		{"malformed", "not-a-key", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+			mockAuditRepo := new(mocks.MockAuditRepository)
+			apiKeyService := newTestAPIKeyService(mockAPIKeyRepo, mockAuditRepo, now)
+
+			if tt.key != nil {
+				mockAPIKeyRepo.On("FindByPrefix", "abcdefgh").Return(tt.key, nil)
+			} else {
+				mockAPIKeyRepo.On("FindByPrefix", "abcdefgh").Return(nil, apperror.ErrNotFound)
+			}
+			mockAPIKeyRepo.On("TouchLastUsed", uint(1), "10.0.0.1").Return(nil)
+
+			key, err := apiKeyService.AuthenticateAPIKey(tt.rawKey, "10.0.0.1")
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperror.ErrInvalidToken)
+				mockAPIKeyRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), key.ID)
+			mockAPIKeyRepo.AssertCalled(t, "TouchLastUsed", uint(1), "10.0.0.1")
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	apiKeyService := newTestAPIKeyService(mockAPIKeyRepo, mockAuditRepo, time.Now())
+
+	mockAPIKeyRepo.On("FindByID", uint(3)).Return(&model.APIKey{ID: 3, Name: "warehouse"}, nil)
+	mockAPIKeyRepo.On("Revoke", uint(3)).Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditAPIKeyRevoke && e.TargetID == "3"
+	})).Return(nil)
+
+	assert.NoError(t, apiKeyService.Revoke(1, 3, "10.0.0.1"))
+	mockAPIKeyRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Revoke_AlreadyRevoked(t *testing.T) {
+	mockAPIKeyRepo := new(mocks.MockAPIKeyRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	apiKeyService := newTestAPIKeyService(mockAPIKeyRepo, mockAuditRepo, time.Now())
+
+	revokedAt := time.Now().Add(-time.Hour)
+	mockAPIKeyRepo.On("FindByID", uint(3)).Return(&model.APIKey{ID: 3, RevokedAt: &revokedAt}, nil)
+
+	assert.NoError(t, apiKeyService.Revoke(1, 3, "10.0.0.1"))
+	mockAPIKeyRepo.AssertNotCalled(t, "Revoke", mock.Anything)
+	mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -1,55 +1,478 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gift-redemption/internal/config"
 	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/authtoken"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/securetoken"
 	"github.com/gift-redemption/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// emailVerificationTTL is how long a verification link stays valid
+const emailVerificationTTL = 24 * time.Hour
+
+// twoFactorChallengeTTL is how long the user has to enter the second factor after the password
+const twoFactorChallengeTTL = 5 * time.Minute
+
+const (
+	purposeVerifyEmail    = "verify_email"
+	purposeLoginTwoFactor = "login_2fa"
 )
 
 type AuthService interface {
-	Login(req dto.LoginRequest) (*dto.LoginResponse, error)
+	Register(orgID uint, req dto.CreateUserRequest) (*dto.UserResponse, error)
+	VerifyEmail(req dto.VerifyEmailRequest) (*dto.UserResponse, error)
+	ResendVerification(req dto.ResendVerificationRequest) error
+	// Login rejects locked accounts/IPs with an *apperror.LockedError
+	Login(req dto.LoginRequest, clientIP string) (*dto.LoginResponse, error)
+	// SetupTwoFactor lets a user who must use 2FA enrol with the login challenge token
+	SetupTwoFactor(req dto.TwoFactorChallengeRequest) (*dto.TwoFactorSetupResponse, error)
+	LoginTwoFactor(req dto.LoginTwoFactorRequest, clientIP string) (*dto.LoginResponse, error)
+	Refresh(req dto.RefreshRequest) (*dto.TokenResponse, error)
+	Logout(req dto.LogoutRequest) error
+	// ValidateSession is called by the auth middleware on every request
+	ValidateSession(userID uint, sessionID string, issuedAt time.Time) error
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	cfg      *config.Config
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	refreshRepo repository.RefreshTokenRepository
+	lockout     LockoutService
+	twoFactor   TwoFactorService
+	mailer      mailer.Mailer
+	keys        *jwtkeys.KeySet
+	cfg         *config.Config
 }
 
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
-	return &authService{userRepo, cfg}
+func NewAuthService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	lockout LockoutService,
+	twoFactor TwoFactorService,
+	mailer mailer.Mailer,
+	keys *jwtkeys.KeySet,
+	cfg *config.Config,
+) AuthService {
+	return &authService{db, userRepo, refreshRepo, lockout, twoFactor, mailer, keys, cfg}
+}
+
+// Register creates a regular user from the public sign-up form. The requested
+// role is ignored and the account cannot log in until the email is verified.
+func (s *authService) Register(orgID uint, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	user := &model.User{
+		OrganisationID: orgID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Role:           model.RoleUser,
+	}
+
+	if err := user.HashPassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendVerification(user); err != nil {
+		return nil, err
+	}
+
+	res := dto.ToUserResponse(*user)
+	return &res, nil
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+func (s *authService) VerifyEmail(req dto.VerifyEmailRequest) (*dto.UserResponse, error) {
+	claims, err := s.parsePurposeToken(req.Token, purposeVerifyEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(claims.userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// a link sent to a previous address must not verify the current one
+	if user.Email != claims.Email {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.MarkEmailVerified(user.ID, user.Email, now); err != nil {
+			return nil, err
+		}
+	}
+
+	res := dto.ToUserResponse(*user)
+	return &res, nil
+}
+
+// ResendVerification never reports whether the email exists or is already verified.
+func (s *authService) ResendVerification(req dto.ResendVerificationRequest) error {
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
-		return nil, apperror.ErrNotFound
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.IsEmailVerified() {
+		return nil
+	}
+	return s.sendVerification(user)
+}
+
+func (s *authService) Login(req dto.LoginRequest, clientIP string) (*dto.LoginResponse, error) {
+	// checked before bcrypt so a locked account costs nothing to reject
+	if err := s.lockout.Check(req.Email, clientIP); err != nil {
+		return nil, err
 	}
 
-	if !user.CheckPassword(req.Password) {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil || !user.CheckPassword(req.Password) {
+		// unknown emails count too, otherwise lockouts would reveal which accounts exist
+		if recErr := s.lockout.RecordFailure(req.Email, clientIP); recErr != nil {
+			return nil, recErr
+		}
 		return nil, apperror.ErrNotFound
 	}
 
-	token, err := s.generateToken(user.ID, string(user.Role))
+	// checked after the password so unverified status is not leaked to strangers
+	if !user.IsEmailVerified() {
+		return nil, apperror.ErrEmailNotVerified
+	}
+
+	// the failure counter is only reset once every factor has been checked
+	if user.IsTwoFactorEnabled() || s.twoFactor.IsRequired(user) {
+		return s.twoFactorChallenge(user)
+	}
+
+	if err := s.lockout.RecordSuccess(req.Email); err != nil {
+		return nil, err
+	}
+	return s.startSession(user)
+}
+
+func (s *authService) SetupTwoFactor(req dto.TwoFactorChallengeRequest) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.challengeUser(req.ChallengeToken)
 	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
+		return nil, err
+	}
+	return s.twoFactor.BeginSetup(user.ID)
+}
+
+// LoginTwoFactor completes a login started with a password. Users enrolling
+// during login confirm their first code here and receive recovery codes.
+func (s *authService) LoginTwoFactor(req dto.LoginTwoFactorRequest, clientIP string) (*dto.LoginResponse, error) {
+	user, err := s.challengeUser(req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lockout.Check(user.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if user.IsTwoFactorEnabled() {
+		err = s.twoFactor.Verify(user, req.Code)
+	} else {
+		var codes *dto.RecoveryCodesResponse
+		if codes, err = s.twoFactor.ConfirmSetup(user.ID, req.Code); err == nil {
+			recoveryCodes = codes.RecoveryCodes
+		}
+	}
+	if errors.Is(err, apperror.ErrInvalidOTP) {
+		if recErr := s.lockout.RecordFailure(user.Email, clientIP); recErr != nil {
+			return nil, recErr
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lockout.RecordSuccess(user.Email); err != nil {
+		return nil, err
+	}
+
+	res, err := s.startSession(user)
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+func (s *authService) twoFactorChallenge(user *model.User) (*dto.LoginResponse, error) {
+	token, err := s.signPurposeToken(purposeLoginTwoFactor, user, twoFactorChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("sign two-factor challenge: %w", err)
+	}
+	return &dto.LoginResponse{
+		TwoFactorRequired:      true,
+		TwoFactorSetupRequired: !user.IsTwoFactorEnabled(),
+		ChallengeToken:         token,
+		User:                   dto.ToUserResponse(*user),
+	}, nil
+}
+
+func (s *authService) challengeUser(challengeToken string) (*model.User, error) {
+	claims, err := s.parsePurposeToken(challengeToken, purposeLoginTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(claims.userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if user.Email != claims.Email {
+		return nil, apperror.ErrInvalidToken
+	}
+	return user, nil
+}
+
+func (s *authService) startSession(user *model.User) (*dto.LoginResponse, error) {
+	familyID, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, stored, err := s.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshRepo.Create(stored); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
 	}
 
-	res := dto.ToLoginResponse(token, *user)
+	tokens, err := s.issueTokens(user, familyID, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToLoginResponse(*tokens, *user)
 	return &res, nil
 }
 
-func (s *authService) generateToken(userID uint, role string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"role":    role,
-		"exp":     time.Now().Add(time.Duration(s.cfg.JWT.ExpiryHours) * time.Hour).Unix(),
+// Refresh exchanges a refresh token for a new access/refresh pair. Presenting a
+// token that was already rotated means it leaked, so the whole family is revoked.
+func (s *authService) Refresh(req dto.RefreshRequest) (*dto.TokenResponse, error) {
+	hash := securetoken.Hash(req.RefreshToken)
+
+	var tokens *dto.TokenResponse
+	var reusedFamily string
+
+	err := repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		current, err := s.refreshRepo.FindByHashForUpdate(tx, hash)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+
+		if current.IsRevoked() {
+			reusedFamily = current.FamilyID
+			return apperror.ErrTokenReused
+		}
+		if current.IsExpired(time.Now()) {
+			return apperror.ErrInvalidToken
+		}
+
+		// deleted users cannot refresh
+		user, err := s.userRepo.FindByID(current.UserID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+
+		refreshToken, next, err := s.newRefreshToken(user.ID, current.FamilyID)
+		if err != nil {
+			return err
+		}
+		if err := s.refreshRepo.Rotate(tx, current, next); err != nil {
+			if errors.Is(err, apperror.ErrTokenReused) {
+				reusedFamily = current.FamilyID
+			}
+			return err
+		}
+
+		tokens, err = s.issueTokens(user, current.FamilyID, refreshToken)
+		return err
+	})
+
+	if errors.Is(err, apperror.ErrTokenReused) {
+		// revoke outside the rolled back transaction
+		if revokeErr := s.refreshRepo.RevokeFamily(reusedFamily); revokeErr != nil {
+			return nil, fmt.Errorf("revoke token family: %w", revokeErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.cfg.JWT.Secret))
+	return tokens, nil
+}
+
+func (s *authService) Logout(req dto.LogoutRequest) error {
+	token, err := s.refreshRepo.FindByHash(securetoken.Hash(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return apperror.ErrInvalidToken
+		}
+		return err
+	}
+	return s.refreshRepo.RevokeFamily(token.FamilyID)
+}
+
+func (s *authService) ValidateSession(userID uint, sessionID string, issuedAt time.Time) error {
+	if sessionID == "" {
+		return apperror.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return apperror.ErrInvalidToken
+		}
+		return err
+	}
+	// the role claim and credentials may be stale after a password or role change
+	if user.TokenIssuedBeforeRevocation(issuedAt) {
+		return apperror.ErrInvalidToken
+	}
+
+	active, err := s.refreshRepo.IsFamilyActive(sessionID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return apperror.ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *authService) newRefreshToken(userID uint, familyID string) (string, *model.RefreshToken, error) {
+	plain, err := securetoken.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+	stored := &model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: securetoken.Hash(plain),
+		ExpiresAt: time.Now().Add(s.cfg.JWT.RefreshTTL),
+	}
+	return plain, stored, nil
+}
+
+func (s *authService) issueTokens(user *model.User, sessionID, refreshToken string) (*dto.TokenResponse, error) {
+	token, err := s.generateToken(user, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	return &dto.TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.cfg.JWT.AccessTTL.Seconds()),
+	}, nil
+}
+
+func (s *authService) generateToken(user *model.User, sessionID string) (string, error) {
+	claims, err := authtoken.New(s.cfg.JWT, user.ID, user.OrganisationID, string(user.Role), sessionID, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return s.keys.Sign(claims)
+}
+
+func (s *authService) sendVerification(user *model.User) error {
+	token, err := s.signPurposeToken(purposeVerifyEmail, user, emailVerificationTTL)
+	if err != nil {
+		return fmt.Errorf("sign verification token: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.cfg.AppBaseURL, url.QueryEscape(token))
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below. "+
+			"The link expires in %d hours.\n\n%s\n", user.Name, int(emailVerificationTTL.Hours()), link),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+	return nil
+}
+
+// purposeClaims back single-purpose tokens (email verification, 2FA challenge).
+// The purpose is the audience, so they can never pass as access tokens.
+type purposeClaims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email"`
+	// userID is parsed from the subject
+	userID uint
+}
+
+// signPurposeToken signs a single-purpose token bound to the user's current email
+func (s *authService) signPurposeToken(purpose string, user *model.User, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &purposeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.cfg.JWT.Issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  jwt.ClaimStrings{purpose},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Email: user.Email,
+	}
+	return s.keys.Sign(claims)
+}
+
+func (s *authService) parsePurposeToken(tokenStr, purpose string) (*purposeClaims, error) {
+	claims := &purposeClaims{}
+	_, err := s.keys.Parse(tokenStr, claims,
+		jwt.WithIssuer(s.cfg.JWT.Issuer),
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 0)
+	if err != nil {
+		return nil, apperror.ErrInvalidToken
+	}
+	claims.userID = uint(userID)
+	return claims, nil
 }
@@ -1,60 +1,106 @@
 package service
 
 import (
+	"net/url"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/gift-redemption/internal/config"
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/authtoken"
+	"github.com/gift-redemption/internal/pkg/jwtkeys"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/securetoken"
 	"github.com/gift-redemption/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+var testKeys = jwtkeys.NewHMAC("test-secret")
+
 func TestAuthService_Login_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
-			Secret:      "test-secret",
-			ExpiryHours: 24,
+			Secret:     "test-secret",
+			Issuer:     "https://gifts.example.com",
+			Audience:   "gift-redemption-api",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
 		},
 	}
-	authService := NewAuthService(mockUserRepo, cfg)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
 
+	verifiedAt := time.Now()
 	user := &model.User{
-		ID:    1,
-		Name:  "Test User",
-		Email: "test@example.com",
-		Role:  model.RoleUser,
+		ID:              1,
+		Name:            "Test User",
+		OrganisationID:  1,
+		Email:           "test@example.com",
+		Role:            model.RoleUser,
+		EmailVerifiedAt: &verifiedAt,
 	}
 	_ = user.HashPassword("password123")
 
+	mockLockout.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockLockout.On("RecordSuccess", "test@example.com").Return(nil)
+	mockTwoFactor.On("IsRequired", user).Return(false)
 	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockRefreshRepo.On("Create", mock.MatchedBy(func(rt *model.RefreshToken) bool {
+		return rt.UserID == 1 && rt.FamilyID != "" && len(rt.TokenHash) == 64
+	})).Return(nil)
 
 	req := dto.LoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
 	}
 
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, "10.0.0.1")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.Equal(t, 900, result.ExpiresIn)
+	mockRefreshRepo.AssertExpectations(t)
 	assert.Equal(t, user.ID, result.User.ID)
+
+	claims, err := authtoken.Parse(testKeys, cfg.JWT, result.Token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", claims.Subject)
+		assert.Equal(t, "https://gifts.example.com", claims.Issuer)
+		assert.Equal(t, []string{"gift-redemption-api"}, []string(claims.Audience))
+		assert.Equal(t, "user", claims.Role)
+		assert.Equal(t, uint(1), claims.OrganisationID)
+		assert.NotEmpty(t, claims.ID)
+		assert.NotEmpty(t, claims.SessionID)
+	}
 	assert.Equal(t, user.Email, result.User.Email)
 	mockUserRepo.AssertExpectations(t)
+	mockLockout.AssertExpectations(t)
 }
 
 func TestAuthService_Login_WrongPassword(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
-			Secret:      "test-secret",
-			ExpiryHours: 24,
+			Secret:     "test-secret",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
 		},
 	}
-	authService := NewAuthService(mockUserRepo, cfg)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
 
 	user := &model.User{
 		ID:    1,
@@ -62,6 +108,8 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 	}
 	_ = user.HashPassword("correctpassword")
 
+	mockLockout.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockLockout.On("RecordFailure", "test@example.com", "10.0.0.1").Return(nil)
 	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
 
 	req := dto.LoginRequest{
@@ -69,24 +117,33 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 		Password: "wrongpassword",
 	}
 
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, "10.0.0.1")
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
 	assert.Nil(t, result)
 	mockUserRepo.AssertExpectations(t)
+	mockLockout.AssertExpectations(t)
+	mockLockout.AssertNotCalled(t, "RecordSuccess", mock.Anything)
 }
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
-			Secret:      "test-secret",
-			ExpiryHours: 24,
+			Secret:     "test-secret",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
 		},
 	}
-	authService := NewAuthService(mockUserRepo, cfg)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
 
+	mockLockout.On("Check", "nonexistent@example.com", "10.0.0.1").Return(nil)
+	mockLockout.On("RecordFailure", "nonexistent@example.com", "10.0.0.1").Return(nil)
 	mockUserRepo.On("FindByEmail", "nonexistent@example.com").Return(nil, apperror.ErrNotFound)
 
 	req := dto.LoginRequest{
@@ -94,10 +151,403 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 		Password: "password123",
 	}
 
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, "10.0.0.1")
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
 	assert.Nil(t, result)
 	mockUserRepo.AssertExpectations(t)
+	mockLockout.AssertExpectations(t)
+}
+
+func TestAuthService_Login_Locked(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+	locked := &apperror.LockedError{RetryAfter: 90 * time.Second}
+	mockLockout.On("Check", "test@example.com", "10.0.0.1").Return(locked)
+
+	result, err := authService.Login(dto.LoginRequest{Email: "test@example.com", Password: "password123"}, "10.0.0.1")
+
+	assert.ErrorIs(t, err, apperror.ErrTooManyAttempts)
+	assert.Nil(t, result)
+	mockUserRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	mockLockout.AssertNotCalled(t, "RecordFailure", mock.Anything, mock.Anything)
+}
+
+func newTwoFactorTestConfig() *config.Config {
+	return &config.Config{
+		JWT: config.JWTConfig{
+			Secret:     "test-secret",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
+		},
+	}
+}
+
+func signTestToken(t *testing.T, cfg *config.Config, purpose string, user *model.User) string {
+	t.Helper()
+	token, err := (&authService{keys: testKeys, cfg: cfg}).signPurposeToken(purpose, user, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, newTwoFactorTestConfig())
+
+	now := time.Now()
+	user := &model.User{ID: 1, Email: "admin@example.com", Role: model.RoleAdmin, EmailVerifiedAt: &now, TOTPEnabledAt: &now}
+	_ = user.HashPassword("password123")
+
+	mockLockout.On("Check", "admin@example.com", "10.0.0.1").Return(nil)
+	mockUserRepo.On("FindByEmail", "admin@example.com").Return(user, nil)
+
+	result, err := authService.Login(dto.LoginRequest{Email: "admin@example.com", Password: "password123"}, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired)
+	assert.False(t, result.TwoFactorSetupRequired)
+	assert.NotEmpty(t, result.ChallengeToken)
+	assert.Empty(t, result.Token)
+	assert.Empty(t, result.RefreshToken)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockLockout.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestAuthService_Login_TwoFactorSetupRequired(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, newTwoFactorTestConfig())
+
+	now := time.Now()
+	user := &model.User{ID: 1, Email: "admin@example.com", Role: model.RoleAdmin, EmailVerifiedAt: &now}
+	_ = user.HashPassword("password123")
+
+	mockLockout.On("Check", "admin@example.com", "10.0.0.1").Return(nil)
+	mockUserRepo.On("FindByEmail", "admin@example.com").Return(user, nil)
+	mockTwoFactor.On("IsRequired", user).Return(true)
+
+	result, err := authService.Login(dto.LoginRequest{Email: "admin@example.com", Password: "password123"}, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired)
+	assert.True(t, result.TwoFactorSetupRequired)
+	assert.Empty(t, result.Token)
+
+	// the challenge token is what unlocks enrolment
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	setup := &dto.TwoFactorSetupResponse{Secret: "SECRET", OTPAuthURI: "otpauth://totp/x"}
+	mockTwoFactor.On("BeginSetup", uint(1)).Return(setup, nil)
+
+	got, err := authService.SetupTwoFactor(dto.TwoFactorChallengeRequest{ChallengeToken: result.ChallengeToken})
+
+	assert.NoError(t, err)
+	assert.Equal(t, setup, got)
+
+	// confirming the first code completes the login and hands out recovery codes
+	mockTwoFactor.On("ConfirmSetup", uint(1), "123456").
+		Return(&dto.RecoveryCodesResponse{RecoveryCodes: []string{"aaaaa-bbbbb"}}, nil)
+	mockLockout.On("RecordSuccess", "admin@example.com").Return(nil)
+	mockRefreshRepo.On("Create", mock.Anything).Return(nil)
+
+	final, err := authService.LoginTwoFactor(dto.LoginTwoFactorRequest{ChallengeToken: result.ChallengeToken, Code: "123456"}, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, final.Token)
+	assert.Equal(t, []string{"aaaaa-bbbbb"}, final.RecoveryCodes)
+}
+
+func TestAuthService_LoginTwoFactor_Success(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	cfg := newTwoFactorTestConfig()
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
+
+	now := time.Now()
+	user := &model.User{ID: 1, Email: "admin@example.com", Role: model.RoleAdmin, EmailVerifiedAt: &now, TOTPEnabledAt: &now}
+	challenge := signTestToken(t, cfg, purposeLoginTwoFactor, user)
+
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockLockout.On("Check", "admin@example.com", "10.0.0.1").Return(nil)
+	mockTwoFactor.On("Verify", user, "654321").Return(nil)
+	mockLockout.On("RecordSuccess", "admin@example.com").Return(nil)
+	mockRefreshRepo.On("Create", mock.Anything).Return(nil)
+
+	result, err := authService.LoginTwoFactor(dto.LoginTwoFactorRequest{ChallengeToken: challenge, Code: "654321"}, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.Empty(t, result.RecoveryCodes)
+	mockLockout.AssertExpectations(t)
+}
+
+func TestAuthService_LoginTwoFactor_WrongCodeCountsAsFailure(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	cfg := newTwoFactorTestConfig()
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
+
+	now := time.Now()
+	user := &model.User{ID: 1, Email: "admin@example.com", EmailVerifiedAt: &now, TOTPEnabledAt: &now}
+	challenge := signTestToken(t, cfg, purposeLoginTwoFactor, user)
+
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockLockout.On("Check", "admin@example.com", "10.0.0.1").Return(nil)
+	mockTwoFactor.On("Verify", user, "000000").Return(apperror.ErrInvalidOTP)
+	mockLockout.On("RecordFailure", "admin@example.com", "10.0.0.1").Return(nil)
+
+	result, err := authService.LoginTwoFactor(dto.LoginTwoFactorRequest{ChallengeToken: challenge, Code: "000000"}, "10.0.0.1")
+
+	assert.Equal(t, apperror.ErrInvalidOTP, err)
+	assert.Nil(t, result)
+	mockLockout.AssertExpectations(t)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthService_LoginTwoFactor_RejectsOtherTokens(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	cfg := newTwoFactorTestConfig()
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
+
+	user := &model.User{ID: 1, Email: "admin@example.com"}
+	verifyToken := signTestToken(t, cfg, purposeVerifyEmail, user)
+
+	result, err := authService.LoginTwoFactor(dto.LoginTwoFactorRequest{ChallengeToken: verifyToken, Code: "123456"}, "10.0.0.1")
+
+	assert.Equal(t, apperror.ErrInvalidToken, err)
+	assert.Nil(t, result)
+	mockTwoFactor.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Logout_RevokesFamily(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+	stored := &model.RefreshToken{ID: 1, UserID: 1, FamilyID: "family-1"}
+	mockRefreshRepo.On("FindByHash", securetoken.Hash("plain-token")).Return(stored, nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1").Return(nil)
+
+	err := authService.Logout(dto.LogoutRequest{RefreshToken: "plain-token"})
+
+	assert.NoError(t, err)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Logout_UnknownToken(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+	mockRefreshRepo.On("FindByHash", mock.Anything).Return(nil, apperror.ErrNotFound)
+
+	err := authService.Logout(dto.LogoutRequest{RefreshToken: "unknown"})
+
+	assert.Equal(t, apperror.ErrInvalidToken, err)
+	mockRefreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything)
+}
+
+func TestAuthService_ValidateSession(t *testing.T) {
+	issuedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := issuedAt.Add(2 * time.Second)
+	changedBefore := issuedAt.Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		userErr   error
+		validFrom *time.Time
+		active    bool
+		wantErr   error
+	}{
+		{"active session", "family-1", nil, nil, true, nil},
+		{"revoked session", "family-1", nil, nil, false, apperror.ErrInvalidToken},
+		{"deleted user", "family-1", apperror.ErrNotFound, nil, true, apperror.ErrInvalidToken},
+		{"token without session", "", nil, nil, true, apperror.ErrInvalidToken},
+		{"issued before credential change", "family-1", nil, &changedAt, true, apperror.ErrInvalidToken},
+		{"issued after credential change", "family-1", nil, &changedBefore, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.MockUserRepository)
+			mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+			mockMailer := new(mocks.MockMailer)
+			mockLockout := new(mocks.MockLockoutService)
+			mockTwoFactor := new(mocks.MockTwoFactorService)
+			authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+			if tt.userErr != nil {
+				mockUserRepo.On("FindByID", uint(1)).Return(nil, tt.userErr)
+			} else {
+				mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, TokensValidAfter: tt.validFrom}, nil)
+			}
+			mockRefreshRepo.On("IsFamilyActive", tt.sessionID).Return(tt.active, nil)
+
+			err := authService.ValidateSession(1, tt.sessionID, issuedAt)
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestAuthService_Login_EmailNotVerified(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+	user := &model.User{ID: 1, Email: "new@example.com"}
+	_ = user.HashPassword("password123")
+
+	mockLockout.On("Check", "new@example.com", "10.0.0.1").Return(nil)
+	mockLockout.On("RecordSuccess", "new@example.com").Return(nil)
+	mockUserRepo.On("FindByEmail", "new@example.com").Return(user, nil)
+
+	result, err := authService.Login(dto.LoginRequest{Email: "new@example.com", Password: "password123"}, "10.0.0.1")
+
+	assert.Equal(t, apperror.ErrEmailNotVerified, err)
+	assert.Nil(t, result)
+	mockRefreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthService_Register_ForcesUserRoleAndVerifies(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	cfg := &config.Config{
+		AppBaseURL: "https://gifts.example.com",
+		JWT:        config.JWTConfig{Secret: "test-secret"},
+	}
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
+
+	var created *model.User
+	mockUserRepo.On("Create", mock.MatchedBy(func(u *model.User) bool {
+		return u.Role == model.RoleUser && u.EmailVerifiedAt == nil
+	})).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*model.User)
+		created.ID = 10
+	}).Return(nil)
+
+	var sent mailer.Message
+	mockMailer.On("Send", mock.MatchedBy(func(m mailer.Message) bool {
+		return m.To == "jane@example.com"
+	})).Run(func(args mock.Arguments) {
+		sent = args.Get(0).(mailer.Message)
+	}).Return(nil)
+
+	req := dto.CreateUserRequest{
+		Name:     "Jane",
+		Email:    "jane@example.com",
+		Password: "password123",
+		Role:     "admin",
+	}
+	result, err := authService.Register(1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user", result.Role)
+	mockMailer.AssertExpectations(t)
+
+	// the emailed link verifies the account
+	match := regexp.MustCompile(`https://gifts\.example\.com/verify-email\?token=(\S+)`).FindStringSubmatch(sent.Body)
+	if assert.Len(t, match, 2) {
+		token, _ := url.QueryUnescape(match[1])
+		mockUserRepo.On("FindByID", uint(10)).Return(created, nil)
+		mockUserRepo.On("MarkEmailVerified", uint(10), created.Email, mock.AnythingOfType("time.Time")).Return(nil)
+
+		verified, err := authService.VerifyEmail(dto.VerifyEmailRequest{Token: token})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(10), verified.ID)
+		mockUserRepo.AssertExpectations(t)
+	}
+}
+
+func TestAuthService_VerifyEmail_InvalidToken(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", AccessTTL: time.Minute}}
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, cfg)
+
+	result, err := authService.VerifyEmail(dto.VerifyEmailRequest{Token: "not-a-token"})
+
+	assert.Equal(t, apperror.ErrInvalidToken, err)
+	assert.Nil(t, result)
+}
+
+func TestAuthService_ResendVerification_UnknownEmail(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	mockLockout := new(mocks.MockLockoutService)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	authService := NewAuthService(nil, mockUserRepo, mockRefreshRepo, mockLockout, mockTwoFactor, mockMailer, testKeys, &config.Config{})
+
+	mockUserRepo.On("FindByEmail", "ghost@example.com").Return(nil, apperror.ErrNotFound)
+
+	err := authService.ResendVerification(dto.ResendVerificationRequest{Email: "ghost@example.com"})
+
+	assert.NoError(t, err)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestAuthService_Login_SuperAdminTwoFactorRequired(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockLockout := new(mocks.MockLockoutService)
+	twoFactor := NewTwoFactorService(nil, mockUserRepo, new(mocks.MockRecoveryCodeRepository), new(mocks.MockRoleRepository),
+		config.TwoFactorConfig{RequiredForAdmin: true})
+	authService := NewAuthService(nil, mockUserRepo, new(mocks.MockRefreshTokenRepository), mockLockout, twoFactor, new(mocks.MockMailer), testKeys, newTwoFactorTestConfig())
+
+	now := time.Now()
+	user := &model.User{ID: 1, Email: "root@example.com", Role: model.RoleSuperAdmin, EmailVerifiedAt: &now}
+	_ = user.HashPassword("password123")
+
+	mockLockout.On("Check", "root@example.com", "10.0.0.1").Return(nil)
+	mockUserRepo.On("FindByEmail", "root@example.com").Return(user, nil)
+
+	result, err := authService.Login(dto.LoginRequest{Email: "root@example.com", Password: "password123"}, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.True(t, result.TwoFactorRequired)
+	assert.True(t, result.TwoFactorSetupRequired)
+	assert.Empty(t, result.Token)
+	mockLockout.AssertNotCalled(t, "RecordSuccess", mock.Anything)
 }
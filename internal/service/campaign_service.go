@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+)
+
+// CampaignService manages promotional campaigns that discount the point
+// price of gifts for a while.
+type CampaignService interface {
+	GetAll(orgID uint) ([]dto.CampaignResponse, error)
+	Create(orgID uint, req dto.CampaignRequest) (*dto.CampaignResponse, error)
+	Update(orgID, id uint, req dto.CampaignRequest) (*dto.CampaignResponse, error)
+	Delete(orgID, id uint) error
+	// Report sums up the redemptions the campaign priced
+	Report(orgID, id uint) (*dto.CampaignReportResponse, error)
+}
+
+type campaignService struct {
+	campaignRepo repository.CampaignRepository
+	giftRepo     repository.GiftRepository
+	now          func() time.Time
+}
+
+func NewCampaignService(campaignRepo repository.CampaignRepository, giftRepo repository.GiftRepository) CampaignService {
+	return &campaignService{campaignRepo, giftRepo, time.Now}
+}
+
+func (s *campaignService) GetAll(orgID uint) ([]dto.CampaignResponse, error) {
+	campaigns, err := s.campaignRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	res := make([]dto.CampaignResponse, len(campaigns))
+	for i, c := range campaigns {
+		res[i] = dto.ToCampaignResponse(c, now)
+	}
+	return res, nil
+}
+
+func (s *campaignService) Create(orgID uint, req dto.CampaignRequest) (*dto.CampaignResponse, error) {
+	campaign := &model.Campaign{OrganisationID: orgID}
+	if err := s.apply(campaign, req); err != nil {
+		return nil, err
+	}
+	if err := s.campaignRepo.Create(campaign); err != nil {
+		return nil, err
+	}
+	res := dto.ToCampaignResponse(*campaign, s.now())
+	return &res, nil
+}
+
+// Update reprices gifts at once; past redemptions keep the price they paid
+func (s *campaignService) Update(orgID, id uint, req dto.CampaignRequest) (*dto.CampaignResponse, error) {
+	campaign, err := s.campaignRepo.FindByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(campaign, req); err != nil {
+		return nil, err
+	}
+	if err := s.campaignRepo.Update(campaign); err != nil {
+		return nil, err
+	}
+	res := dto.ToCampaignResponse(*campaign, s.now())
+	return &res, nil
+}
+
+func (s *campaignService) Delete(orgID, id uint) error {
+	return s.campaignRepo.Delete(orgID, id)
+}
+
+func (s *campaignService) Report(orgID, id uint) (*dto.CampaignReportResponse, error) {
+	// scopes the report to the caller's organisation
+	if _, err := s.campaignRepo.FindByID(orgID, id); err != nil {
+		return nil, err
+	}
+	stats, err := s.campaignRepo.Stats(id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CampaignReportResponse{
+		CampaignID:       id,
+		Redemptions:      stats.Redemptions,
+		Quantity:         stats.Quantity,
+		Users:            stats.Users,
+		PointsSpent:      stats.PointsSpent,
+		PointsDiscounted: stats.PointsDiscounted,
+	}, nil
+}
+
+// apply validates the request and copies it onto the campaign
+func (s *campaignService) apply(campaign *model.Campaign, req dto.CampaignRequest) error {
+	if req.DiscountType == model.DiscountPercent && req.DiscountValue > 100 {
+		return fmt.Errorf("%w: a percent discount cannot exceed 100", apperror.ErrInvalidCampaign)
+	}
+
+	var categories []string
+	for _, c := range req.Categories {
+		if c = model.NormalizeCategory(c); c != "" && !slices.Contains(categories, c) {
+			categories = append(categories, c)
+		}
+	}
+	giftIDs := slices.Compact(slices.Sorted(slices.Values(req.GiftIDs)))
+	if len(giftIDs) == 0 && len(categories) == 0 {
+		return fmt.Errorf("%w: target at least one gift or category", apperror.ErrInvalidCampaign)
+	}
+	for _, id := range giftIDs {
+		_, err := s.giftRepo.FindByID(campaign.OrganisationID, id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fmt.Errorf("%w: gift %d does not exist", apperror.ErrInvalidCampaign, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	campaign.Name = req.Name
+	campaign.DiscountType = req.DiscountType
+	campaign.DiscountValue = req.DiscountValue
+	campaign.GiftIDs = giftIDs
+	campaign.Categories = categories
+	campaign.StartsAt = req.StartsAt
+	campaign.EndsAt = req.EndsAt
+	return nil
+}
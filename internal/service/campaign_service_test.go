@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCampaignService(now time.Time) (*campaignService, *mocks.MockCampaignRepository, *mocks.MockGiftRepository) {
+	campaigns, gifts := new(mocks.MockCampaignRepository), new(mocks.MockGiftRepository)
+	svc := NewCampaignService(campaigns, gifts).(*campaignService)
+	svc.now = func() time.Time { return now }
+	return svc, campaigns, gifts
+}
+
+func TestCampaignService_Create(t *testing.T) {
+	now := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	svc, campaigns, gifts := newTestCampaignService(now)
+	gifts.On("FindByID", uint(1), uint(4)).Return(&model.Gift{ID: 4}, nil)
+	campaigns.On("Create", mock.MatchedBy(func(c *model.Campaign) bool {
+		return c.OrganisationID == 1 &&
+			assert.ObjectsAreEqual(model.UintList{4}, c.GiftIDs) &&
+			assert.ObjectsAreEqual(model.StringList{"electronics"}, c.Categories)
+	})).Return(nil)
+
+	res, err := svc.Create(1, dto.CampaignRequest{
+		Name:          "Electronics weekend",
+		DiscountType:  model.DiscountPercent,
+		DiscountValue: 20,
+		GiftIDs:       []uint{4, 4},
+		Categories:    []string{" Electronics", "electronics"},
+		StartsAt:      now.Add(24 * time.Hour),
+		EndsAt:        now.Add(72 * time.Hour),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", res.Status)
+	campaigns.AssertExpectations(t)
+}
+
+func TestCampaignService_Create_Invalid(t *testing.T) {
+	now := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	base := dto.CampaignRequest{Name: "Sale", DiscountType: model.DiscountPercent, DiscountValue: 10, StartsAt: now, EndsAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name   string
+		modify func(r *dto.CampaignRequest)
+	}{
+		{"no target", func(r *dto.CampaignRequest) {}},
+		{"blank category only", func(r *dto.CampaignRequest) { r.Categories = []string{"  "} }},
+		{"percent over 100", func(r *dto.CampaignRequest) { r.Categories = []string{"books"}; r.DiscountValue = 120 }},
+		{"unknown gift", func(r *dto.CampaignRequest) { r.GiftIDs = []uint{99} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, campaigns, gifts := newTestCampaignService(now)
+			gifts.On("FindByID", uint(1), uint(99)).Return(nil, apperror.ErrNotFound)
+			req := base
+			tt.modify(&req)
+
+			_, err := svc.Create(1, req)
+
+			assert.ErrorIs(t, err, apperror.ErrInvalidCampaign)
+			campaigns.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestCampaignService_Report(t *testing.T) {
+	svc, campaigns, _ := newTestCampaignService(time.Now())
+	campaigns.On("FindByID", uint(1), uint(8)).Return(&model.Campaign{ID: 8}, nil)
+	campaigns.On("Stats", uint(8)).Return(&repository.CampaignStats{Redemptions: 3, Quantity: 4, Users: 2, PointsSpent: 3200, PointsDiscounted: 800}, nil)
+
+	res, err := svc.Report(1, 8)
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.CampaignReportResponse{CampaignID: 8, Redemptions: 3, Quantity: 4, Users: 2, PointsSpent: 3200, PointsDiscounted: 800}, *res)
+}
+
+func TestCampaignService_Report_OtherOrganisation(t *testing.T) {
+	svc, campaigns, _ := newTestCampaignService(time.Now())
+	campaigns.On("FindByID", uint(1), uint(8)).Return(nil, apperror.ErrNotFound)
+
+	_, err := svc.Report(1, 8)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	campaigns.AssertNotCalled(t, "Stats", mock.Anything)
+}
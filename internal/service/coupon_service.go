@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// CouponService manages the coupon codes users can enter at redemption
+type CouponService interface {
+	GetAll(orgID uint) ([]dto.CouponResponse, error)
+	Create(orgID uint, req dto.CouponRequest) (*dto.CouponResponse, error)
+	Update(orgID, id uint, req dto.CouponRequest) (*dto.CouponResponse, error)
+	Delete(orgID, id uint) error
+}
+
+type couponService struct {
+	couponRepo repository.CouponRepository
+	giftRepo   repository.GiftRepository
+	now        func() time.Time
+}
+
+func NewCouponService(couponRepo repository.CouponRepository, giftRepo repository.GiftRepository) CouponService {
+	return &couponService{couponRepo, giftRepo, time.Now}
+}
+
+func (s *couponService) GetAll(orgID uint) ([]dto.CouponResponse, error) {
+	coupons, err := s.couponRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	res := make([]dto.CouponResponse, len(coupons))
+	for i, c := range coupons {
+		res[i] = dto.ToCouponResponse(c, now)
+	}
+	return res, nil
+}
+
+func (s *couponService) Create(orgID uint, req dto.CouponRequest) (*dto.CouponResponse, error) {
+	coupon := &model.Coupon{OrganisationID: orgID}
+	if err := s.apply(coupon, req); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Create(coupon); err != nil {
+		return nil, err
+	}
+	res := dto.ToCouponResponse(*coupon, s.now())
+	return &res, nil
+}
+
+// Update applies to redemptions from now on; uses so far still count
+// against the new limits
+func (s *couponService) Update(orgID, id uint, req dto.CouponRequest) (*dto.CouponResponse, error) {
+	coupon, err := s.couponRepo.FindByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(coupon, req); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Update(coupon); err != nil {
+		return nil, err
+	}
+	res := dto.ToCouponResponse(*coupon, s.now())
+	return &res, nil
+}
+
+func (s *couponService) Delete(orgID, id uint) error {
+	return s.couponRepo.Delete(orgID, id)
+}
+
+// apply validates the request and copies it onto the coupon
+func (s *couponService) apply(coupon *model.Coupon, req dto.CouponRequest) error {
+	code := model.NormalizeCouponCode(req.Code)
+	if !couponCodePattern.MatchString(code) {
+		return fmt.Errorf("%w: code may only contain letters, digits, \"-\" and \"_\"", apperror.ErrInvalidCoupon)
+	}
+	switch req.DiscountType {
+	case model.DiscountPercent:
+		if req.DiscountValue < 1 || req.DiscountValue > 100 {
+			return fmt.Errorf("%w: a percent discount must be between 1 and 100", apperror.ErrInvalidCoupon)
+		}
+	case model.DiscountFixed:
+		if req.DiscountValue < 1 {
+			return fmt.Errorf("%w: a fixed discount must take off at least 1 point", apperror.ErrInvalidCoupon)
+		}
+	case model.DiscountFree:
+		if req.DiscountValue != 0 {
+			return fmt.Errorf("%w: a free coupon takes no discount_value", apperror.ErrInvalidCoupon)
+		}
+	}
+	if req.MaxUses > 0 && req.MaxUsesPerUser > req.MaxUses {
+		return fmt.Errorf("%w: max_uses_per_user cannot exceed max_uses", apperror.ErrInvalidCoupon)
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", apperror.ErrInvalidCoupon)
+	}
+
+	var categories []string
+	for _, c := range req.Categories {
+		if c = model.NormalizeCategory(c); c != "" && !slices.Contains(categories, c) {
+			categories = append(categories, c)
+		}
+	}
+	giftIDs := slices.Compact(slices.Sorted(slices.Values(req.GiftIDs)))
+	for _, id := range giftIDs {
+		_, err := s.giftRepo.FindByID(coupon.OrganisationID, id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return fmt.Errorf("%w: gift %d does not exist", apperror.ErrInvalidCoupon, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	coupon.Code = code
+	coupon.DiscountType = req.DiscountType
+	coupon.DiscountValue = req.DiscountValue
+	coupon.MaxUses = req.MaxUses
+	coupon.MaxUsesPerUser = req.MaxUsesPerUser
+	coupon.MinPoints = req.MinPoints
+	coupon.GiftIDs = giftIDs
+	coupon.Categories = categories
+	coupon.StartsAt = req.StartsAt
+	coupon.EndsAt = req.EndsAt
+	return nil
+}
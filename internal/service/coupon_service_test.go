@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCouponService_Create(t *testing.T) {
+	mockCouponRepo := new(mocks.MockCouponRepository)
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	svc := NewCouponService(mockCouponRepo, mockGiftRepo)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(7)).Return(&model.Gift{ID: 7}, nil)
+	mockCouponRepo.On("Create", mock.AnythingOfType("*model.Coupon")).Return(nil)
+
+	result, err := svc.Create(1, dto.CouponRequest{
+		Code:           " welcome50 ",
+		DiscountType:   model.DiscountPercent,
+		DiscountValue:  50,
+		MaxUses:        100,
+		MaxUsesPerUser: 1,
+		GiftIDs:        []uint{7, 7},
+		Categories:     []string{" Electronics "},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WELCOME50", result.Code)
+	assert.Equal(t, []uint{7}, result.GiftIDs)
+	assert.Equal(t, []string{"electronics"}, result.Categories)
+	assert.Equal(t, "active", result.Status)
+	mockGiftRepo.AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestCouponService_Create_Invalid(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name string
+		req  dto.CouponRequest
+	}{
+		{"code characters", dto.CouponRequest{Code: "WELCOME 50", DiscountType: model.DiscountFixed, DiscountValue: 10}},
+		{"percent over 100", dto.CouponRequest{Code: "HALF", DiscountType: model.DiscountPercent, DiscountValue: 150}},
+		{"fixed without value", dto.CouponRequest{Code: "NOTHING", DiscountType: model.DiscountFixed}},
+		{"free with value", dto.CouponRequest{Code: "FREEBIE", DiscountType: model.DiscountFree, DiscountValue: 10}},
+		{"per user over total", dto.CouponRequest{Code: "FEW", DiscountType: model.DiscountFree, MaxUses: 2, MaxUsesPerUser: 3}},
+		{"ends before start", dto.CouponRequest{Code: "SOON", DiscountType: model.DiscountFree, StartsAt: &start, EndsAt: &start}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCouponRepo := new(mocks.MockCouponRepository)
+			svc := NewCouponService(mockCouponRepo, new(mocks.MockGiftRepository))
+
+			result, err := svc.Create(1, tt.req)
+
+			assert.ErrorIs(t, err, apperror.ErrInvalidCoupon)
+			assert.Nil(t, result)
+			mockCouponRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestCouponService_Create_UnknownGift(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	svc := NewCouponService(new(mocks.MockCouponRepository), mockGiftRepo)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(9)).Return(nil, apperror.ErrNotFound)
+
+	result, err := svc.Create(1, dto.CouponRequest{Code: "GIFT9", DiscountType: model.DiscountFree, GiftIDs: []uint{9}})
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidCoupon)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+// eventClockSkew is how far in the future an event may claim to have occurred
+const eventClockSkew = 5 * time.Minute
+
+// EarningService manages earning rules and awards points for the business
+// events other systems send.
+type EarningService interface {
+	GetRules(orgID uint) ([]dto.EarningRuleResponse, error)
+	CreateRule(orgID uint, req dto.EarningRuleRequest) (*dto.EarningRuleResponse, error)
+	UpdateRule(orgID, id uint, req dto.EarningRuleRequest) (*dto.EarningRuleResponse, error)
+	DeleteRule(orgID, id uint) error
+	// Ingest records the event and credits what every matching rule awards.
+	// A redelivered event ID returns the original event marked as duplicate.
+	Ingest(orgID, apiKeyID uint, req dto.PointEventRequest) (*dto.PointEventResponse, error)
+}
+
+type earningService struct {
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	pointRepo   repository.PointRepository
+	earningRepo repository.EarningRepository
+	cfg         config.PointConfig
+	now         func() time.Time
+}
+
+func NewEarningService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	pointRepo repository.PointRepository,
+	earningRepo repository.EarningRepository,
+	cfg config.PointConfig,
+) EarningService {
+	return &earningService{db, userRepo, pointRepo, earningRepo, cfg, time.Now}
+}
+
+func (s *earningService) GetRules(orgID uint) ([]dto.EarningRuleResponse, error) {
+	rules, err := s.earningRepo.FindAllRules(orgID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.EarningRuleResponse, len(rules))
+	for i, r := range rules {
+		res[i] = dto.ToEarningRuleResponse(r)
+	}
+	return res, nil
+}
+
+func (s *earningService) CreateRule(orgID uint, req dto.EarningRuleRequest) (*dto.EarningRuleResponse, error) {
+	if err := validateEarningRule(req); err != nil {
+		return nil, err
+	}
+	rule := &model.EarningRule{OrganisationID: orgID}
+	applyEarningRule(rule, req)
+	if err := s.earningRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	res := dto.ToEarningRuleResponse(*rule)
+	return &res, nil
+}
+
+// UpdateRule applies to events received from now on; past awards stand
+func (s *earningService) UpdateRule(orgID, id uint, req dto.EarningRuleRequest) (*dto.EarningRuleResponse, error) {
+	if err := validateEarningRule(req); err != nil {
+		return nil, err
+	}
+	rule, err := s.earningRepo.FindRuleByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	applyEarningRule(rule, req)
+	if err := s.earningRepo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	res := dto.ToEarningRuleResponse(*rule)
+	return &res, nil
+}
+
+func (s *earningService) DeleteRule(orgID, id uint) error {
+	return s.earningRepo.DeleteRule(orgID, id)
+}
+
+func (s *earningService) Ingest(orgID, apiKeyID uint, req dto.PointEventRequest) (*dto.PointEventResponse, error) {
+	// most redeliveries are caught here; the unique index catches the rest
+	if res, err := s.findDuplicate(orgID, req.EventID); res != nil || err != nil {
+		return res, err
+	}
+
+	user, err := s.userRepo.FindByEmail(req.UserEmail)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if user == nil || user.OrganisationID != orgID || user.IsErased() {
+		return nil, fmt.Errorf("%w: no active user with this email in the organisation", apperror.ErrInvalidEvent)
+	}
+
+	now := s.now()
+	event := &model.PointEvent{
+		OrganisationID: orgID,
+		ExternalID:     req.EventID,
+		Type:           req.Type,
+		UserID:         user.ID,
+		Attributes:     req.Attributes,
+		OccurredAt:     now,
+	}
+	if req.OccurredAt != nil {
+		event.OccurredAt = *req.OccurredAt
+	}
+	if event.OccurredAt.After(now.Add(eventClockSkew)) {
+		return nil, fmt.Errorf("%w: occurred_at is in the future", apperror.ErrInvalidEvent)
+	}
+	if apiKeyID != 0 {
+		event.APIKeyID = &apiKeyID
+	}
+
+	rules, err := s.earningRepo.FindActiveRules(orgID, req.Type)
+	if err != nil {
+		return nil, err
+	}
+	matched := matchEarningRules(rules, event)
+
+	var awards []model.PointAward
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		// the user's lock serialises their awards, so the caps are exact
+		if _, err := s.pointRepo.LockBalances(tx, user.ID); err != nil {
+			return err
+		}
+
+		var lots []*model.PointLot
+		for _, m := range matched {
+			points := m.points
+			if m.rule.UserCap > 0 {
+				earned, err := s.earningRepo.AwardedSince(tx, m.rule.ID, user.ID, m.rule.UserCapSince(now))
+				if err != nil {
+					return err
+				}
+				points = min(points, m.rule.UserCap-earned)
+			}
+			if points <= 0 {
+				continue
+			}
+			awards = append(awards, model.PointAward{RuleID: m.rule.ID, UserID: user.ID, Points: points})
+			lots = append(lots, model.NewPointLot(orgID, user.ID, model.LedgerEarning, points, now, s.cfg.ValidMonths))
+			event.PointsAwarded += points
+		}
+
+		if err := s.earningRepo.CreateEvent(tx, event); err != nil {
+			return err
+		}
+		if len(lots) == 0 {
+			return nil
+		}
+		if err := s.pointRepo.CreditLots(tx, user.ID, lots); err != nil {
+			return err
+		}
+		for i := range awards {
+			awards[i].EventID = event.ID
+			awards[i].LotID = lots[i].ID
+			if err := s.earningRepo.CreateAward(tx, &awards[i]); err != nil {
+				return err
+			}
+			err := s.pointRepo.CreateEntry(tx, &model.PointLedgerEntry{
+				OrganisationID: orgID,
+				UserID:         user.ID,
+				Type:           model.LedgerEarning,
+				Points:         awards[i].Points,
+				LotID:          &lots[i].ID,
+				EventID:        &event.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, apperror.ErrDuplicateEntry) {
+		// a concurrent delivery of the same event won
+		res, err := s.findDuplicate(orgID, req.EventID)
+		if res == nil && err == nil {
+			return nil, apperror.ErrDuplicateEntry
+		}
+		return res, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event.Awards = awards
+	res := dto.ToPointEventResponse(*event, false)
+	return &res, nil
+}
+
+// findDuplicate returns the already received event, nil when there is none
+func (s *earningService) findDuplicate(orgID uint, externalID string) (*dto.PointEventResponse, error) {
+	event, err := s.earningRepo.FindEventByExternalID(orgID, externalID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := dto.ToPointEventResponse(*event, true)
+	return &res, nil
+}
+
+type earningMatch struct {
+	rule   *model.EarningRule
+	points int
+}
+
+// matchEarningRules returns every rule the event matches with its award
+// before the user cap; matching rules add up
+func matchEarningRules(rules []model.EarningRule, event *model.PointEvent) []earningMatch {
+	var matched []earningMatch
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(event) {
+			continue
+		}
+		if points := rule.Award(event); points > 0 {
+			matched = append(matched, earningMatch{rule, points})
+		}
+	}
+	return matched
+}
+
+func validateEarningRule(req dto.EarningRuleRequest) error {
+	if req.PointsPerUnit > 0 && req.UnitAttribute == "" {
+		return fmt.Errorf("%w: points_per_unit needs a unit_attribute", apperror.ErrInvalidRule)
+	}
+	if req.Points == 0 && req.PointsPerUnit == 0 {
+		return fmt.Errorf("%w: the rule awards no points", apperror.ErrInvalidRule)
+	}
+	if req.UserCapDays > 0 && req.UserCap == 0 {
+		return fmt.Errorf("%w: user_cap_days needs a user_cap", apperror.ErrInvalidRule)
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", apperror.ErrInvalidRule)
+	}
+	for name, value := range req.Conditions {
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return fmt.Errorf("%w: condition %q must be a string, number or boolean", apperror.ErrInvalidRule, name)
+		}
+	}
+	return nil
+}
+
+func applyEarningRule(rule *model.EarningRule, req dto.EarningRuleRequest) {
+	rule.Name = req.Name
+	rule.EventType = req.EventType
+	rule.Conditions = req.Conditions
+	rule.Points = req.Points
+	rule.UnitAttribute = req.UnitAttribute
+	rule.PointsPerUnit = req.PointsPerUnit
+	rule.Multiplier = req.RuleMultiplier()
+	rule.MaxPerEvent = req.MaxPerEvent
+	rule.UserCap = req.UserCap
+	rule.UserCapDays = req.UserCapDays
+	rule.StartsAt = req.StartsAt
+	rule.EndsAt = req.EndsAt
+	rule.Active = req.IsActive()
+}
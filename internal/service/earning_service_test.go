@@ -0,0 +1,167 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEarningService(now time.Time) (*earningService, *mocks.MockUserRepository, *mocks.MockEarningRepository) {
+	users, earning := new(mocks.MockUserRepository), new(mocks.MockEarningRepository)
+	svc := NewEarningService(nil, users, new(mocks.MockPointRepository), earning, config.PointConfig{ValidMonths: 12}).(*earningService)
+	svc.now = func() time.Time { return now }
+	return svc, users, earning
+}
+
+func TestMatchEarningRules(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	rules := []model.EarningRule{
+		{ID: 1, EventType: "purchase", Active: true, UnitAttribute: "amount", PointsPerUnit: 0.5, Multiplier: 1},
+		{ID: 2, EventType: "purchase", Active: true, Points: 100, Multiplier: 2, StartsAt: &start, EndsAt: &end},
+		{ID: 3, EventType: "purchase", Active: true, Points: 50, Multiplier: 1, Conditions: model.JSONMap{"channel": "store"}},
+		{ID: 4, EventType: "purchase", Active: true, UnitAttribute: "amount", PointsPerUnit: 10, Multiplier: 1, MaxPerEvent: 300},
+		{ID: 5, EventType: "purchase", Active: false, Points: 1000, Multiplier: 1},
+		{ID: 6, EventType: "training_completed", Active: true, Points: 200, Multiplier: 1},
+	}
+
+	tests := []struct {
+		name  string
+		event model.PointEvent
+		want  map[uint]int
+	}{
+		{
+			name: "per unit, window, condition and cap",
+			event: model.PointEvent{Type: "purchase", OccurredAt: start.Add(time.Hour),
+				Attributes: model.JSONMap{"amount": 45.0, "channel": "store"}},
+			want: map[uint]int{1: 22, 2: 200, 3: 50, 4: 300},
+		},
+		{
+			name: "outside window and condition not met",
+			event: model.PointEvent{Type: "purchase", OccurredAt: end,
+				Attributes: model.JSONMap{"amount": 10.0, "channel": "online"}},
+			want: map[uint]int{1: 5, 4: 100},
+		},
+		{
+			name:  "missing unit attribute awards nothing per unit",
+			event: model.PointEvent{Type: "purchase", OccurredAt: end, Attributes: model.JSONMap{"amount": "ten"}},
+			want:  map[uint]int{},
+		},
+		{
+			name:  "other event type",
+			event: model.PointEvent{Type: "training_completed", OccurredAt: end},
+			want:  map[uint]int{6: 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[uint]int{}
+			for _, m := range matchEarningRules(rules, &tt.event) {
+				got[m.rule.ID] = m.points
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateEarningRule(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     dto.EarningRuleRequest
+		wantErr bool
+	}{
+		{"fixed points", dto.EarningRuleRequest{Points: 100}, false},
+		{"per unit", dto.EarningRuleRequest{UnitAttribute: "amount", PointsPerUnit: 1}, false},
+		{"no points", dto.EarningRuleRequest{}, true},
+		{"per unit without attribute", dto.EarningRuleRequest{PointsPerUnit: 1}, true},
+		{"cap window without cap", dto.EarningRuleRequest{Points: 10, UserCapDays: 30}, true},
+		{"ends before start", dto.EarningRuleRequest{Points: 10, StartsAt: &start, EndsAt: &start}, true},
+		{"nested condition", dto.EarningRuleRequest{Points: 10, Conditions: map[string]interface{}{"a": []interface{}{1.0}}}, true},
+		{"scalar conditions", dto.EarningRuleRequest{Points: 10, Conditions: map[string]interface{}{"a": "x", "b": 1.0, "c": true}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEarningRule(tt.req)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperror.ErrInvalidRule)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEarningService_CreateRule_Defaults(t *testing.T) {
+	svc, _, earning := newTestEarningService(time.Now())
+	earning.On("CreateRule", mock.MatchedBy(func(r *model.EarningRule) bool {
+		return r.OrganisationID == 10 && r.Multiplier == 1 && r.Active
+	})).Return(nil)
+
+	res, err := svc.CreateRule(10, dto.EarningRuleRequest{Name: "Anniversary", EventType: "work_anniversary", Points: 500})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, res.Multiplier)
+	assert.True(t, res.Active)
+	assert.Equal(t, map[string]interface{}{}, res.Conditions)
+}
+
+func TestEarningService_Ingest_Duplicate(t *testing.T) {
+	svc, users, earning := newTestEarningService(time.Now())
+	earning.On("FindEventByExternalID", uint(10), "evt-1").Return(&model.PointEvent{
+		ID: 3, ExternalID: "evt-1", PointsAwarded: 40,
+		Awards: []model.PointAward{{RuleID: 1, Points: 40}},
+	}, nil)
+
+	res, err := svc.Ingest(10, 2, dto.PointEventRequest{EventID: "evt-1", Type: "purchase", UserEmail: "ann@example.com"})
+
+	require.NoError(t, err)
+	assert.True(t, res.Duplicate)
+	assert.Equal(t, 40, res.PointsAwarded)
+	assert.Equal(t, []dto.PointAwardResponse{{RuleID: 1, Points: 40}}, res.Awards)
+	users.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+func TestEarningService_Ingest_InvalidEvent(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user *model.User
+		at   *time.Time
+	}{
+		{"unknown user", nil, nil},
+		{"user of another organisation", &model.User{ID: 1, OrganisationID: 20}, nil},
+		{"erased user", &model.User{ID: 1, OrganisationID: 10, ErasedAt: &now}, nil},
+		{"occurred in the future", &model.User{ID: 1, OrganisationID: 10}, &future},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, users, earning := newTestEarningService(now)
+			earning.On("FindEventByExternalID", uint(10), "evt-1").Return(nil, apperror.ErrNotFound)
+			if tt.user == nil {
+				users.On("FindByEmail", "ann@example.com").Return(nil, apperror.ErrNotFound)
+			} else {
+				users.On("FindByEmail", "ann@example.com").Return(tt.user, nil)
+			}
+
+			_, err := svc.Ingest(10, 2, dto.PointEventRequest{EventID: "evt-1", Type: "purchase", UserEmail: "ann@example.com", OccurredAt: tt.at})
+
+			assert.ErrorIs(t, err, apperror.ErrInvalidEvent)
+			earning.AssertNotCalled(t, "FindActiveRules", mock.Anything, mock.Anything)
+		})
+	}
+}
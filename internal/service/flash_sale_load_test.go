@@ -0,0 +1,188 @@
+//go:build integration
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Run with `make test-load` against a disposable database configured through
+// the usual DB_* or DATABASE_URL variables. Every run creates its own
+// organisation, so runs do not interfere with each other.
+
+const (
+	loadSaleQuantity = 50
+	loadUsers        = 400
+	loadGiftStock    = 1000
+	loadGiftPoint    = 10
+)
+
+func TestFlashSaleLoad_NoOversell(t *testing.T) {
+	db := openLoadTestDB(t)
+	orgID, giftID, userIDs := seedFlashSaleLoad(t, db)
+
+	giftRepo := repository.NewGiftRepository(db)
+	pointRepo := repository.NewPointRepository(db)
+	flashSaleRepo := repository.NewFlashSaleRepository(db)
+	flashSales := NewFlashSaleService(db, flashSaleRepo, giftRepo)
+	redemptions := NewRedemptionService(db,
+		repository.NewUserRepository(db), giftRepo, repository.NewRedemptionRepository(db),
+		repository.NewRatingRepository(db), repository.NewTierRepository(db), pointRepo,
+		repository.NewCampaignRepository(db), flashSaleRepo, repository.NewCouponRepository(db))
+
+	sale, err := flashSales.Create(orgID, dto.FlashSaleRequest{
+		GiftID:   giftID,
+		Quantity: loadSaleQuantity,
+		StartsAt: time.Now().Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	// every user fires twice at once to exercise the per-user cap too
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		won      = map[uint]int{}
+		unexpect []error
+	)
+	start := make(chan struct{})
+	for _, userID := range userIDs {
+		for range 2 {
+			wg.Add(1)
+			go func(userID uint) {
+				defer wg.Done()
+				<-start
+				_, err := redemptions.Redeem(orgID, userID, giftID, dto.RedemptionRequest{Quantity: 1})
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err == nil:
+					won[userID]++
+				case errors.Is(err, apperror.ErrSoldOut), errors.Is(err, apperror.ErrFlashSaleLimit):
+				default:
+					unexpect = append(unexpect, err)
+				}
+			}(userID)
+		}
+	}
+	began := time.Now()
+	close(start)
+	wg.Wait()
+	t.Logf("%d redemption attempts took %s", 2*len(userIDs), time.Since(began))
+
+	require.Empty(t, unexpect)
+	total := 0
+	for userID, n := range won {
+		assert.Equalf(t, 1, n, "user %d redeemed more than once", userID)
+		total += n
+	}
+	assert.LessOrEqual(t, total, loadSaleQuantity, "oversold")
+
+	// units briefly held by claims that rolled back may be left over; they
+	// must still be there to sell, never lost
+	for _, userID := range userIDs {
+		if total == loadSaleQuantity {
+			break
+		}
+		if won[userID] > 0 {
+			continue
+		}
+		_, err := redemptions.Redeem(orgID, userID, giftID, dto.RedemptionRequest{Quantity: 1})
+		require.NoError(t, err)
+		total++
+	}
+	assert.Equal(t, loadSaleQuantity, total)
+
+	var claimed, linked, users int64
+	require.NoError(t, db.Raw("SELECT COUNT(*), COUNT(redemption_id), COUNT(DISTINCT user_id) FROM flash_sale_units "+
+		"WHERE flash_sale_id = ? AND user_id IS NOT NULL", sale.ID).Row().Scan(&claimed, &linked, &users))
+	assert.EqualValues(t, loadSaleQuantity, claimed)
+	assert.EqualValues(t, loadSaleQuantity, linked)
+	assert.EqualValues(t, loadSaleQuantity, users)
+
+	var redeemed, spent int64
+	require.NoError(t, db.Raw("SELECT COUNT(*), COALESCE(SUM(total_point), 0) FROM redemptions WHERE gift_id = ?", giftID).
+		Row().Scan(&redeemed, &spent))
+	assert.EqualValues(t, loadSaleQuantity, redeemed)
+	assert.EqualValues(t, loadSaleQuantity*loadGiftPoint, spent)
+
+	// the sale only ever took its reservation out of the gift's stock
+	var stock int
+	require.NoError(t, db.Raw("SELECT stock FROM gifts WHERE id = ?", giftID).Row().Scan(&stock))
+	assert.Equal(t, loadGiftStock-loadSaleQuantity, stock)
+
+	_, err = redemptions.Redeem(orgID, userIDs[len(userIDs)-1], giftID, dto.RedemptionRequest{Quantity: 1})
+	assert.True(t, errors.Is(err, apperror.ErrSoldOut) || errors.Is(err, apperror.ErrFlashSaleLimit))
+}
+
+// openLoadTestDB connects and migrates, skipping the test without a database
+func openLoadTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.Load()
+
+	db, err := gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Skipf("no database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	if err := sqlDB.Ping(); err != nil {
+		t.Skipf("no database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(50)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	m, err := migrate.New("file://../../migrations", cfg.Database.MigrationURL())
+	require.NoError(t, err)
+	defer m.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+	return db
+}
+
+// seedFlashSaleLoad creates an organisation with a gift and users who can
+// each afford it
+func seedFlashSaleLoad(t *testing.T, db *gorm.DB) (uint, uint, []uint) {
+	t.Helper()
+	run := time.Now().UnixNano()
+
+	org := model.Organisation{Name: "Flash sale load", Slug: fmt.Sprintf("flash-load-%d", run)}
+	require.NoError(t, db.Create(&org).Error)
+
+	gift := model.Gift{OrganisationID: org.ID, Name: "Limited drop", Point: loadGiftPoint, Stock: loadGiftStock}
+	require.NoError(t, db.Create(&gift).Error)
+
+	pointRepo := repository.NewPointRepository(db)
+	userIDs := make([]uint, loadUsers)
+	for i := range userIDs {
+		user := model.User{
+			OrganisationID: org.ID,
+			Name:           fmt.Sprintf("Buyer %d", i),
+			Email:          fmt.Sprintf("buyer-%d-%d@load.test", run, i),
+			Password:       "x",
+		}
+		require.NoError(t, db.Create(&user).Error)
+		lot := model.NewPointLot(org.ID, user.ID, model.LedgerOpeningBalance, 10*loadGiftPoint, time.Now(), 0)
+		require.NoError(t, pointRepo.Credit(lot))
+		userIDs[i] = user.ID
+	}
+	return org.ID, gift.ID, userIDs
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+// FlashSaleService manages flash sales: limited drops of a gift sold one unit
+// per user from a start time.
+type FlashSaleService interface {
+	GetAll(orgID uint) ([]dto.FlashSaleResponse, error)
+	// Create reserves the sale quantity out of the gift's stock
+	Create(orgID uint, req dto.FlashSaleRequest) (*dto.FlashSaleResponse, error)
+	// Delete stops the sale and returns its unclaimed units to the gift's stock
+	Delete(orgID, id uint) error
+	// ReleaseEnded returns the unclaimed units of every ended sale to their
+	// gifts' stock; run by the scheduler
+	ReleaseEnded(ctx context.Context) error
+}
+
+type flashSaleService struct {
+	db            *gorm.DB
+	flashSaleRepo repository.FlashSaleRepository
+	giftRepo      repository.GiftRepository
+	now           func() time.Time
+}
+
+func NewFlashSaleService(db *gorm.DB, flashSaleRepo repository.FlashSaleRepository, giftRepo repository.GiftRepository) FlashSaleService {
+	return &flashSaleService{db, flashSaleRepo, giftRepo, time.Now}
+}
+
+func (s *flashSaleService) GetAll(orgID uint) ([]dto.FlashSaleResponse, error) {
+	sales, err := s.flashSaleRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	res := make([]dto.FlashSaleResponse, len(sales))
+	for i, sale := range sales {
+		res[i] = dto.ToFlashSaleResponse(sale, now)
+	}
+	return res, nil
+}
+
+func (s *flashSaleService) Create(orgID uint, req dto.FlashSaleRequest) (*dto.FlashSaleResponse, error) {
+	if req.EndsAt != nil && !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", apperror.ErrInvalidFlashSale)
+	}
+	now := s.now()
+	if req.EndsAt != nil && !req.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: ends_at is in the past", apperror.ErrInvalidFlashSale)
+	}
+
+	_, err := s.giftRepo.FindByID(orgID, req.GiftID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, fmt.Errorf("%w: gift %d does not exist", apperror.ErrInvalidFlashSale, req.GiftID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sale := &model.FlashSale{
+		OrganisationID: orgID,
+		GiftID:         req.GiftID,
+		Quantity:       req.Quantity,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+	}
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		// deducting locks the gift row, so concurrent creates for the gift
+		// check for overlaps one at a time
+		if err := s.giftRepo.DeductStock(tx, req.GiftID, req.Quantity); err != nil {
+			return err
+		}
+		existing, err := s.flashSaleRepo.FindByGift(tx, orgID, req.GiftID)
+		if err != nil {
+			return err
+		}
+		if err := checkOverlap(sale, existing, now); err != nil {
+			return err
+		}
+		return s.flashSaleRepo.Create(tx, sale)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToFlashSaleResponse(*sale, now)
+	return &res, nil
+}
+
+// checkOverlap rejects a sale that would run alongside another sale of the
+// gift that has not ended, since redemptions look up the one sale running
+func checkOverlap(sale *model.FlashSale, existing []model.FlashSale, now time.Time) error {
+	for i := range existing {
+		if !existing[i].EndedAt(now) && sale.Overlaps(&existing[i]) {
+			return fmt.Errorf("%w: overlaps flash sale %d of the gift", apperror.ErrInvalidFlashSale, existing[i].ID)
+		}
+	}
+	return nil
+}
+
+func (s *flashSaleService) Delete(orgID, id uint) error {
+	sale, err := s.flashSaleRepo.FindByID(orgID, id)
+	if err != nil {
+		return err
+	}
+	return repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		return s.flashSaleRepo.Delete(tx, sale)
+	})
+}
+
+func (s *flashSaleService) ReleaseEnded(ctx context.Context) error {
+	sales, err := s.flashSaleRepo.FindEndedUnsold(s.now())
+	if err != nil {
+		return err
+	}
+	units := 0
+	for i := range sales {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		released, err := s.flashSaleRepo.ReleaseUnsold(&sales[i])
+		if err != nil {
+			return err
+		}
+		units += released
+	}
+
+	if units > 0 {
+		log.Printf("flash sales: returned %d unclaimed units of %d ended sales to stock", units, len(sales))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFlashSaleService_Create_EndsBeforeStart(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	svc := NewFlashSaleService(nil, new(mocks.MockFlashSaleRepository), mockGiftRepo)
+
+	start := time.Now().Add(time.Hour)
+	end := start.Add(-time.Minute)
+	result, err := svc.Create(1, dto.FlashSaleRequest{GiftID: 7, Quantity: 10, StartsAt: start, EndsAt: &end})
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidFlashSale)
+	assert.Nil(t, result)
+	mockGiftRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestFlashSaleService_Create_UnknownGift(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	svc := NewFlashSaleService(nil, new(mocks.MockFlashSaleRepository), mockGiftRepo)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(7)).Return(nil, apperror.ErrNotFound)
+
+	result, err := svc.Create(1, dto.FlashSaleRequest{GiftID: 7, Quantity: 10, StartsAt: time.Now()})
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidFlashSale)
+	assert.Nil(t, result)
+}
+
+func TestFlashSaleService_CheckOverlap(t *testing.T) {
+	now := time.Now()
+	existing := []model.FlashSale{
+		// sold out, so it no longer blocks a new sale
+		{ID: 1, GiftID: 7, Quantity: 5, Claimed: 5, StartsAt: now.Add(-time.Hour)},
+		// open-ended and still selling
+		{ID: 2, GiftID: 7, Quantity: 5, Claimed: 1, StartsAt: now.Add(-time.Minute)},
+	}
+
+	err := checkOverlap(&model.FlashSale{GiftID: 7, Quantity: 10, StartsAt: now.Add(time.Hour)}, existing, now)
+	assert.ErrorIs(t, err, apperror.ErrInvalidFlashSale)
+	assert.Contains(t, err.Error(), "flash sale 2")
+
+	// a sale that ends before the running one started does not overlap it
+	before := now.Add(-2 * time.Minute)
+	err = checkOverlap(&model.FlashSale{GiftID: 7, Quantity: 10, StartsAt: now.Add(-time.Hour), EndsAt: &before}, existing[1:], now)
+	assert.NoError(t, err)
+}
+
+func TestFlashSaleService_ReleaseEnded(t *testing.T) {
+	mockFlashSaleRepo := new(mocks.MockFlashSaleRepository)
+	svc := NewFlashSaleService(nil, mockFlashSaleRepo, new(mocks.MockGiftRepository))
+
+	ended := []model.FlashSale{{ID: 1, GiftID: 7, Quantity: 5}, {ID: 2, GiftID: 8, Quantity: 3}}
+	mockFlashSaleRepo.On("FindEndedUnsold", mock.AnythingOfType("time.Time")).Return(ended, nil)
+	mockFlashSaleRepo.On("ReleaseUnsold", &ended[0]).Return(4, nil)
+	mockFlashSaleRepo.On("ReleaseUnsold", &ended[1]).Return(3, nil)
+
+	assert.NoError(t, svc.ReleaseEnded(context.Background()))
+	mockFlashSaleRepo.AssertNumberOfCalls(t, "ReleaseUnsold", 2)
+}
+
+func TestFlashSaleService_ReleaseEnded_StopsOnError(t *testing.T) {
+	mockFlashSaleRepo := new(mocks.MockFlashSaleRepository)
+	svc := NewFlashSaleService(nil, mockFlashSaleRepo, new(mocks.MockGiftRepository))
+
+	ended := []model.FlashSale{{ID: 1, GiftID: 7}, {ID: 2, GiftID: 8}}
+	mockFlashSaleRepo.On("FindEndedUnsold", mock.AnythingOfType("time.Time")).Return(ended, nil)
+	mockFlashSaleRepo.On("ReleaseUnsold", &ended[0]).Return(0, errors.New("db down"))
+
+	assert.Error(t, svc.ReleaseEnded(context.Background()))
+	mockFlashSaleRepo.AssertNumberOfCalls(t, "ReleaseUnsold", 1)
+}
+
+func TestFlashSale_Status(t *testing.T) {
+	now := time.Now()
+	end := now.Add(time.Hour)
+	tests := []struct {
+		name string
+		sale model.FlashSale
+		want string
+	}{
+		{"scheduled", model.FlashSale{Quantity: 5, StartsAt: now.Add(time.Minute)}, "scheduled"},
+		{"running", model.FlashSale{Quantity: 5, Claimed: 4, StartsAt: now.Add(-time.Minute), EndsAt: &end}, "running"},
+		{"sold out", model.FlashSale{Quantity: 5, Claimed: 5, StartsAt: now.Add(-time.Minute)}, "ended"},
+		{"past end", model.FlashSale{Quantity: 5, StartsAt: now.Add(-2 * time.Hour), EndsAt: &now}, "ended"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := dto.ToFlashSaleResponse(tt.sale, now)
+			assert.Equal(t, tt.want, res.Status)
+			assert.Equal(t, tt.sale.Quantity-tt.sale.Claimed, res.Remaining)
+		})
+	}
+}
@@ -1,39 +1,67 @@
 package service
 
 import (
+	"errors"
 	"math"
+	"time"
 
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gift-redemption/internal/repository"
 )
 
+// GiftViewer is who browses the catalogue. Tier-exclusive gifts are hidden
+// from users below the gift's tier unless AllTiers is set.
+type GiftViewer struct {
+	UserID   uint
+	AllTiers bool
+}
+
 type GiftService interface {
-	GetAll(query dto.PaginationQuery) ([]dto.GiftResponse, *response.Pagination, error)
-	GetByID(id uint) (*dto.GiftResponse, error)
-	Create(req dto.CreateGiftRequest) (*dto.GiftResponse, error)
-	Update(id uint, req dto.UpdateGiftRequest) (*dto.GiftResponse, error)
-	Patch(id uint, req dto.PatchGiftRequest) (*dto.GiftResponse, error)
-	Delete(id uint) error
+	GetAll(orgID uint, viewer GiftViewer, query dto.PaginationQuery) ([]dto.GiftResponse, *response.Pagination, error)
+	GetByID(orgID uint, viewer GiftViewer, id uint) (*dto.GiftResponse, error)
+	Create(orgID uint, req dto.CreateGiftRequest) (*dto.GiftResponse, error)
+	Update(orgID, id uint, req dto.UpdateGiftRequest) (*dto.GiftResponse, error)
+	Patch(orgID, id uint, req dto.PatchGiftRequest) (*dto.GiftResponse, error)
+	Delete(orgID, id uint) error
 }
 
 type giftService struct {
-	giftRepo repository.GiftRepository
+	giftRepo     repository.GiftRepository
+	userRepo     repository.UserRepository
+	tierRepo     repository.TierRepository
+	campaignRepo repository.CampaignRepository
+	now          func() time.Time
 }
 
-func NewGiftService(giftRepo repository.GiftRepository) GiftService {
-	return &giftService{giftRepo}
+func NewGiftService(
+	giftRepo repository.GiftRepository,
+	userRepo repository.UserRepository,
+	tierRepo repository.TierRepository,
+	campaignRepo repository.CampaignRepository,
+) GiftService {
+	return &giftService{giftRepo, userRepo, tierRepo, campaignRepo, time.Now}
 }
 
-func (s *giftService) GetAll(query dto.PaginationQuery) ([]dto.GiftResponse, *response.Pagination, error) {
+func (s *giftService) GetAll(orgID uint, viewer GiftViewer, query dto.PaginationQuery) ([]dto.GiftResponse, *response.Pagination, error) {
 	query.Normalize()
 
 	filter := repository.GiftFilter{
-		Page:    query.Page,
-		Limit:   query.Limit,
-		SortBy:  query.SortBy,
-		SortDir: query.SortDir,
+		OrganisationID: orgID,
+		Page:           query.Page,
+		Limit:          query.Limit,
+		SortBy:         query.SortBy,
+		SortDir:        query.SortDir,
+		AllTiers:       viewer.AllTiers,
+	}
+	if !viewer.AllTiers {
+		tierID, err := s.viewerTier(viewer)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter.ViewerTierID = tierID
 	}
 
 	gifts, total, err := s.giftRepo.FindAll(filter)
@@ -41,9 +69,9 @@ func (s *giftService) GetAll(query dto.PaginationQuery) ([]dto.GiftResponse, *re
 		return nil, nil, err
 	}
 
-	result := make([]dto.GiftResponse, len(gifts))
-	for i, g := range gifts {
-		result[i] = dto.ToGiftResponse(g)
+	result, err := s.toResponses(orgID, gifts...)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
@@ -57,39 +85,61 @@ func (s *giftService) GetAll(query dto.PaginationQuery) ([]dto.GiftResponse, *re
 	return result, pagination, nil
 }
 
-func (s *giftService) GetByID(id uint) (*dto.GiftResponse, error) {
-	gift, err := s.giftRepo.FindByID(id)
+func (s *giftService) GetByID(orgID uint, viewer GiftViewer, id uint) (*dto.GiftResponse, error) {
+	gift, err := s.giftRepo.FindByID(orgID, id)
 	if err != nil {
 		return nil, err
 	}
-	res := dto.ToGiftResponse(*gift)
-	return &res, nil
+	if gift.MinTierID != nil && !viewer.AllTiers {
+		tierID, err := s.viewerTier(viewer)
+		if err != nil {
+			return nil, err
+		}
+		tiers, err := s.tierRepo.FindAll(orgID)
+		if err != nil {
+			return nil, err
+		}
+		// hidden gifts do not exist for this viewer
+		if !model.TierAllows(tiers, tierID, gift.MinTierID) {
+			return nil, apperror.ErrNotFound
+		}
+	}
+	return s.toResponse(orgID, gift)
 }
 
-func (s *giftService) Create(req dto.CreateGiftRequest) (*dto.GiftResponse, error) {
+func (s *giftService) Create(orgID uint, req dto.CreateGiftRequest) (*dto.GiftResponse, error) {
+	if err := s.checkTier(orgID, req.MinTierID); err != nil {
+		return nil, err
+	}
+
 	gift := &model.Gift{
-		Name:         req.Name,
-		Description:  req.Description,
-		Point:        req.Point,
-		Stock:        req.Stock,
-		ImageURL:     req.ImageURL,
-		IsNew:        req.IsNew,
-		IsBestSeller: req.IsBestSeller,
+		OrganisationID: orgID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Point:          req.Point,
+		Stock:          req.Stock,
+		ImageURL:       req.ImageURL,
+		IsNew:          req.IsNew,
+		IsBestSeller:   req.IsBestSeller,
+		MinTierID:      req.MinTierID,
+		Category:       model.NormalizeCategory(req.Category),
 	}
 
 	if err := s.giftRepo.Create(gift); err != nil {
 		return nil, err
 	}
 
-	res := dto.ToGiftResponse(*gift)
-	return &res, nil
+	return s.toResponse(orgID, gift)
 }
 
-func (s *giftService) Update(id uint, req dto.UpdateGiftRequest) (*dto.GiftResponse, error) {
-	gift, err := s.giftRepo.FindByID(id)
+func (s *giftService) Update(orgID, id uint, req dto.UpdateGiftRequest) (*dto.GiftResponse, error) {
+	gift, err := s.giftRepo.FindByID(orgID, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkTier(orgID, req.MinTierID); err != nil {
+		return nil, err
+	}
 
 	gift.Name = req.Name
 	gift.Description = req.Description
@@ -98,17 +148,18 @@ func (s *giftService) Update(id uint, req dto.UpdateGiftRequest) (*dto.GiftRespo
 	gift.ImageURL = req.ImageURL
 	gift.IsNew = req.IsNew
 	gift.IsBestSeller = req.IsBestSeller
+	gift.MinTierID = req.MinTierID
+	gift.Category = model.NormalizeCategory(req.Category)
 
 	if err := s.giftRepo.Update(gift); err != nil {
 		return nil, err
 	}
 
-	res := dto.ToGiftResponse(*gift)
-	return &res, nil
+	return s.toResponse(orgID, gift)
 }
 
-func (s *giftService) Patch(id uint, req dto.PatchGiftRequest) (*dto.GiftResponse, error) {
-	gift, err := s.giftRepo.FindByID(id)
+func (s *giftService) Patch(orgID, id uint, req dto.PatchGiftRequest) (*dto.GiftResponse, error) {
+	gift, err := s.giftRepo.FindByID(orgID, id)
 	if err != nil {
 		return nil, err
 	}
@@ -135,15 +186,74 @@ func (s *giftService) Patch(id uint, req dto.PatchGiftRequest) (*dto.GiftRespons
 	if req.IsBestSeller != nil {
 		gift.IsBestSeller = *req.IsBestSeller
 	}
+	if req.Category != nil {
+		gift.Category = model.NormalizeCategory(*req.Category)
+	}
+	if req.MinTierID != nil {
+		gift.MinTierID = nil
+		if *req.MinTierID != 0 {
+			if err := s.checkTier(orgID, req.MinTierID); err != nil {
+				return nil, err
+			}
+			gift.MinTierID = req.MinTierID
+		}
+	}
 
 	if err := s.giftRepo.Update(gift); err != nil {
 		return nil, err
 	}
 
-	res := dto.ToGiftResponse(*gift)
-	return &res, nil
+	return s.toResponse(orgID, gift)
+}
+
+func (s *giftService) Delete(orgID, id uint) error {
+	return s.giftRepo.Delete(orgID, id)
+}
+
+// toResponses prices the gifts with the campaigns running now
+func (s *giftService) toResponses(orgID uint, gifts ...model.Gift) ([]dto.GiftResponse, error) {
+	now := s.now()
+	campaigns, err := s.campaignRepo.FindRunning(orgID, now)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.GiftResponse, len(gifts))
+	for i := range gifts {
+		res[i] = dto.ToGiftResponse(gifts[i])
+		res[i].ApplyCampaign(model.BestCampaign(campaigns, &gifts[i], now))
+	}
+	return res, nil
+}
+
+func (s *giftService) toResponse(orgID uint, gift *model.Gift) (*dto.GiftResponse, error) {
+	res, err := s.toResponses(orgID, *gift)
+	if err != nil {
+		return nil, err
+	}
+	return &res[0], nil
 }
 
-func (s *giftService) Delete(id uint) error {
-	return s.giftRepo.Delete(id)
+// viewerTier returns the tier of the browsing user; API keys and users
+// without a tier get nil
+func (s *giftService) viewerTier(viewer GiftViewer) (*uint, error) {
+	if viewer.UserID == 0 {
+		return nil, nil
+	}
+	user, err := s.userRepo.FindByID(viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return user.TierID, nil
+}
+
+// checkTier verifies a gift's minimum tier belongs to the organisation
+func (s *giftService) checkTier(orgID uint, tierID *uint) error {
+	if tierID == nil {
+		return nil
+	}
+	_, err := s.tierRepo.FindByID(orgID, *tierID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return apperror.ErrUnknownTier
+	}
+	return err
 }
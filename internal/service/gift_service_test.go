@@ -13,9 +13,16 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// noCampaigns is a campaign repository with nothing running
+func noCampaigns() *mocks.MockCampaignRepository {
+	campaigns := new(mocks.MockCampaignRepository)
+	campaigns.On("FindRunning", mock.Anything, mock.Anything).Return([]model.Campaign(nil), nil)
+	return campaigns
+}
+
 func TestGiftService_GetAll_Success(t *testing.T) {
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
 	gifts := []model.Gift{
 		{
@@ -39,10 +46,11 @@ func TestGiftService_GetAll_Success(t *testing.T) {
 	}
 
 	filter := repository.GiftFilter{
-		Page:    1,
-		Limit:   10,
-		SortBy:  "created_at",
-		SortDir: "desc",
+		OrganisationID: 1,
+		Page:           1,
+		Limit:          10,
+		SortBy:         "created_at",
+		SortDir:        "desc",
 	}
 
 	mockGiftRepo.On("FindAll", filter).Return(gifts, int64(2), nil)
@@ -54,7 +62,7 @@ func TestGiftService_GetAll_Success(t *testing.T) {
 		SortDir: "desc",
 	}
 
-	result, pagination, err := giftService.GetAll(query)
+	result, pagination, err := giftService.GetAll(1, GiftViewer{}, query)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
@@ -68,7 +76,7 @@ func TestGiftService_GetAll_Success(t *testing.T) {
 
 func TestGiftService_GetByID_Success(t *testing.T) {
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
 	gift := &model.Gift{
 		ID:           1,
@@ -80,9 +88,9 @@ func TestGiftService_GetByID_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockGiftRepo.On("FindByID", uint(1)).Return(gift, nil)
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(gift, nil)
 
-	result, err := giftService.GetByID(1)
+	result, err := giftService.GetByID(1, GiftViewer{}, 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -95,11 +103,11 @@ func TestGiftService_GetByID_Success(t *testing.T) {
 
 func TestGiftService_GetByID_NotFound(t *testing.T) {
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
-	mockGiftRepo.On("FindByID", uint(999)).Return(nil, apperror.ErrNotFound)
+	mockGiftRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
 
-	result, err := giftService.GetByID(999)
+	result, err := giftService.GetByID(1, GiftViewer{}, 999)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
@@ -109,7 +117,7 @@ func TestGiftService_GetByID_NotFound(t *testing.T) {
 
 func TestGiftService_Create_Success(t *testing.T) {
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
 	req := dto.CreateGiftRequest{
 		Name:         "New Gift",
@@ -124,7 +132,7 @@ func TestGiftService_Create_Success(t *testing.T) {
 		return g.Name == req.Name && g.Point == req.Point
 	})).Return(nil)
 
-	result, err := giftService.Create(req)
+	result, err := giftService.Create(1, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -134,7 +142,7 @@ func TestGiftService_Create_Success(t *testing.T) {
 
 func TestGiftService_Patch_Success(t *testing.T) {
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
 	existingGift := &model.Gift{
 		ID:           1,
@@ -153,10 +161,10 @@ func TestGiftService_Patch_Success(t *testing.T) {
 		IsNew: &isNew,
 	}
 
-	mockGiftRepo.On("FindByID", uint(1)).Return(existingGift, nil)
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(existingGift, nil)
 	mockGiftRepo.On("Update", existingGift).Return(nil)
 
-	result, err := giftService.Patch(1, req)
+	result, err := giftService.Patch(1, 1, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -181,7 +189,7 @@ func TestGiftService_StarRating_Rounding(t *testing.T) {
 	}
 
 	mockGiftRepo := new(mocks.MockGiftRepository)
-	giftService := NewGiftService(mockGiftRepo)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), noCampaigns())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -191,9 +199,9 @@ func TestGiftService_StarRating_Rounding(t *testing.T) {
 				AvgRating: tt.avgRating,
 			}
 
-			mockGiftRepo.On("FindByID", uint(1)).Return(gift, nil).Once()
+			mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(gift, nil).Once()
 
-			result, err := giftService.GetByID(1)
+			result, err := giftService.GetByID(1, GiftViewer{}, 1)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tt.wantRating, result.StarRating)
@@ -202,3 +210,106 @@ func TestGiftService_StarRating_Rounding(t *testing.T) {
 
 	mockGiftRepo.AssertExpectations(t)
 }
+
+func TestGiftService_GetAll_FiltersByViewerTier(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	giftService := NewGiftService(mockGiftRepo, mockUserRepo, new(mocks.MockTierRepository), noCampaigns())
+
+	silver := uint(3)
+	mockUserRepo.On("FindByID", uint(5)).Return(&model.User{ID: 5, TierID: &silver}, nil)
+	mockGiftRepo.On("FindAll", mock.MatchedBy(func(f repository.GiftFilter) bool {
+		return !f.AllTiers && f.ViewerTierID != nil && *f.ViewerTierID == silver
+	})).Return([]model.Gift{}, int64(0), nil)
+
+	_, _, err := giftService.GetAll(1, GiftViewer{UserID: 5}, dto.PaginationQuery{})
+
+	assert.NoError(t, err)
+	mockGiftRepo.AssertExpectations(t)
+}
+
+func TestGiftService_GetByID_TierExclusive(t *testing.T) {
+	silver, gold := uint(3), uint(4)
+	tiers := []model.Tier{{ID: silver, MinPoints: 1000}, {ID: gold, MinPoints: 5000}}
+
+	tests := []struct {
+		name    string
+		viewer  GiftViewer
+		tierID  *uint
+		wantErr error
+	}{
+		{"below tier", GiftViewer{UserID: 5}, &silver, apperror.ErrNotFound},
+		{"no tier", GiftViewer{UserID: 5}, nil, apperror.ErrNotFound},
+		{"at tier", GiftViewer{UserID: 5}, &gold, nil},
+		{"staff", GiftViewer{UserID: 5, AllTiers: true}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGiftRepo := new(mocks.MockGiftRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			mockTierRepo := new(mocks.MockTierRepository)
+			giftService := NewGiftService(mockGiftRepo, mockUserRepo, mockTierRepo, noCampaigns())
+
+			mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1, MinTierID: &gold}, nil)
+			mockUserRepo.On("FindByID", uint(5)).Return(&model.User{ID: 5, TierID: tt.tierID}, nil)
+			mockTierRepo.On("FindAll", uint(1)).Return(tiers, nil)
+
+			result, err := giftService.GetByID(1, tt.viewer, 1)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &gold, result.MinTierID)
+		})
+	}
+}
+
+func TestGiftService_Create_UnknownTier(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockTierRepo := new(mocks.MockTierRepository)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), mockTierRepo, noCampaigns())
+
+	tierID := uint(9)
+	mockTierRepo.On("FindByID", uint(1), tierID).Return(nil, apperror.ErrNotFound)
+
+	result, err := giftService.Create(1, dto.CreateGiftRequest{Name: "Lounge pass", Point: 100, Stock: 1, MinTierID: &tierID})
+
+	assert.ErrorIs(t, err, apperror.ErrUnknownTier)
+	assert.Nil(t, result)
+	mockGiftRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestGiftService_GetAll_CampaignPrice(t *testing.T) {
+	now := time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC)
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockCampaignRepo := new(mocks.MockCampaignRepository)
+	giftService := NewGiftService(mockGiftRepo, new(mocks.MockUserRepository), new(mocks.MockTierRepository), mockCampaignRepo).(*giftService)
+	giftService.now = func() time.Time { return now }
+
+	gifts := []model.Gift{
+		{ID: 1, Point: 1000, Category: "electronics"},
+		{ID: 2, Point: 1000, Category: "books"},
+		{ID: 3, Point: 150},
+	}
+	weekend := model.Campaign{ID: 8, Name: "Electronics weekend", DiscountType: model.DiscountPercent, DiscountValue: 20,
+		Categories: model.StringList{"electronics"}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(48 * time.Hour)}
+	fixed := model.Campaign{ID: 9, Name: "100 off", DiscountType: model.DiscountFixed, DiscountValue: 100,
+		GiftIDs: model.UintList{1, 3}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+	mockGiftRepo.On("FindAll", mock.Anything).Return(gifts, int64(3), nil)
+	mockCampaignRepo.On("FindRunning", uint(1), now).Return([]model.Campaign{fixed, weekend}, nil)
+
+	result, _, err := giftService.GetAll(1, GiftViewer{AllTiers: true}, dto.PaginationQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, result[0].Point)
+	assert.Equal(t, 800, result[0].EffectivePoint)
+	assert.Equal(t, uint(8), result[0].Campaign.ID)
+	assert.Equal(t, 1000, result[1].EffectivePoint)
+	assert.Nil(t, result[1].Campaign)
+	assert.Equal(t, 50, result[2].EffectivePoint)
+	assert.Equal(t, uint(9), result[2].Campaign.ID)
+}
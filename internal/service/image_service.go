@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/imageutil"
+	"github.com/gift-redemption/internal/pkg/storage"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	MaxGiftImages   = 10
+	MaxReviewPhotos = 5
+	thumbnailSide   = 320
+)
+
+type ImageService interface {
+	UploadGiftImages(orgID, uploaderID, giftID uint, files []dto.UploadFile) ([]dto.ImageResponse, error)
+	DeleteGiftImage(orgID, giftID, imageID uint) error
+	UploadReviewPhotos(orgID, userID, ratingID uint, files []dto.UploadFile) ([]dto.ImageResponse, error)
+}
+
+type imageService struct {
+	giftRepo   repository.GiftRepository
+	ratingRepo repository.RatingRepository
+	imageRepo  repository.ImageRepository
+	storage    storage.Storage
+	maxBytes   int64
+}
+
+func NewImageService(
+	giftRepo repository.GiftRepository,
+	ratingRepo repository.RatingRepository,
+	imageRepo repository.ImageRepository,
+	storage storage.Storage,
+	maxBytes int64,
+) ImageService {
+	return &imageService{giftRepo, ratingRepo, imageRepo, storage, maxBytes}
+}
+
+func (s *imageService) UploadGiftImages(orgID, uploaderID, giftID uint, files []dto.UploadFile) ([]dto.ImageResponse, error) {
+	gift, err := s.giftRepo.FindByID(orgID, giftID)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.upload(model.ImageOwnerGift, giftID, uploaderID, MaxGiftImages, files)
+	if err != nil {
+		return nil, err
+	}
+
+	// first gallery image becomes the cover when the gift has none
+	if gift.ImageURL == "" && len(images) > 0 {
+		if err := s.giftRepo.UpdateImageURL(giftID, images[0].URL); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.ToImageResponses(images), nil
+}
+
+func (s *imageService) DeleteGiftImage(orgID, giftID, imageID uint) error {
+	gift, err := s.giftRepo.FindByID(orgID, giftID)
+	if err != nil {
+		return err
+	}
+
+	image, err := s.imageRepo.FindByID(imageID)
+	if err != nil {
+		return err
+	}
+	if image.OwnerType != model.ImageOwnerGift || image.OwnerID != giftID {
+		return apperror.ErrNotFound
+	}
+
+	if err := s.imageRepo.Delete(imageID); err != nil {
+		return err
+	}
+	s.removeObjects(image.StorageKey, image.ThumbnailKey)
+
+	// move the cover to the next gallery image
+	if gift.ImageURL == image.URL {
+		cover := ""
+		for _, img := range gift.Images {
+			if img.ID != imageID {
+				cover = img.URL
+				break
+			}
+		}
+		return s.giftRepo.UpdateImageURL(giftID, cover)
+	}
+	return nil
+}
+
+func (s *imageService) UploadReviewPhotos(orgID, userID, ratingID uint, files []dto.UploadFile) ([]dto.ImageResponse, error) {
+	rating, err := s.ratingRepo.FindByID(orgID, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.IsHidden() {
+		return nil, apperror.ErrNotFound
+	}
+	if rating.UserID != userID {
+		return nil, apperror.ErrForbidden
+	}
+
+	images, err := s.upload(model.ImageOwnerRating, ratingID, userID, MaxReviewPhotos, files)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToImageResponses(images), nil
+}
+
+type preparedImage struct {
+	data      []byte
+	info      imageutil.Info
+	thumb     []byte
+	thumbType string
+}
+
+// upload validates every file before storing any of them, so a bad file
+// in the batch never leaves a partial gallery behind
+func (s *imageService) upload(ownerType string, ownerID, uploaderID uint, limit int, files []dto.UploadFile) ([]model.Image, error) {
+	if len(files) == 0 {
+		return nil, apperror.ErrUnsupportedFile
+	}
+
+	existing, err := s.imageRepo.CountByOwner(ownerType, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if int(existing)+len(files) > limit {
+		return nil, apperror.ErrTooManyFiles
+	}
+
+	prepared := make([]preparedImage, len(files))
+	for i, f := range files {
+		if int64(len(f.Data)) > s.maxBytes {
+			return nil, apperror.ErrFileTooLarge
+		}
+		info, err := imageutil.Sniff(f.Data)
+		if err != nil {
+			return nil, apperror.ErrUnsupportedFile
+		}
+		thumb, thumbType, err := imageutil.Thumbnail(f.Data, thumbnailSide)
+		if err != nil {
+			return nil, apperror.ErrUnsupportedFile
+		}
+		prepared[i] = preparedImage{f.Data, info, thumb, thumbType}
+	}
+
+	ctx := context.Background()
+	images := make([]model.Image, 0, len(prepared))
+	for i, p := range prepared {
+		token, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		image := model.Image{
+			OwnerType:    ownerType,
+			OwnerID:      ownerID,
+			StorageKey:   fmt.Sprintf("%s/%d/%s%s", ownerType, ownerID, token, p.info.Ext),
+			ThumbnailKey: fmt.Sprintf("%s/%d/%s_thumb%s", ownerType, ownerID, token, thumbExt(p.thumbType)),
+			ContentType:  p.info.ContentType,
+			SizeBytes:    int64(len(p.data)),
+			Width:        p.info.Width,
+			Height:       p.info.Height,
+			Position:     int(existing) + i,
+			UploadedBy:   uploaderID,
+		}
+
+		if image.URL, err = s.storage.Put(ctx, image.StorageKey, p.data, p.info.ContentType); err != nil {
+			return nil, fmt.Errorf("store image: %w", err)
+		}
+		if image.ThumbnailURL, err = s.storage.Put(ctx, image.ThumbnailKey, p.thumb, p.thumbType); err != nil {
+			s.removeObjects(image.StorageKey)
+			return nil, fmt.Errorf("store thumbnail: %w", err)
+		}
+		if err := s.imageRepo.Create(&image); err != nil {
+			s.removeObjects(image.StorageKey, image.ThumbnailKey)
+			return nil, err
+		}
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// removeObjects is best effort: an orphaned object is cheaper than a failed request
+func (s *imageService) removeObjects(keys ...string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			log.Printf("delete object %s: %v", key, err)
+		}
+	}
+}
+
+func thumbExt(contentType string) string {
+	if contentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageService_UploadGiftImages_Success(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	gift := &model.Gift{ID: 1, Name: "Gift"}
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(gift, nil)
+	mockImageRepo.On("CountByOwner", model.ImageOwnerGift, uint(1)).Return(int64(0), nil)
+	mockStorage.On("Put", mock.MatchedBy(func(k string) bool { return !strings.Contains(k, "_thumb") }), "image/png").
+		Return("/uploads/gifts/1/a.png", nil)
+	mockStorage.On("Put", mock.MatchedBy(func(k string) bool { return strings.Contains(k, "_thumb") }), "image/png").
+		Return("/uploads/gifts/1/a_thumb.png", nil)
+	mockImageRepo.On("Create", mock.MatchedBy(func(img *model.Image) bool {
+		return img.OwnerType == model.ImageOwnerGift && img.Width == 640 && img.Height == 480
+	})).Return(nil)
+	mockGiftRepo.On("UpdateImageURL", uint(1), "/uploads/gifts/1/a.png").Return(nil)
+
+	result, err := imageService.UploadGiftImages(1, 9, 1, []dto.UploadFile{{Filename: "a.png", Data: testPNG(t)}})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "/uploads/gifts/1/a_thumb.png", result[0].ThumbnailURL)
+	mockStorage.AssertExpectations(t)
+	mockImageRepo.AssertExpectations(t)
+	mockGiftRepo.AssertExpectations(t)
+}
+
+func TestImageService_UploadGiftImages_UnsupportedType(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1}, nil)
+	mockImageRepo.On("CountByOwner", model.ImageOwnerGift, uint(1)).Return(int64(0), nil)
+
+	// valid PNG followed by an HTML file renamed to .png: nothing must be stored
+	files := []dto.UploadFile{
+		{Filename: "a.png", Data: testPNG(t)},
+		{Filename: "evil.png", Data: []byte("<html><body>hi</body></html>")},
+	}
+	result, err := imageService.UploadGiftImages(1, 9, 1, files)
+
+	assert.Equal(t, apperror.ErrUnsupportedFile, err)
+	assert.Nil(t, result)
+	mockStorage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything)
+}
+
+func TestImageService_UploadGiftImages_TooLarge(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 100)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1}, nil)
+	mockImageRepo.On("CountByOwner", model.ImageOwnerGift, uint(1)).Return(int64(0), nil)
+
+	result, err := imageService.UploadGiftImages(1, 9, 1, []dto.UploadFile{{Data: testPNG(t)}})
+
+	assert.Equal(t, apperror.ErrFileTooLarge, err)
+	assert.Nil(t, result)
+}
+
+func TestImageService_UploadGiftImages_LimitReached(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1}, nil)
+	mockImageRepo.On("CountByOwner", model.ImageOwnerGift, uint(1)).Return(int64(MaxGiftImages), nil)
+
+	result, err := imageService.UploadGiftImages(1, 9, 1, []dto.UploadFile{{Data: testPNG(t)}})
+
+	assert.Equal(t, apperror.ErrTooManyFiles, err)
+	assert.Nil(t, result)
+}
+
+func TestImageService_UploadReviewPhotos_NotOwner(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(4)).Return(&model.Rating{ID: 4, UserID: 2}, nil)
+
+	result, err := imageService.UploadReviewPhotos(1, 3, 4, []dto.UploadFile{{Data: testPNG(t)}})
+
+	assert.Equal(t, apperror.ErrForbidden, err)
+	assert.Nil(t, result)
+	mockImageRepo.AssertNotCalled(t, "CountByOwner", mock.Anything, mock.Anything)
+}
+
+func TestImageService_DeleteGiftImage_WrongGift(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1}, nil)
+	mockImageRepo.On("FindByID", uint(5)).Return(&model.Image{ID: 5, OwnerType: model.ImageOwnerGift, OwnerID: 2}, nil)
+
+	err := imageService.DeleteGiftImage(1, 1, 5)
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	mockImageRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestImageService_DeleteGiftImage_MovesCover(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockImageRepo := new(mocks.MockImageRepository)
+	mockStorage := new(mocks.MockStorage)
+
+	imageService := NewImageService(mockGiftRepo, mockRatingRepo, mockImageRepo, mockStorage, 1<<20)
+
+	gift := &model.Gift{ID: 1, Stock: 3, ImageURL: "/a.png", Images: []model.Image{
+		{ID: 5, URL: "/a.png"},
+		{ID: 6, URL: "/b.png"},
+	}}
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(gift, nil)
+	mockImageRepo.On("FindByID", uint(5)).
+		Return(&model.Image{ID: 5, OwnerType: model.ImageOwnerGift, OwnerID: 1, URL: "/a.png", StorageKey: "a.png"}, nil)
+	mockImageRepo.On("Delete", uint(5)).Return(nil)
+	mockStorage.On("Delete", mock.Anything).Return(nil)
+	mockGiftRepo.On("UpdateImageURL", uint(1), "/b.png").Return(nil)
+
+	err := imageService.DeleteGiftImage(1, 1, 5)
+
+	assert.NoError(t, err)
+	mockGiftRepo.AssertExpectations(t)
+	// the loaded copy is never saved back over stock a redemption may have taken
+	mockGiftRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/authtoken"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var impersonationTestCfg = config.JWTConfig{
+	Issuer:           "https://gifts.example.com",
+	Audience:         "gift-redemption-api",
+	AccessTTL:        15 * time.Minute,
+	ImpersonationTTL: 5 * time.Minute,
+}
+
+func newTestImpersonationService(userRepo *mocks.MockUserRepository, roleRepo *mocks.MockRoleRepository, auditRepo *mocks.MockAuditRepository) ImpersonationService {
+	roles := NewRoleService(nil, roleRepo, new(mocks.MockAuditRepository))
+	return NewImpersonationService(userRepo, roles, auditRepo, testKeys, impersonationTestCfg)
+}
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	impersonationService := newTestImpersonationService(mockUserRepo, mockRoleRepo, mockAuditRepo)
+
+	mockUserRepo.On("FindInOrganisation", uint(2), uint(5)).Return(&model.User{ID: 5, OrganisationID: 2, Role: model.RoleUser}, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, OrganisationID: 2, Role: model.RoleAdmin}, nil)
+	mockRoleRepo.On("FindByName", "user").Return(&model.Role{Name: "user", Permissions: []model.RolePermission{{Permission: model.PermGiftsRead}}}, nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditImpersonationStart && *e.ActorID == 1 && e.TargetID == "5" && e.Metadata["reason"] == "TICKET-42"
+	})).Return(nil)
+
+	result, err := impersonationService.Impersonate(2, 1, 5, "family-1", dto.ImpersonateRequest{Reason: " TICKET-42 "}, "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), result.ImpersonatorID)
+	assert.Equal(t, uint(5), result.User.ID)
+	assert.Equal(t, 300, result.ExpiresIn)
+
+	claims, err := authtoken.Parse(testKeys, impersonationTestCfg, result.Token)
+	require.NoError(t, err)
+	userID, _ := claims.UserID()
+	assert.Equal(t, uint(5), userID)
+	assert.Equal(t, uint(1), claims.ImpersonatorID())
+	assert.Equal(t, "family-1", claims.SessionID)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestImpersonationService_Impersonate_Self(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	impersonationService := newTestImpersonationService(mockUserRepo, new(mocks.MockRoleRepository), new(mocks.MockAuditRepository))
+
+	result, err := impersonationService.Impersonate(2, 1, 1, "family-1", dto.ImpersonateRequest{Reason: "test"}, "10.0.0.1")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrCannotImpersonate)
+	mockUserRepo.AssertNotCalled(t, "FindInOrganisation", mock.Anything, mock.Anything)
+}
+
+func TestImpersonationService_Impersonate_MorePrivilegedTarget(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	impersonationService := newTestImpersonationService(mockUserRepo, mockRoleRepo, mockAuditRepo)
+
+	mockUserRepo.On("FindInOrganisation", uint(2), uint(5)).Return(&model.User{ID: 5, OrganisationID: 2, Role: model.RoleAdmin}, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, OrganisationID: 2, Role: "support_agent"}, nil)
+	mockRoleRepo.On("FindByName", "support_agent").Return(&model.Role{Name: "support_agent", Permissions: []model.RolePermission{
+		{Permission: model.PermUsersRead}, {Permission: model.PermUsersImpersonate},
+	}}, nil)
+
+	result, err := impersonationService.Impersonate(2, 1, 5, "family-1", dto.ImpersonateRequest{Reason: "test"}, "10.0.0.1")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrCannotImpersonate)
+	mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	AuditLoginLockout = "auth.login_lockout"
+	AuditLockoutClear = "auth.lockout_cleared"
+)
+
+type LockoutService interface {
+	// Check returns an *apperror.LockedError when the account or IP is locked
+	Check(email, ip string) error
+	RecordFailure(email, ip string) error
+	RecordSuccess(email string) error
+	GetLocked() ([]dto.LockoutResponse, error)
+	Clear(adminID, id uint, ip string) error
+}
+
+type lockoutService struct {
+	throttleRepo repository.LoginThrottleRepository
+	auditRepo    repository.AuditRepository
+	cfg          config.LockoutConfig
+	now          func() time.Time
+}
+
+func NewLockoutService(
+	throttleRepo repository.LoginThrottleRepository,
+	auditRepo repository.AuditRepository,
+	cfg config.LockoutConfig,
+) LockoutService {
+	return &lockoutService{throttleRepo, auditRepo, cfg, time.Now}
+}
+
+func (s *lockoutService) Check(email, ip string) error {
+	locked, err := s.throttleRepo.FindLocked(normalizeEmail(email), ip)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	var wait time.Duration
+	for _, t := range locked {
+		if t.IsLocked(now) {
+			wait = max(wait, t.LockedUntil.Sub(now))
+		}
+	}
+	if wait > 0 {
+		return &apperror.LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (s *lockoutService) RecordFailure(email, ip string) error {
+	if err := s.recordFailure(model.ThrottleScopeAccount, normalizeEmail(email), s.cfg.MaxAttempts, ip); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return s.recordFailure(model.ThrottleScopeIP, ip, s.cfg.IPMaxAttempts, ip)
+}
+
+func (s *lockoutService) RecordSuccess(email string) error {
+	return s.throttleRepo.Reset(model.ThrottleScopeAccount, normalizeEmail(email))
+}
+
+func (s *lockoutService) GetLocked() ([]dto.LockoutResponse, error) {
+	throttles, err := s.throttleRepo.FindAllLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.LockoutResponse, len(throttles))
+	for i, t := range throttles {
+		result[i] = dto.ToLockoutResponse(t)
+	}
+	return result, nil
+}
+
+func (s *lockoutService) Clear(adminID, id uint, ip string) error {
+	throttle, err := s.throttleRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.throttleRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.audit(&model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     AuditLockoutClear,
+		TargetType: throttle.Scope,
+		TargetID:   throttle.Key,
+		IP:         ip,
+		Metadata:   model.JSONMap{"failed_count": throttle.FailedCount},
+	})
+	return nil
+}
+
+func (s *lockoutService) recordFailure(scope, key string, threshold int, ip string) error {
+	throttle, err := s.throttleRepo.RecordFailure(scope, key, s.cfg.ResetAfter)
+	if err != nil {
+		return fmt.Errorf("record failed login: %w", err)
+	}
+
+	delay := s.lockDelay(throttle.FailedCount, threshold)
+	if delay == 0 {
+		return nil
+	}
+
+	until := s.now().Add(delay)
+	if err := s.throttleRepo.Lock(throttle.ID, until); err != nil {
+		return err
+	}
+
+	s.audit(&model.AuditLog{
+		ActorType:  model.ActorSystem,
+		Action:     AuditLoginLockout,
+		TargetType: scope,
+		TargetID:   key,
+		IP:         ip,
+		Metadata: model.JSONMap{
+			"failed_count": throttle.FailedCount,
+			"locked_until": until.Format(time.RFC3339),
+		},
+	})
+	return nil
+}
+
+// lockDelay doubles the lock for every failure past the threshold, capped at MaxDelay
+func (s *lockoutService) lockDelay(failed, threshold int) time.Duration {
+	if threshold <= 0 || failed < threshold {
+		return 0
+	}
+	delay := s.cfg.BaseDelay
+	for i := threshold; i < failed && delay < s.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.MaxDelay)
+}
+
+// audit failures are logged, never surfaced: a missing audit row must not unlock logins
+func (s *lockoutService) audit(entry *model.AuditLog) {
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testLockoutConfig = config.LockoutConfig{
+	MaxAttempts:   5,
+	IPMaxAttempts: 20,
+	BaseDelay:     30 * time.Second,
+	MaxDelay:      time.Hour,
+	ResetAfter:    time.Hour,
+}
+
+func newTestLockoutService(throttleRepo *mocks.MockLoginThrottleRepository, auditRepo *mocks.MockAuditRepository, now time.Time) LockoutService {
+	svc := NewLockoutService(throttleRepo, auditRepo, testLockoutConfig)
+	svc.(*lockoutService).now = func() time.Time { return now }
+	return svc
+}
+
+func TestLockoutService_Check_ReturnsLongestWait(t *testing.T) {
+	mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, now)
+
+	accountUntil := now.Add(2 * time.Minute)
+	ipUntil := now.Add(10 * time.Minute)
+	mockThrottleRepo.On("FindLocked", "test@example.com", "10.0.0.1").Return([]model.LoginThrottle{
+		{ID: 1, Scope: model.ThrottleScopeAccount, Key: "test@example.com", LockedUntil: &accountUntil},
+		{ID: 2, Scope: model.ThrottleScopeIP, Key: "10.0.0.1", LockedUntil: &ipUntil},
+	}, nil)
+
+	err := lockoutService.Check(" Test@Example.com", "10.0.0.1")
+
+	var locked *apperror.LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, 10*time.Minute, locked.RetryAfter)
+	assert.ErrorIs(t, err, apperror.ErrTooManyAttempts)
+}
+
+func TestLockoutService_Check_NotLocked(t *testing.T) {
+	mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, time.Now())
+
+	mockThrottleRepo.On("FindLocked", "test@example.com", "10.0.0.1").Return([]model.LoginThrottle{}, nil)
+
+	assert.NoError(t, lockoutService.Check("test@example.com", "10.0.0.1"))
+}
+
+func TestLockoutService_RecordFailure_Backoff(t *testing.T) {
+	tests := []struct {
+		name        string
+		failedCount int
+		wantDelay   time.Duration
+	}{
+		{"below threshold", 4, 0},
+		{"at threshold", 5, 30 * time.Second},
+		{"doubles per failure", 7, 2 * time.Minute},
+		{"capped at max delay", 30, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+			mockAuditRepo := new(mocks.MockAuditRepository)
+			now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, now)
+
+			mockThrottleRepo.On("RecordFailure", model.ThrottleScopeAccount, "test@example.com", time.Hour).
+				Return(&model.LoginThrottle{ID: 1, FailedCount: tt.failedCount}, nil)
+			mockThrottleRepo.On("RecordFailure", model.ThrottleScopeIP, "10.0.0.1", time.Hour).
+				Return(&model.LoginThrottle{ID: 2, FailedCount: 1}, nil)
+			if tt.wantDelay > 0 {
+				mockThrottleRepo.On("Lock", uint(1), now.Add(tt.wantDelay)).Return(nil)
+				mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+					return e.Action == AuditLoginLockout && e.ActorType == model.ActorSystem &&
+						e.TargetType == model.ThrottleScopeAccount && e.TargetID == "test@example.com"
+				})).Return(nil)
+			}
+
+			err := lockoutService.RecordFailure("test@example.com", "10.0.0.1")
+
+			assert.NoError(t, err)
+			mockThrottleRepo.AssertExpectations(t)
+			mockAuditRepo.AssertExpectations(t)
+			if tt.wantDelay == 0 {
+				mockThrottleRepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLockoutService_RecordSuccess_ResetsAccountOnly(t *testing.T) {
+	mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, time.Now())
+
+	mockThrottleRepo.On("Reset", model.ThrottleScopeAccount, "test@example.com").Return(nil)
+
+	assert.NoError(t, lockoutService.RecordSuccess("Test@example.com"))
+	mockThrottleRepo.AssertExpectations(t)
+}
+
+func TestLockoutService_Clear_WritesAudit(t *testing.T) {
+	mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, time.Now())
+
+	throttle := &model.LoginThrottle{ID: 3, Scope: model.ThrottleScopeIP, Key: "10.0.0.1", FailedCount: 25}
+	mockThrottleRepo.On("FindByID", uint(3)).Return(throttle, nil)
+	mockThrottleRepo.On("Delete", uint(3)).Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditLockoutClear && e.ActorType == model.ActorUser &&
+			*e.ActorID == 1 && e.TargetID == "10.0.0.1"
+	})).Return(nil)
+
+	err := lockoutService.Clear(1, 3, "192.168.1.1")
+
+	assert.NoError(t, err)
+	mockThrottleRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestLockoutService_Clear_NotFound(t *testing.T) {
+	mockThrottleRepo := new(mocks.MockLoginThrottleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	lockoutService := newTestLockoutService(mockThrottleRepo, mockAuditRepo, time.Now())
+
+	mockThrottleRepo.On("FindByID", uint(9)).Return(nil, apperror.ErrNotFound)
+
+	err := lockoutService.Clear(1, 9, "192.168.1.1")
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	mockThrottleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	AuditOrgCreate = "organisation.created"
+	AuditOrgUpdate = "organisation.updated"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+type OrganisationService interface {
+	GetAll() ([]dto.OrganisationResponse, error)
+	GetByID(id uint) (*dto.OrganisationResponse, error)
+	Create(adminID uint, req dto.CreateOrganisationRequest, ip string) (*dto.OrganisationResponse, error)
+	Update(adminID, id uint, req dto.UpdateOrganisationRequest, ip string) (*dto.OrganisationResponse, error)
+	// ResolveOrganisation maps a slug to its ID, apperror.ErrUnknownOrg if there is none
+	ResolveOrganisation(slug string) (uint, error)
+}
+
+type organisationService struct {
+	orgRepo   repository.OrganisationRepository
+	auditRepo repository.AuditRepository
+}
+
+func NewOrganisationService(orgRepo repository.OrganisationRepository, auditRepo repository.AuditRepository) OrganisationService {
+	return &organisationService{orgRepo, auditRepo}
+}
+
+func (s *organisationService) GetAll() ([]dto.OrganisationResponse, error) {
+	orgs, err := s.orgRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.OrganisationResponse, len(orgs))
+	for i, o := range orgs {
+		result[i] = dto.ToOrganisationResponse(o)
+	}
+	return result, nil
+}
+
+func (s *organisationService) GetByID(id uint) (*dto.OrganisationResponse, error) {
+	org, err := s.orgRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	res := dto.ToOrganisationResponse(*org)
+	return &res, nil
+}
+
+func (s *organisationService) Create(adminID uint, req dto.CreateOrganisationRequest, ip string) (*dto.OrganisationResponse, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, apperror.ErrInvalidSlug
+	}
+
+	org := &model.Organisation{Name: req.Name, Slug: req.Slug}
+	if err := s.orgRepo.Create(org); err != nil {
+		return nil, err
+	}
+
+	s.audit(adminID, AuditOrgCreate, org, ip)
+	res := dto.ToOrganisationResponse(*org)
+	return &res, nil
+}
+
+func (s *organisationService) Update(adminID, id uint, req dto.UpdateOrganisationRequest, ip string) (*dto.OrganisationResponse, error) {
+	org, err := s.orgRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the slug is part of client integrations, so it is immutable
+	org.Name = req.Name
+	if err := s.orgRepo.Update(org); err != nil {
+		return nil, err
+	}
+
+	s.audit(adminID, AuditOrgUpdate, org, ip)
+	res := dto.ToOrganisationResponse(*org)
+	return &res, nil
+}
+
+func (s *organisationService) ResolveOrganisation(slug string) (uint, error) {
+	org, err := s.orgRepo.FindBySlug(slug)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return 0, apperror.ErrUnknownOrg
+	}
+	if err != nil {
+		return 0, err
+	}
+	return org.ID, nil
+}
+
+func (s *organisationService) audit(adminID uint, action string, org *model.Organisation, ip string) {
+	entry := &model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     action,
+		TargetType: "organisation",
+		TargetID:   strconv.FormatUint(uint64(org.ID), 10),
+		IP:         ip,
+		Metadata:   model.JSONMap{"name": org.Name, "slug": org.Slug},
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganisationService_Create(t *testing.T) {
+	mockOrgRepo := new(mocks.MockOrganisationRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	orgService := NewOrganisationService(mockOrgRepo, mockAuditRepo)
+
+	mockOrgRepo.On("Create", mock.MatchedBy(func(o *model.Organisation) bool {
+		return o.Name == "Acme" && o.Slug == "acme"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.Organisation).ID = 2
+	}).Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditOrgCreate && e.TargetID == "2"
+	})).Return(nil)
+
+	result, err := orgService.Create(1, dto.CreateOrganisationRequest{Name: "Acme", Slug: "acme"}, "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), result.ID)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestOrganisationService_Create_InvalidSlug(t *testing.T) {
+	mockOrgRepo := new(mocks.MockOrganisationRepository)
+	orgService := NewOrganisationService(mockOrgRepo, new(mocks.MockAuditRepository))
+
+	for _, slug := range []string{"Acme", "-acme", "acme corp", "a"} {
+		result, err := orgService.Create(1, dto.CreateOrganisationRequest{Name: "Acme", Slug: slug}, "10.0.0.1")
+		assert.Nil(t, result, slug)
+		assert.ErrorIs(t, err, apperror.ErrInvalidSlug, slug)
+	}
+	mockOrgRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestOrganisationService_ResolveOrganisation(t *testing.T) {
+	mockOrgRepo := new(mocks.MockOrganisationRepository)
+	orgService := NewOrganisationService(mockOrgRepo, new(mocks.MockAuditRepository))
+
+	mockOrgRepo.On("FindBySlug", "acme").Return(&model.Organisation{ID: 2, Slug: "acme"}, nil)
+	mockOrgRepo.On("FindBySlug", "nope").Return(nil, apperror.ErrNotFound)
+
+	id, err := orgService.ResolveOrganisation("acme")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), id)
+
+	_, err = orgService.ResolveOrganisation("nope")
+	assert.ErrorIs(t, err, apperror.ErrUnknownOrg)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+// passwordResetTTL is how long a reset link stays valid
+const passwordResetTTL = time.Hour
+
+type PasswordService interface {
+	// ChangePassword ends every other session. All access tokens stop working,
+	// the caller's too, but the caller's refresh token survives to get a new one.
+	ChangePassword(userID uint, sessionID string, req dto.ChangePasswordRequest) error
+	ForgotPassword(req dto.ForgotPasswordRequest) error
+	ResetPassword(req dto.ResetPasswordRequest) error
+}
+
+type passwordService struct {
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	resetRepo   repository.PasswordResetRepository
+	refreshRepo repository.RefreshTokenRepository
+	mailer      mailer.Mailer
+	cfg         *config.Config
+}
+
+func NewPasswordService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	mailer mailer.Mailer,
+	cfg *config.Config,
+) PasswordService {
+	return &passwordService{db, userRepo, resetRepo, refreshRepo, mailer, cfg}
+}
+
+func (s *passwordService) ChangePassword(userID uint, sessionID string, req dto.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CheckPassword(req.CurrentPassword) {
+		return apperror.ErrInvalidPassword
+	}
+
+	if err := user.HashPassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(s.db, user.ID, user.Password); err != nil {
+		return err
+	}
+
+	return s.refreshRepo.RevokeAllForUser(user.ID, sessionID)
+}
+
+// ForgotPassword returns nil whether or not the email exists so the endpoint
+// cannot be used to enumerate accounts.
+func (s *passwordService) ForgotPassword(req dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	plain, err := securetoken.Generate()
+	if err != nil {
+		return err
+	}
+
+	token := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: securetoken.Hash(plain),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := s.resetRepo.Create(token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.AppBaseURL, url.QueryEscape(plain))
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone requested a password reset for your account. "+
+			"Open the link below within %d minutes to choose a new password. "+
+			"If this wasn't you, you can ignore this email.\n\n%s\n",
+			user.Name, int(passwordResetTTL.Minutes()), link),
+	}
+	// a failure is only logged: an error here would reveal the account exists
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("send reset email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (s *passwordService) ResetPassword(req dto.ResetPasswordRequest) error {
+	var userID uint
+
+	err := repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		token, err := s.resetRepo.FindUsableByHashForUpdate(tx, securetoken.Hash(req.Token))
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+		// only one reset may spend the token
+		if err := s.resetRepo.Consume(tx, token.ID); err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+
+		user, err := s.userRepo.FindByID(token.UserID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+
+		if err := user.HashPassword(req.NewPassword); err != nil {
+			return err
+		}
+		if err := s.userRepo.UpdatePassword(tx, user.ID, user.Password); err != nil {
+			return err
+		}
+
+		// any other outstanding token dies with the old password
+		userID = user.ID
+		return s.resetRepo.MarkAllUsed(tx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// whoever had the old password may still hold a session
+	return s.refreshRepo.RevokeAllForUser(userID, "")
+}
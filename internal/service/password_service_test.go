@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordService_ChangePassword_Success(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	passwordService := NewPasswordService(nil, mockUserRepo, mockResetRepo, mockRefreshRepo, mockMailer, &config.Config{})
+
+	user := &model.User{ID: 1}
+	_ = user.HashPassword("oldpassword")
+
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockUserRepo.On("UpdatePassword", mock.Anything, uint(1), mock.MatchedBy(func(hashed string) bool {
+		u := model.User{Password: hashed}
+		return u.CheckPassword("newpassword")
+	})).Return(nil)
+	mockRefreshRepo.On("RevokeAllForUser", uint(1), "current-session").Return(nil)
+
+	req := dto.ChangePasswordRequest{CurrentPassword: "oldpassword", NewPassword: "newpassword"}
+	err := passwordService.ChangePassword(1, "current-session", req)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestPasswordService_ChangePassword_WrongCurrent(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	passwordService := NewPasswordService(nil, mockUserRepo, mockResetRepo, mockRefreshRepo, mockMailer, &config.Config{})
+
+	user := &model.User{ID: 1}
+	_ = user.HashPassword("oldpassword")
+
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+
+	req := dto.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "newpassword"}
+	err := passwordService.ChangePassword(1, "current-session", req)
+
+	assert.Equal(t, apperror.ErrInvalidPassword, err)
+	mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	mockRefreshRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_ForgotPassword_StoresHashAndSendsLink(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	cfg := &config.Config{AppBaseURL: "https://gifts.example.com"}
+	passwordService := NewPasswordService(nil, mockUserRepo, mockResetRepo, mockRefreshRepo, mockMailer, cfg)
+
+	mockUserRepo.On("FindByEmail", "john@example.com").Return(&model.User{ID: 1, Email: "john@example.com"}, nil)
+
+	var stored *model.PasswordResetToken
+	mockResetRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.PasswordResetToken)
+	}).Return(nil)
+
+	var sent mailer.Message
+	mockMailer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(0).(mailer.Message)
+	}).Return(nil)
+
+	err := passwordService.ForgotPassword(dto.ForgotPasswordRequest{Email: "john@example.com"})
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+
+	// the link carries the plain token, the database only its hash
+	idx := strings.Index(sent.Body, "token=")
+	if assert.NotEqual(t, -1, idx) {
+		plain := strings.Fields(sent.Body[idx+len("token="):])[0]
+		assert.Equal(t, securetoken.Hash(plain), stored.TokenHash)
+		assert.NotContains(t, sent.Body, stored.TokenHash)
+	}
+}
+
+func TestPasswordService_ForgotPassword_MailerFailureLooksTheSame(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	passwordService := NewPasswordService(nil, mockUserRepo, mockResetRepo, mockRefreshRepo, mockMailer, &config.Config{})
+
+	mockUserRepo.On("FindByEmail", "john@example.com").Return(&model.User{ID: 1, Email: "john@example.com"}, nil)
+	mockResetRepo.On("Create", mock.Anything).Return(nil)
+	mockMailer.On("Send", mock.Anything).Return(errors.New("smtp down"))
+
+	err := passwordService.ForgotPassword(dto.ForgotPasswordRequest{Email: "john@example.com"})
+
+	assert.NoError(t, err)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestPasswordService_ForgotPassword_UnknownEmail(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockMailer := new(mocks.MockMailer)
+	passwordService := NewPasswordService(nil, mockUserRepo, mockResetRepo, mockRefreshRepo, mockMailer, &config.Config{})
+
+	mockUserRepo.On("FindByEmail", "ghost@example.com").Return(nil, apperror.ErrNotFound)
+
+	err := passwordService.ForgotPassword(dto.ForgotPasswordRequest{Email: "ghost@example.com"})
+
+	assert.NoError(t, err)
+	mockResetRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything)
+}
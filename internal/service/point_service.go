@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	// pointExpiryBatchSize is how many due lots the expiry job loads at a time
+	pointExpiryBatchSize = 500
+	// recentPointActivity is how many ledger entries the balance shows
+	recentPointActivity = 20
+)
+
+// PointService reports balances and expires points that have outlived
+// their validity.
+type PointService interface {
+	// GetBalance returns the user's balance, the points expiring within the
+	// warning window and their latest ledger entries
+	GetBalance(userID uint) (*dto.PointBalanceResponse, error)
+	// ExpireDue writes off every lot past its expiry; run by the scheduler
+	ExpireDue(ctx context.Context) error
+}
+
+type pointService struct {
+	userRepo  repository.UserRepository
+	pointRepo repository.PointRepository
+	cfg       config.PointConfig
+	now       func() time.Time
+}
+
+func NewPointService(userRepo repository.UserRepository, pointRepo repository.PointRepository, cfg config.PointConfig) PointService {
+	return &pointService{userRepo, pointRepo, cfg, time.Now}
+}
+
+func (s *pointService) GetBalance(userID uint) (*dto.PointBalanceResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	lots, err := s.pointRepo.FindExpiringLots(userID, now, now.Add(s.cfg.ExpiryWarning))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.pointRepo.FindEntriesByUser(userID, recentPointActivity)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.PointBalanceResponse{
+		Balance:             user.PointBalance,
+		UpcomingExpirations: dto.ToPointExpirations(lots),
+		RecentActivity:      dto.ToPointActivities(entries),
+	}
+	for _, e := range res.UpcomingExpirations {
+		res.ExpiringSoon += e.Points
+	}
+	return res, nil
+}
+
+func (s *pointService) ExpireDue(ctx context.Context) error {
+	now := s.now()
+	lots, points := 0, 0
+	for {
+		due, err := s.pointRepo.FindDueLots(now, pointExpiryBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, lot := range due {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			expired, err := s.pointRepo.ExpireLot(lot, now)
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				lots++
+				points += expired
+			}
+		}
+		// a short batch is the last one
+		if len(due) < pointExpiryBatchSize {
+			break
+		}
+	}
+
+	if lots > 0 {
+		log.Printf("points: expired %d points from %d lots", points, lots)
+	}
+	return nil
+}
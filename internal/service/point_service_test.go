@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPointService(now time.Time) (*pointService, *mocks.MockUserRepository, *mocks.MockPointRepository) {
+	users, points := new(mocks.MockUserRepository), new(mocks.MockPointRepository)
+	cfg := config.PointConfig{ValidMonths: 12, ExpiryInterval: time.Hour, ExpiryWarning: 90 * 24 * time.Hour}
+	svc := NewPointService(users, points, cfg).(*pointService)
+	svc.now = func() time.Time { return now }
+	return svc, users, points
+}
+
+func TestPointService_GetBalance(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc, users, points := newTestPointService(now)
+
+	day1 := time.Date(2025, 6, 20, 8, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 7, 3, 9, 30, 0, 0, time.UTC)
+	users.On("FindByID", uint(5)).Return(&model.User{ID: 5, PointBalance: 9000}, nil)
+	points.On("FindExpiringLots", uint(5), now, now.Add(90*24*time.Hour)).Return([]model.PointLot{
+		{Remaining: 3000, ExpiresAt: &day1},
+		{Remaining: 2000, ExpiresAt: &day2},
+		{Remaining: 500, ExpiresAt: &day2},
+	}, nil)
+	redemptionID := uint(11)
+	points.On("FindEntriesByUser", uint(5), recentPointActivity).Return([]model.PointLedgerEntry{
+		{Type: model.LedgerRedemption, Points: -300, RedemptionID: &redemptionID, CreatedAt: now},
+	}, nil)
+
+	res, err := svc.GetBalance(5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 9000, res.Balance)
+	assert.Equal(t, 5500, res.ExpiringSoon)
+	assert.Equal(t, []dto.PointExpiration{
+		{Date: "2025-06-20", Points: 3000},
+		{Date: "2025-07-03", Points: 2500},
+	}, res.UpcomingExpirations)
+	require.Len(t, res.RecentActivity, 1)
+	assert.Equal(t, &redemptionID, res.RecentActivity[0].RedemptionID)
+}
+
+func TestPointService_ExpireDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc, _, points := newTestPointService(now)
+
+	full := make([]model.PointLot, pointExpiryBatchSize)
+	for i := range full {
+		full[i] = model.PointLot{ID: uint(i + 1), Remaining: 10}
+	}
+	last := []model.PointLot{{ID: 9001, Remaining: 50}}
+	points.On("FindDueLots", now, pointExpiryBatchSize).Return(full, nil).Once()
+	points.On("FindDueLots", now, pointExpiryBatchSize).Return(last, nil).Once()
+	points.On("ExpireLot", mock.AnythingOfType("model.PointLot"), now).Return(10, nil)
+
+	err := svc.ExpireDue(context.Background())
+
+	require.NoError(t, err)
+	points.AssertNumberOfCalls(t, "FindDueLots", 2)
+	points.AssertNumberOfCalls(t, "ExpireLot", pointExpiryBatchSize+1)
+}
+
+func TestPointService_ExpireDue_StopsWhenCancelled(t *testing.T) {
+	svc, _, points := newTestPointService(time.Now())
+	points.On("FindDueLots", mock.Anything, pointExpiryBatchSize).Return([]model.PointLot{{ID: 1}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := svc.ExpireDue(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	points.AssertNotCalled(t, "ExpireLot", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/repository"
+)
+
+type preferenceNotifier struct {
+	userRepo repository.UserRepository
+	next     notifier.Notifier
+}
+
+// NewPreferenceNotifier drops events the recipient opted out of in their
+// notification preferences and forwards the rest to next.
+func NewPreferenceNotifier(userRepo repository.UserRepository, next notifier.Notifier) notifier.Notifier {
+	return &preferenceNotifier{userRepo, next}
+}
+
+func (n *preferenceNotifier) Notify(event notifier.Event) error {
+	user, err := n.userRepo.FindByID(event.UserID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		// deactivated users get nothing
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.NotificationPreferences.Allows(event.Type) {
+		return nil
+	}
+	return n.next.Notify(event)
+}
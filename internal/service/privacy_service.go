@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/storage"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditUserDataExport = "user.data_exported"
+	AuditUserErase      = "user.erased"
+)
+
+// PrivacyService answers data-protection requests: a full export of what is
+// held about a user and the erasure of their personal data.
+type PrivacyService interface {
+	// ExportOwn builds the archive of the caller's own account
+	ExportOwn(userID uint, ip string) (*dto.PersonalDataExport, error)
+	// Export builds the archive of a user of the organisation, deactivated ones included
+	Export(orgID uint, actor model.Actor, userID uint, ip string) (*dto.PersonalDataExport, error)
+	// Erase anonymises the user in place and deletes their review photos.
+	// Redemptions, ratings and votes are kept so gift statistics and stock
+	// history do not change. Erasing an erased user does nothing.
+	Erase(orgID uint, actor model.Actor, userID uint, platformAdmin bool, ip string) (*dto.EraseUserResponse, error)
+}
+
+type privacyService struct {
+	db             *gorm.DB
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	redemptionRepo repository.RedemptionRepository
+	pointRepo      repository.PointRepository
+	ratingRepo     repository.RatingRepository
+	voteRepo       repository.ReviewVoteRepository
+	refreshRepo    repository.RefreshTokenRepository
+	recoveryRepo   repository.RecoveryCodeRepository
+	resetRepo      repository.PasswordResetRepository
+	imageRepo      repository.ImageRepository
+	auditRepo      repository.AuditRepository
+	storage        storage.Storage
+	now            func() time.Time
+}
+
+func NewPrivacyService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	redemptionRepo repository.RedemptionRepository,
+	pointRepo repository.PointRepository,
+	ratingRepo repository.RatingRepository,
+	voteRepo repository.ReviewVoteRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	resetRepo repository.PasswordResetRepository,
+	imageRepo repository.ImageRepository,
+	auditRepo repository.AuditRepository,
+	storage storage.Storage,
+) PrivacyService {
+	return &privacyService{
+		db:             db,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		redemptionRepo: redemptionRepo,
+		pointRepo:      pointRepo,
+		ratingRepo:     ratingRepo,
+		voteRepo:       voteRepo,
+		refreshRepo:    refreshRepo,
+		recoveryRepo:   recoveryRepo,
+		resetRepo:      resetRepo,
+		imageRepo:      imageRepo,
+		auditRepo:      auditRepo,
+		storage:        storage,
+		now:            time.Now,
+	}
+}
+
+func (s *privacyService) ExportOwn(userID uint, ip string) (*dto.PersonalDataExport, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.export(model.UserActor(userID), user, ip)
+}
+
+func (s *privacyService) Export(orgID uint, actor model.Actor, userID uint, ip string) (*dto.PersonalDataExport, error) {
+	user, err := s.userRepo.FindInOrganisationWithDeleted(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.export(actor, user, ip)
+}
+
+func (s *privacyService) export(actor model.Actor, user *model.User, ip string) (*dto.PersonalDataExport, error) {
+	redemptions, err := s.redemptionRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := s.pointRepo.FindEntriesByUser(user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.ratingRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.voteRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.refreshRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.auditRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &dto.PersonalDataExport{
+		GeneratedAt: s.now().UTC(),
+		Profile: dto.PersonalProfile{
+			ID:                      user.ID,
+			OrganisationID:          user.OrganisationID,
+			Name:                    user.Name,
+			Email:                   user.Email,
+			Role:                    string(user.Role),
+			AvatarURL:               user.AvatarURL,
+			Locale:                  user.Locale,
+			NotificationPreferences: user.NotificationPreferences,
+			EmailVerifiedAt:         user.EmailVerifiedAt,
+			TwoFactorEnabled:        user.IsTwoFactorEnabled(),
+			CreatedAt:               user.CreatedAt,
+			UpdatedAt:               user.UpdatedAt,
+			ErasedAt:                user.ErasedAt,
+		},
+		Points: dto.PersonalPoints{
+			Balance: user.PointBalance,
+			Ledger:  make([]dto.PointLedgerEntry, 0, len(ledger)),
+		},
+		Redemptions:    make([]dto.PersonalRedemption, 0, len(redemptions)),
+		Ratings:        make([]dto.PersonalRating, 0, len(ratings)),
+		ReviewVotes:    make([]dto.PersonalReviewVote, 0, len(votes)),
+		Sessions:       make([]dto.PersonalSession, 0, len(sessions)),
+		SecurityEvents: make([]dto.PersonalSecurityEvent, 0, len(events)),
+	}
+	if user.DeletedAt.Valid {
+		archive.Profile.DeactivatedAt = &user.DeletedAt.Time
+	}
+
+	for _, r := range redemptions {
+		giftName := ""
+		if r.Gift != nil {
+			giftName = r.Gift.Name
+		}
+		archive.Redemptions = append(archive.Redemptions, dto.PersonalRedemption{
+			ID:         r.ID,
+			GiftID:     r.GiftID,
+			GiftName:   giftName,
+			Quantity:   r.Quantity,
+			TotalPoint: r.TotalPoint,
+			RedeemedAt: r.RedeemedAt,
+		})
+	}
+
+	for _, e := range ledger {
+		entry := dto.PointLedgerEntry{At: e.CreatedAt, Type: e.Type, Points: e.Points}
+		switch {
+		case e.RedemptionID != nil:
+			entry.Reference = *e.RedemptionID
+		case e.LotID != nil:
+			entry.Reference = *e.LotID
+		}
+		archive.Points.Ledger = append(archive.Points.Ledger, entry)
+	}
+
+	for _, r := range ratings {
+		rating := dto.PersonalRating{
+			ID:           r.ID,
+			GiftID:       r.GiftID,
+			RedemptionID: r.RedemptionID,
+			Score:        r.Score,
+			HiddenAt:     r.HiddenAt,
+			PhotoURLs:    make([]string, 0, len(r.Photos)),
+			CreatedAt:    r.CreatedAt,
+		}
+		if r.Gift != nil {
+			rating.GiftName = r.Gift.Name
+		}
+		if r.Reply != nil {
+			rating.Reply = r.Reply.Body
+		}
+		for _, photo := range r.Photos {
+			rating.PhotoURLs = append(rating.PhotoURLs, photo.URL)
+		}
+		archive.Ratings = append(archive.Ratings, rating)
+	}
+
+	for _, v := range votes {
+		archive.ReviewVotes = append(archive.ReviewVotes, dto.PersonalReviewVote{
+			RatingID:  v.RatingID,
+			IsHelpful: v.IsHelpful,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+
+	for _, t := range sessions {
+		archive.Sessions = append(archive.Sessions, dto.PersonalSession{
+			SessionID: t.FamilyID,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+			RevokedAt: t.RevokedAt,
+		})
+	}
+
+	for _, e := range events {
+		archive.SecurityEvents = append(archive.SecurityEvents, dto.PersonalSecurityEvent{
+			Action:    e.Action,
+			IP:        e.IP,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	s.audit(actor, AuditUserDataExport, user.ID, ip, nil)
+	return archive, nil
+}
+
+func (s *privacyService) Erase(orgID uint, actor model.Actor, userID uint, platformAdmin bool, ip string) (*dto.EraseUserResponse, error) {
+	// an admin erasing their own account would lock themselves out mid-request
+	if actor.Type == model.ActorUser && actor.ID == userID {
+		return nil, apperror.ErrForbidden
+	}
+
+	user, err := s.userRepo.FindInOrganisationWithDeleted(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUserManageable(s.roleRepo, user, platformAdmin); err != nil {
+		return nil, err
+	}
+	if user.IsErased() {
+		return &dto.EraseUserResponse{UserID: user.ID, ErasedAt: *user.ErasedAt}, nil
+	}
+
+	// photos go first: if anonymising fails the erasure can simply be retried
+	ratings, err := s.ratingRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	photos := 0
+	for _, rating := range ratings {
+		for _, photo := range rating.Photos {
+			if err := s.imageRepo.Delete(photo.ID); err != nil {
+				return nil, err
+			}
+			s.removeObjects(photo.StorageKey, photo.ThumbnailKey)
+			photos++
+		}
+	}
+
+	erasedAt := s.now()
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.userRepo.Anonymise(tx, user.ID, erasedAt); err != nil {
+			return err
+		}
+		if err := s.recoveryRepo.DeleteByUser(tx, user.ID); err != nil {
+			return err
+		}
+		return s.resetRepo.MarkAllUsed(tx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// access tokens die with tokens_valid_after; refresh tokens are revoked here
+	if err := s.refreshRepo.RevokeAllForUser(user.ID, ""); err != nil {
+		log.Printf("revoke sessions of erased user %d: %v", user.ID, err)
+	}
+
+	s.audit(actor, AuditUserErase, user.ID, ip, model.JSONMap{"photos_deleted": photos})
+	return &dto.EraseUserResponse{UserID: user.ID, ErasedAt: erasedAt, PhotosDeleted: photos}, nil
+}
+
+func (s *privacyService) audit(actor model.Actor, action string, userID uint, ip string, metadata model.JSONMap) {
+	entry := &model.AuditLog{
+		ActorType:  actor.Type,
+		ActorID:    actor.ActorIDPtr(),
+		Action:     action,
+		TargetType: "user",
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		IP:         ip,
+		Metadata:   metadata,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
+
+// removeObjects is best effort: the rows are already gone, so a leftover
+// object is unreachable
+func (s *privacyService) removeObjects(keys ...string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			log.Printf("delete object %s: %v", key, err)
+		}
+	}
+}
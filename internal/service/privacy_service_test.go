@@ -0,0 +1,158 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type privacyMocks struct {
+	users       *mocks.MockUserRepository
+	roles       *mocks.MockRoleRepository
+	redemptions *mocks.MockRedemptionRepository
+	points      *mocks.MockPointRepository
+	ratings     *mocks.MockRatingRepository
+	votes       *mocks.MockReviewVoteRepository
+	refresh     *mocks.MockRefreshTokenRepository
+	audit       *mocks.MockAuditRepository
+	images      *mocks.MockImageRepository
+}
+
+func newTestPrivacyService() (PrivacyService, privacyMocks) {
+	m := privacyMocks{
+		users:       new(mocks.MockUserRepository),
+		roles:       new(mocks.MockRoleRepository),
+		redemptions: new(mocks.MockRedemptionRepository),
+		points:      new(mocks.MockPointRepository),
+		ratings:     new(mocks.MockRatingRepository),
+		votes:       new(mocks.MockReviewVoteRepository),
+		refresh:     new(mocks.MockRefreshTokenRepository),
+		audit:       new(mocks.MockAuditRepository),
+		images:      new(mocks.MockImageRepository),
+	}
+	svc := NewPrivacyService(nil, m.users, m.roles, m.redemptions, m.points, m.ratings, m.votes, m.refresh,
+		new(mocks.MockRecoveryCodeRepository), new(mocks.MockPasswordResetRepository), m.images, m.audit, new(mocks.MockStorage))
+	return svc, m
+}
+
+func TestPrivacyService_ExportOwn(t *testing.T) {
+	svc, m := newTestPrivacyService()
+
+	redeemedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	user := &model.User{ID: 5, OrganisationID: 1, Name: "John", Email: "john@example.com", Role: model.RoleUser, PointBalance: 700}
+	m.users.On("FindByID", uint(5)).Return(user, nil)
+	m.redemptions.On("FindByUser", uint(5)).Return([]model.Redemption{
+		{ID: 11, GiftID: 3, Quantity: 2, TotalPoint: 300, RedeemedAt: redeemedAt, Gift: &model.Gift{Name: "Mug"}},
+	}, nil)
+	redemptionID, lotID := uint(11), uint(4)
+	m.points.On("FindEntriesByUser", uint(5), 0).Return([]model.PointLedgerEntry{
+		{Type: model.LedgerRedemption, Points: -300, RedemptionID: &redemptionID, CreatedAt: redeemedAt},
+		{Type: model.LedgerImport, Points: 1000, LotID: &lotID},
+	}, nil)
+	m.ratings.On("FindByUser", uint(5)).Return([]model.Rating{
+		{ID: 21, GiftID: 3, RedemptionID: 11, Score: 4.5, Gift: &model.Gift{Name: "Mug"},
+			Photos: []model.Image{{URL: "https://cdn.example.com/p.jpg"}},
+			Reply:  &model.ReviewReply{Body: "Thanks!"}},
+	}, nil)
+	m.votes.On("FindByUser", uint(5)).Return([]model.ReviewVote{{RatingID: 30, IsHelpful: true}}, nil)
+	m.refresh.On("FindByUser", uint(5)).Return([]model.RefreshToken{{FamilyID: "fam-1", TokenHash: "secret"}}, nil)
+	m.audit.On("FindByUser", uint(5)).Return([]model.AuditLog{{Action: "auth.login", IP: "10.0.0.1"}}, nil)
+	m.audit.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditUserDataExport && e.TargetID == "5" && *e.ActorID == 5
+	})).Return(nil)
+
+	archive, err := svc.ExportOwn(5, "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", archive.Profile.Email)
+	assert.Nil(t, archive.Profile.DeactivatedAt)
+	assert.Equal(t, 700, archive.Points.Balance)
+	require.Len(t, archive.Points.Ledger, 2)
+	assert.Equal(t, -300, archive.Points.Ledger[0].Points)
+	assert.Equal(t, uint(11), archive.Points.Ledger[0].Reference)
+	assert.Equal(t, model.LedgerImport, archive.Points.Ledger[1].Type)
+	assert.Equal(t, uint(4), archive.Points.Ledger[1].Reference)
+	require.Len(t, archive.Redemptions, 1)
+	assert.Equal(t, "Mug", archive.Redemptions[0].GiftName)
+	require.Len(t, archive.Ratings, 1)
+	assert.Equal(t, []string{"https://cdn.example.com/p.jpg"}, archive.Ratings[0].PhotoURLs)
+	assert.Equal(t, "Thanks!", archive.Ratings[0].Reply)
+	assert.Len(t, archive.ReviewVotes, 1)
+	require.Len(t, archive.Sessions, 1)
+	assert.Equal(t, "fam-1", archive.Sessions[0].SessionID)
+	assert.Equal(t, "auth.login", archive.SecurityEvents[0].Action)
+	m.audit.AssertExpectations(t)
+}
+
+func TestPrivacyService_Export_DeactivatedUser(t *testing.T) {
+	svc, m := newTestPrivacyService()
+
+	deletedAt := time.Now()
+	user := &model.User{ID: 5, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}
+	m.users.On("FindInOrganisationWithDeleted", uint(1), uint(5)).Return(user, nil)
+	m.redemptions.On("FindByUser", uint(5)).Return([]model.Redemption{}, nil)
+	m.points.On("FindEntriesByUser", uint(5), 0).Return([]model.PointLedgerEntry{}, nil)
+	m.ratings.On("FindByUser", uint(5)).Return([]model.Rating{}, nil)
+	m.votes.On("FindByUser", uint(5)).Return([]model.ReviewVote{}, nil)
+	m.refresh.On("FindByUser", uint(5)).Return([]model.RefreshToken{}, nil)
+	m.audit.On("FindByUser", uint(5)).Return([]model.AuditLog{}, nil)
+	m.audit.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil)
+
+	archive, err := svc.Export(1, model.UserActor(9), 5, "")
+
+	require.NoError(t, err)
+	require.NotNil(t, archive.Profile.DeactivatedAt)
+	assert.True(t, deletedAt.Equal(*archive.Profile.DeactivatedAt))
+	assert.NotNil(t, archive.Redemptions)
+	assert.NotNil(t, archive.Points.Ledger)
+}
+
+func TestPrivacyService_Export_OtherOrganisation(t *testing.T) {
+	svc, m := newTestPrivacyService()
+	m.users.On("FindInOrganisationWithDeleted", uint(2), uint(5)).Return(nil, apperror.ErrNotFound)
+
+	_, err := svc.Export(2, model.UserActor(9), 5, "")
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestPrivacyService_Erase_Self(t *testing.T) {
+	svc, m := newTestPrivacyService()
+
+	_, err := svc.Erase(1, model.UserActor(5), 5, false, "")
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	m.users.AssertNotCalled(t, "FindInOrganisationWithDeleted", mock.Anything, mock.Anything)
+}
+
+func TestPrivacyService_Erase_SuperAdminByTenantAdmin(t *testing.T) {
+	svc, m := newTestPrivacyService()
+	m.users.On("FindInOrganisationWithDeleted", uint(1), uint(5)).
+		Return(&model.User{ID: 5, Role: model.RoleSuperAdmin}, nil)
+
+	_, err := svc.Erase(1, model.UserActor(9), 5, false, "")
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	m.ratings.AssertNotCalled(t, "FindByUser", mock.Anything)
+}
+
+func TestPrivacyService_Erase_AlreadyErased(t *testing.T) {
+	svc, m := newTestPrivacyService()
+	erasedAt := time.Now().Add(-time.Hour)
+	m.users.On("FindInOrganisationWithDeleted", uint(1), uint(5)).
+		Return(&model.User{ID: 5, Role: model.RoleUser, ErasedAt: &erasedAt}, nil)
+
+	res, err := svc.Erase(1, model.UserActor(9), 5, false, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, erasedAt, res.ErasedAt)
+	m.users.AssertNotCalled(t, "Anonymise", mock.Anything, mock.Anything, mock.Anything)
+	m.audit.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/repository"
+)
+
+const (
+	AuditUserDeactivate = "user.deactivated"
+
+	// profileTierChanges is how much tier history the profile shows
+	profileTierChanges = 20
+)
+
+// ProfileService serves the caller's own account under /me
+type ProfileService interface {
+	// Get includes the caller's loyalty tier and their latest tier changes
+	Get(userID uint) (*dto.ProfileResponse, error)
+	Update(userID uint, req dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	// Deactivate soft-deletes the account and ends all its sessions
+	Deactivate(userID uint, req dto.DeactivateAccountRequest, ip string) error
+}
+
+type profileService struct {
+	userRepo       repository.UserRepository
+	redemptionRepo repository.RedemptionRepository
+	tierRepo       repository.TierRepository
+	refreshRepo    repository.RefreshTokenRepository
+	auditRepo      repository.AuditRepository
+}
+
+func NewProfileService(
+	userRepo repository.UserRepository,
+	redemptionRepo repository.RedemptionRepository,
+	tierRepo repository.TierRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	auditRepo repository.AuditRepository,
+) ProfileService {
+	return &profileService{userRepo, redemptionRepo, tierRepo, refreshRepo, auditRepo}
+}
+
+func (s *profileService) Get(userID uint) (*dto.ProfileResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := toUserResponse(s.redemptionRepo, user)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &dto.ProfileResponse{UserResponse: *res}
+	if user.TierID != nil {
+		tier, err := s.tierRepo.FindByID(user.OrganisationID, *user.TierID)
+		if err != nil {
+			return nil, err
+		}
+		tierRes := dto.ToTierResponse(*tier)
+		profile.Tier = &tierRes
+	}
+	changes, err := s.tierRepo.FindChangesByUser(user.ID, profileTierChanges)
+	if err != nil {
+		return nil, err
+	}
+	profile.TierChanges = dto.ToTierChangeResponses(changes)
+	return profile, nil
+}
+
+func (s *profileService) Update(userID uint, req dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	for eventType := range req.NotificationPreferences {
+		if !notifier.IsEventType(eventType) {
+			return nil, fmt.Errorf("%w: %s", apperror.ErrUnknownEventType, eventType)
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if len(req.NotificationPreferences) > 0 {
+		if user.NotificationPreferences == nil {
+			user.NotificationPreferences = model.NotificationPreferences{}
+		}
+		for eventType, enabled := range req.NotificationPreferences {
+			user.NotificationPreferences[eventType] = enabled
+		}
+	}
+
+	if err := s.userRepo.UpdateProfile(user); err != nil {
+		return nil, err
+	}
+	return toUserResponse(s.redemptionRepo, user)
+}
+
+func (s *profileService) Deactivate(userID uint, req dto.DeactivateAccountRequest, ip string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.CheckPassword(req.Password) {
+		return apperror.ErrInvalidPassword
+	}
+	// the platform must not lose its operators by accident
+	if user.Role == model.RoleSuperAdmin {
+		return apperror.ErrForbidden
+	}
+
+	if err := s.userRepo.Delete(user.OrganisationID, user.ID); err != nil {
+		return err
+	}
+	// access tokens already fail validation once the user is gone
+	if err := s.refreshRepo.RevokeAllForUser(user.ID, ""); err != nil {
+		return err
+	}
+
+	entry := &model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &user.ID,
+		Action:     AuditUserDeactivate,
+		TargetType: "user",
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		IP:         ip,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileService_Update(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	profileService := NewProfileService(mockUserRepo, noRedemptions(), new(mocks.MockTierRepository), new(mocks.MockRefreshTokenRepository), new(mocks.MockAuditRepository))
+
+	user := &model.User{ID: 1, Name: "Old", Locale: "en", AvatarURL: "https://cdn.example.com/a.png"}
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockUserRepo.On("UpdateProfile", mock.AnythingOfType("*model.User")).Return(nil)
+
+	name, locale, avatar := "  New Name ", "id-ID", ""
+	result, err := profileService.Update(1, dto.UpdateProfileRequest{
+		Name:                    &name,
+		Locale:                  &locale,
+		AvatarURL:               &avatar,
+		NotificationPreferences: map[string]bool{notifier.EventReviewReplied: false},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "New Name", result.Name)
+	assert.Equal(t, "id-ID", result.Locale)
+	assert.Empty(t, result.AvatarURL)
+	assert.False(t, user.NotificationPreferences.Allows(notifier.EventReviewReplied))
+}
+
+func TestProfileService_Update_UnknownEventType(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	profileService := NewProfileService(mockUserRepo, noRedemptions(), new(mocks.MockTierRepository), new(mocks.MockRefreshTokenRepository), new(mocks.MockAuditRepository))
+
+	result, err := profileService.Update(1, dto.UpdateProfileRequest{
+		NotificationPreferences: map[string]bool{"newsletter.weekly": true},
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrUnknownEventType)
+	mockUserRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything)
+}
+
+func TestProfileService_Deactivate(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRefreshRepo := new(mocks.MockRefreshTokenRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	profileService := NewProfileService(mockUserRepo, noRedemptions(), new(mocks.MockTierRepository), mockRefreshRepo, mockAuditRepo)
+
+	user := &model.User{ID: 1, OrganisationID: 2, Role: model.RoleUser}
+	require.NoError(t, user.HashPassword("password123"))
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockUserRepo.On("Delete", uint(2), uint(1)).Return(nil)
+	mockRefreshRepo.On("RevokeAllForUser", uint(1), "").Return(nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditUserDeactivate && e.TargetID == "1"
+	})).Return(nil)
+
+	err := profileService.Deactivate(1, dto.DeactivateAccountRequest{Password: "password123"}, "10.0.0.1")
+
+	require.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestProfileService_Deactivate_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     model.UserRole
+		password string
+		wantErr  error
+	}{
+		{"wrong password", model.RoleUser, "wrong-password", apperror.ErrInvalidPassword},
+		{"super admin", model.RoleSuperAdmin, "password123", apperror.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.MockUserRepository)
+			profileService := NewProfileService(mockUserRepo, noRedemptions(), new(mocks.MockTierRepository), new(mocks.MockRefreshTokenRepository), new(mocks.MockAuditRepository))
+
+			user := &model.User{ID: 1, OrganisationID: 2, Role: tt.role}
+			require.NoError(t, user.HashPassword("password123"))
+			mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+
+			err := profileService.Deactivate(1, dto.DeactivateAccountRequest{Password: tt.password}, "10.0.0.1")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPreferenceNotifier(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	notify := NewPreferenceNotifier(mockUserRepo, mockNotifier)
+
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1}, nil)
+	mockUserRepo.On("FindByID", uint(2)).Return(&model.User{ID: 2, NotificationPreferences: model.NotificationPreferences{
+		notifier.EventReviewReplied: false,
+	}}, nil)
+	mockNotifier.On("Notify", mock.MatchedBy(func(e notifier.Event) bool { return e.UserID == 1 })).Return(nil)
+
+	require.NoError(t, notify.Notify(notifier.Event{Type: notifier.EventReviewReplied, UserID: 1}))
+	require.NoError(t, notify.Notify(notifier.Event{Type: notifier.EventReviewReplied, UserID: 2}))
+
+	mockNotifier.AssertNumberOfCalls(t, "Notify", 1)
+}
@@ -1,38 +1,82 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/repository"
 	"gorm.io/gorm"
 )
 
 type RedemptionService interface {
-	Redeem(userID, giftID uint, req dto.RedemptionRequest) (*dto.RedemptionResponse, error)
-	Rate(userID, giftID uint, req dto.RatingRequest) (*dto.RatingResponse, error)
+	Redeem(orgID, userID, giftID uint, req dto.RedemptionRequest) (*dto.RedemptionResponse, error)
+	Rate(orgID, userID, giftID uint, req dto.RatingRequest) (*dto.RatingResponse, error)
 }
 
 type redemptionService struct {
 	db             *gorm.DB
+	userRepo       repository.UserRepository
 	giftRepo       repository.GiftRepository
 	redemptionRepo repository.RedemptionRepository
 	ratingRepo     repository.RatingRepository
+	tierRepo       repository.TierRepository
+	pointRepo      repository.PointRepository
+	campaignRepo   repository.CampaignRepository
+	flashSaleRepo  repository.FlashSaleRepository
+	couponRepo     repository.CouponRepository
+	now            func() time.Time
 }
 
 func NewRedemptionService(
 	db *gorm.DB,
+	userRepo repository.UserRepository,
 	giftRepo repository.GiftRepository,
 	redemptionRepo repository.RedemptionRepository,
 	ratingRepo repository.RatingRepository,
+	tierRepo repository.TierRepository,
+	pointRepo repository.PointRepository,
+	campaignRepo repository.CampaignRepository,
+	flashSaleRepo repository.FlashSaleRepository,
+	couponRepo repository.CouponRepository,
 ) RedemptionService {
-	return &redemptionService{db, giftRepo, redemptionRepo, ratingRepo}
+	return &redemptionService{db, userRepo, giftRepo, redemptionRepo, ratingRepo, tierRepo, pointRepo, campaignRepo, flashSaleRepo, couponRepo, time.Now}
 }
 
-func (s *redemptionService) Redeem(userID, giftID uint, req dto.RedemptionRequest) (*dto.RedemptionResponse, error) {
+func (s *redemptionService) Redeem(orgID, userID, giftID uint, req dto.RedemptionRequest) (*dto.RedemptionResponse, error) {
 	// check gift exists before opening transaction
-	gift, err := s.giftRepo.FindByID(giftID)
+	gift, err := s.giftRepo.FindByID(orgID, giftID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, err := s.redeemerTier(orgID, userID, gift)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	sale, err := s.flashSaleRepo.FindRunning(orgID, giftID, now)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if sale != nil && req.Quantity != 1 {
+		return nil, fmt.Errorf("%w: redeem a quantity of 1", apperror.ErrFlashSaleLimit)
+	}
+
+	// the campaign price comes first, then the tier discount applies to it
+	campaigns, err := s.campaignRepo.FindRunning(orgID, now)
+	if err != nil {
+		return nil, err
+	}
+	campaign := model.BestCampaign(campaigns, gift, now)
+	unitPrice := tier.Price(campaign.Price(gift.Point))
+
+	// a coupon comes off the total last
+	coupon, err := s.findCoupon(orgID, req.CouponCode, gift, unitPrice*req.Quantity, now)
 	if err != nil {
 		return nil, err
 	}
@@ -40,19 +84,74 @@ func (s *redemptionService) Redeem(userID, giftID uint, req dto.RedemptionReques
 	var redemption *model.Redemption
 
 	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
-		// deduct stock with row lock inside transaction
-		if err := s.giftRepo.DeductStock(tx, giftID, req.Quantity); err != nil {
+		// a flash sale hands out its own units so buyers never queue on the gift row
+		var unitID uint
+		if sale != nil {
+			id, err := s.flashSaleRepo.ClaimUnit(tx, sale.ID, userID)
+			if err != nil {
+				return err
+			}
+			unitID = id
+		} else if err := s.giftRepo.DeductStock(tx, giftID, req.Quantity); err != nil {
+			// deduct stock with row lock inside transaction
 			return err
 		}
+		if coupon != nil {
+			if err := s.couponRepo.Use(tx, coupon, userID); err != nil {
+				return err
+			}
+		}
 
 		redemption = &model.Redemption{
-			UserID:     userID,
-			GiftID:     giftID,
-			Quantity:   req.Quantity,
-			TotalPoint: gift.Point * req.Quantity,
+			OrganisationID: orgID,
+			UserID:         userID,
+			GiftID:         giftID,
+			Quantity:       req.Quantity,
+			TotalPoint:     unitPrice * req.Quantity,
+		}
+		if campaign != nil {
+			redemption.CampaignID = &campaign.ID
+			redemption.CampaignDiscount = (tier.Price(gift.Point) - unitPrice) * req.Quantity
+		}
+		if coupon != nil {
+			redemption.CouponID = &coupon.ID
+			redemption.CouponDiscount = coupon.Discount(unitPrice, req.Quantity)
+			redemption.TotalPoint -= redemption.CouponDiscount
+		}
+
+		if err := s.userRepo.DebitPoints(tx, userID, redemption.TotalPoint); err != nil {
+			return err
+		}
+		if _, err := s.pointRepo.ConsumeLots(tx, userID, redemption.TotalPoint, now); err != nil {
+			return err
 		}
 
-		return s.redemptionRepo.Create(tx, redemption)
+		if err := s.redemptionRepo.Create(tx, redemption); err != nil {
+			return err
+		}
+		if sale != nil {
+			if err := s.flashSaleRepo.AttachRedemption(tx, unitID, redemption.ID); err != nil {
+				return err
+			}
+		}
+		if coupon != nil {
+			err := s.couponRepo.RecordUse(tx, &model.CouponRedemption{
+				CouponID:     coupon.ID,
+				UserID:       userID,
+				RedemptionID: redemption.ID,
+				Discount:     redemption.CouponDiscount,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return s.pointRepo.CreateEntry(tx, &model.PointLedgerEntry{
+			OrganisationID: orgID,
+			UserID:         userID,
+			Type:           model.LedgerRedemption,
+			Points:         -redemption.TotalPoint,
+			RedemptionID:   &redemption.ID,
+		})
 	})
 
 	if err != nil {
@@ -63,14 +162,62 @@ func (s *redemptionService) Redeem(userID, giftID uint, req dto.RedemptionReques
 	return &res, nil
 }
 
-func (s *redemptionService) Rate(userID, giftID uint, req dto.RatingRequest) (*dto.RatingResponse, error) {
+// findCoupon looks up the code entered for a redemption costing total points,
+// nil when none was entered. The usage limits are checked in the transaction.
+func (s *redemptionService) findCoupon(orgID uint, code string, gift *model.Gift, total int, now time.Time) (*model.Coupon, error) {
+	code = model.NormalizeCouponCode(code)
+	if code == "" {
+		return nil, nil
+	}
+	coupon, err := s.couponRepo.FindByCode(orgID, code)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown code", apperror.ErrCouponNotValid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !coupon.ValidAt(now):
+		return nil, fmt.Errorf("%w: the code is not valid at this time", apperror.ErrCouponNotValid)
+	case !coupon.Targets(gift):
+		return nil, fmt.Errorf("%w: the code does not apply to this gift", apperror.ErrCouponNotValid)
+	case total < coupon.MinPoints:
+		return nil, fmt.Errorf("%w: the redemption must cost at least %d points", apperror.ErrCouponNotValid, coupon.MinPoints)
+	case coupon.MaxUses > 0 && coupon.Uses >= coupon.MaxUses:
+		return nil, apperror.ErrCouponUsedUp
+	}
+	return coupon, nil
+}
+
+// redeemerTier returns the user's tier, nil for none, and hides gifts
+// reserved for higher tiers
+func (s *redemptionService) redeemerTier(orgID, userID uint, gift *model.Gift) (*model.Tier, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TierID == nil && gift.MinTierID == nil {
+		return nil, nil
+	}
+
+	tiers, err := s.tierRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.TierAllows(tiers, user.TierID, gift.MinTierID) {
+		return nil, apperror.ErrNotFound
+	}
+	return model.FindTier(tiers, user.TierID), nil
+}
+
+func (s *redemptionService) Rate(orgID, userID, giftID uint, req dto.RatingRequest) (*dto.RatingResponse, error) {
 	// validate user has an unrated redemption for this gift
-	redemption, err := s.redemptionRepo.FindUnratedByUserAndGift(userID, giftID)
+	redemption, err := s.redemptionRepo.FindUnratedByUserAndGift(orgID, userID, giftID)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.giftRepo.FindByID(giftID)
+	_, err = s.giftRepo.FindByID(orgID, giftID)
 	if err != nil {
 		return nil, err
 	}
@@ -79,10 +226,11 @@ func (s *redemptionService) Rate(userID, giftID uint, req dto.RatingRequest) (*d
 	roundedScore := dto.RoundToHalf(req.Score)
 	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
 		rating = &model.Rating{
-			UserID:       userID,
-			GiftID:       giftID,
-			RedemptionID: redemption.ID,
-			Score:        roundedScore,
+			OrganisationID: orgID,
+			UserID:         userID,
+			GiftID:         giftID,
+			RedemptionID:   redemption.ID,
+			Score:          roundedScore,
 		}
 
 		if err := s.ratingRepo.Create(tx, rating); err != nil {
@@ -98,7 +246,7 @@ func (s *redemptionService) Rate(userID, giftID uint, req dto.RatingRequest) (*d
 	}
 
 	// fetch updated gift for fresh avg_rating
-	updatedGift, err := s.giftRepo.FindByID(giftID)
+	updatedGift, err := s.giftRepo.FindByID(orgID, giftID)
 	if err != nil {
 		return nil, err
 	}
@@ -2,12 +2,14 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
 	"github.com/gift-redemption/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // Note: Full transaction testing requires integration tests with real DB.
@@ -18,15 +20,15 @@ func TestRedemptionService_Redeem_GiftNotFound(t *testing.T) {
 	mockRedemptionRepo := new(mocks.MockRedemptionRepository)
 	mockRatingRepo := new(mocks.MockRatingRepository)
 
-	redemptionService := NewRedemptionService(nil, mockGiftRepo, mockRedemptionRepo, mockRatingRepo)
+	redemptionService := NewRedemptionService(nil, new(mocks.MockUserRepository), mockGiftRepo, mockRedemptionRepo, mockRatingRepo, new(mocks.MockTierRepository), new(mocks.MockPointRepository), new(mocks.MockCampaignRepository), new(mocks.MockFlashSaleRepository), new(mocks.MockCouponRepository))
 
-	mockGiftRepo.On("FindByID", uint(999)).Return(nil, apperror.ErrNotFound)
+	mockGiftRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
 
 	req := dto.RedemptionRequest{
 		Quantity: 1,
 	}
 
-	result, err := redemptionService.Redeem(1, 999, req)
+	result, err := redemptionService.Redeem(1, 1, 999, req)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
@@ -39,16 +41,16 @@ func TestRedemptionService_Rate_NotRedeemed(t *testing.T) {
 	mockRedemptionRepo := new(mocks.MockRedemptionRepository)
 	mockRatingRepo := new(mocks.MockRatingRepository)
 
-	redemptionService := NewRedemptionService(nil, mockGiftRepo, mockRedemptionRepo, mockRatingRepo)
+	redemptionService := NewRedemptionService(nil, new(mocks.MockUserRepository), mockGiftRepo, mockRedemptionRepo, mockRatingRepo, new(mocks.MockTierRepository), new(mocks.MockPointRepository), new(mocks.MockCampaignRepository), new(mocks.MockFlashSaleRepository), new(mocks.MockCouponRepository))
 
-	mockRedemptionRepo.On("FindUnratedByUserAndGift", uint(1), uint(1)).
+	mockRedemptionRepo.On("FindUnratedByUserAndGift", uint(1), uint(1), uint(1)).
 		Return(nil, apperror.ErrNotRedeemed)
 
 	req := dto.RatingRequest{
 		Score: 5,
 	}
 
-	result, err := redemptionService.Rate(1, 1, req)
+	result, err := redemptionService.Rate(1, 1, 1, req)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotRedeemed, err)
@@ -61,7 +63,7 @@ func TestRedemptionService_Rate_GiftNotFound(t *testing.T) {
 	mockRedemptionRepo := new(mocks.MockRedemptionRepository)
 	mockRatingRepo := new(mocks.MockRatingRepository)
 
-	redemptionService := NewRedemptionService(nil, mockGiftRepo, mockRedemptionRepo, mockRatingRepo)
+	redemptionService := NewRedemptionService(nil, new(mocks.MockUserRepository), mockGiftRepo, mockRedemptionRepo, mockRatingRepo, new(mocks.MockTierRepository), new(mocks.MockPointRepository), new(mocks.MockCampaignRepository), new(mocks.MockFlashSaleRepository), new(mocks.MockCouponRepository))
 
 	redemption := &model.Redemption{
 		ID:     1,
@@ -69,14 +71,14 @@ func TestRedemptionService_Rate_GiftNotFound(t *testing.T) {
 		GiftID: 999,
 	}
 
-	mockRedemptionRepo.On("FindUnratedByUserAndGift", uint(1), uint(999)).Return(redemption, nil)
-	mockGiftRepo.On("FindByID", uint(999)).Return(nil, apperror.ErrNotFound)
+	mockRedemptionRepo.On("FindUnratedByUserAndGift", uint(1), uint(1), uint(999)).Return(redemption, nil)
+	mockGiftRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
 
 	req := dto.RatingRequest{
 		Score: 5,
 	}
 
-	result, err := redemptionService.Rate(1, 999, req)
+	result, err := redemptionService.Rate(1, 1, 999, req)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
@@ -107,3 +109,102 @@ func TestRedemptionService_Rate_ValidationScore(t *testing.T) {
 		})
 	}
 }
+
+func TestRedemptionService_Redeem_BelowGiftTier(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTierRepo := new(mocks.MockTierRepository)
+
+	redemptionService := NewRedemptionService(nil, mockUserRepo, mockGiftRepo, new(mocks.MockRedemptionRepository), new(mocks.MockRatingRepository), mockTierRepo, new(mocks.MockPointRepository), new(mocks.MockCampaignRepository), new(mocks.MockFlashSaleRepository), new(mocks.MockCouponRepository))
+
+	silver, gold := uint(3), uint(4)
+	mockGiftRepo.On("FindByID", uint(1), uint(7)).Return(&model.Gift{ID: 7, Point: 100, Stock: 5, MinTierID: &gold}, nil)
+	mockUserRepo.On("FindByID", uint(5)).Return(&model.User{ID: 5, TierID: &silver}, nil)
+	mockTierRepo.On("FindAll", uint(1)).Return([]model.Tier{{ID: silver, MinPoints: 1000}, {ID: gold, MinPoints: 5000}}, nil)
+
+	result, err := redemptionService.Redeem(1, 5, 7, dto.RedemptionRequest{Quantity: 1})
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.Nil(t, result)
+	mockGiftRepo.AssertNotCalled(t, "DeductStock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRedemptionService_Redeem_FlashSaleQuantity(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockFlashSaleRepo := new(mocks.MockFlashSaleRepository)
+
+	redemptionService := NewRedemptionService(nil, mockUserRepo, mockGiftRepo, new(mocks.MockRedemptionRepository), new(mocks.MockRatingRepository), new(mocks.MockTierRepository), new(mocks.MockPointRepository), new(mocks.MockCampaignRepository), mockFlashSaleRepo, new(mocks.MockCouponRepository))
+
+	mockGiftRepo.On("FindByID", uint(1), uint(7)).Return(&model.Gift{ID: 7, Point: 100, Stock: 5}, nil)
+	mockUserRepo.On("FindByID", uint(5)).Return(&model.User{ID: 5}, nil)
+	mockFlashSaleRepo.On("FindRunning", uint(1), uint(7), mock.Anything).Return(&model.FlashSale{ID: 2, GiftID: 7, Quantity: 100}, nil)
+
+	result, err := redemptionService.Redeem(1, 5, 7, dto.RedemptionRequest{Quantity: 2})
+
+	assert.ErrorIs(t, err, apperror.ErrFlashSaleLimit)
+	assert.Nil(t, result)
+	mockFlashSaleRepo.AssertNotCalled(t, "ClaimUnit", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRedemptionService_Redeem_CouponNotValid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		coupon *model.Coupon
+		err    error
+	}{
+		{"unknown code", nil, apperror.ErrCouponNotValid},
+		{"expired", &model.Coupon{ID: 3, Code: "WELCOME50", DiscountType: model.DiscountPercent, DiscountValue: 50, EndsAt: &past}, apperror.ErrCouponNotValid},
+		{"other gift", &model.Coupon{ID: 3, Code: "WELCOME50", DiscountType: model.DiscountPercent, DiscountValue: 50, GiftIDs: model.UintList{8}}, apperror.ErrCouponNotValid},
+		{"below minimum", &model.Coupon{ID: 3, Code: "WELCOME50", DiscountType: model.DiscountPercent, DiscountValue: 50, MinPoints: 500}, apperror.ErrCouponNotValid},
+		{"used up", &model.Coupon{ID: 3, Code: "WELCOME50", DiscountType: model.DiscountFree, MaxUses: 10, Uses: 10}, apperror.ErrCouponUsedUp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGiftRepo := new(mocks.MockGiftRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			mockFlashSaleRepo := new(mocks.MockFlashSaleRepository)
+			mockCouponRepo := new(mocks.MockCouponRepository)
+
+			redemptionService := NewRedemptionService(nil, mockUserRepo, mockGiftRepo, new(mocks.MockRedemptionRepository), new(mocks.MockRatingRepository), new(mocks.MockTierRepository), new(mocks.MockPointRepository), noCampaigns(), mockFlashSaleRepo, mockCouponRepo)
+
+			mockGiftRepo.On("FindByID", uint(1), uint(7)).Return(&model.Gift{ID: 7, Point: 100, Stock: 5}, nil)
+			mockUserRepo.On("FindByID", uint(5)).Return(&model.User{ID: 5}, nil)
+			mockFlashSaleRepo.On("FindRunning", uint(1), uint(7), mock.Anything).Return(nil, apperror.ErrNotFound)
+			if tt.coupon == nil {
+				mockCouponRepo.On("FindByCode", uint(1), "WELCOME50").Return(nil, apperror.ErrNotFound)
+			} else {
+				mockCouponRepo.On("FindByCode", uint(1), "WELCOME50").Return(tt.coupon, nil)
+			}
+
+			result, err := redemptionService.Redeem(1, 5, 7, dto.RedemptionRequest{Quantity: 2, CouponCode: " welcome50 "})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Nil(t, result)
+			mockCouponRepo.AssertExpectations(t)
+			mockGiftRepo.AssertNotCalled(t, "DeductStock", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCoupon_Discount(t *testing.T) {
+	tests := []struct {
+		name      string
+		coupon    model.Coupon
+		unitPrice int
+		quantity  int
+		want      int
+	}{
+		{"percent of the total", model.Coupon{DiscountType: model.DiscountPercent, DiscountValue: 50}, 90, 3, 135},
+		{"fixed off the total", model.Coupon{DiscountType: model.DiscountFixed, DiscountValue: 50}, 90, 3, 50},
+		{"fixed capped at the total", model.Coupon{DiscountType: model.DiscountFixed, DiscountValue: 500}, 90, 3, 270},
+		{"free unit", model.Coupon{DiscountType: model.DiscountFree}, 90, 3, 90},
+		{"free unit of a free gift", model.Coupon{DiscountType: model.DiscountFree}, 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.coupon.Discount(tt.unitPrice, tt.quantity))
+		})
+	}
+}
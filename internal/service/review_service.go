@@ -0,0 +1,200 @@
+package service
+
+import (
+	"log"
+	"math"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/pkg/response"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReviewService interface {
+	GetByGift(orgID, giftID uint, query dto.ReviewQuery) ([]dto.ReviewResponse, *response.Pagination, error)
+	Vote(orgID, userID, ratingID uint, req dto.ReviewVoteRequest) (*dto.ReviewResponse, error)
+	Unvote(orgID, userID, ratingID uint) error
+	Hide(orgID, ratingID uint) error
+	Reply(orgID, adminID, ratingID uint, req dto.ReviewReplyRequest) (*dto.ReviewResponse, error)
+}
+
+type reviewService struct {
+	db         *gorm.DB
+	giftRepo   repository.GiftRepository
+	ratingRepo repository.RatingRepository
+	voteRepo   repository.ReviewVoteRepository
+	replyRepo  repository.ReviewReplyRepository
+	notifier   notifier.Notifier
+}
+
+func NewReviewService(
+	db *gorm.DB,
+	giftRepo repository.GiftRepository,
+	ratingRepo repository.RatingRepository,
+	voteRepo repository.ReviewVoteRepository,
+	replyRepo repository.ReviewReplyRepository,
+	notifier notifier.Notifier,
+) ReviewService {
+	return &reviewService{db, giftRepo, ratingRepo, voteRepo, replyRepo, notifier}
+}
+
+func (s *reviewService) GetByGift(orgID, giftID uint, query dto.ReviewQuery) ([]dto.ReviewResponse, *response.Pagination, error) {
+	if _, err := s.giftRepo.FindByID(orgID, giftID); err != nil {
+		return nil, nil, err
+	}
+
+	query.Normalize()
+
+	filter := repository.ReviewFilter{
+		OrganisationID: orgID,
+		GiftID:         giftID,
+		Page:           query.Page,
+		Limit:          query.Limit,
+		SortBy:         query.SortBy,
+		SortDir:        query.SortDir,
+	}
+
+	ratings, total, err := s.ratingRepo.FindVisibleByGift(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]dto.ReviewResponse, len(ratings))
+	for i, r := range ratings {
+		result[i] = dto.ToReviewResponse(r)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+	pagination := &response.Pagination{
+		CurrentPage: query.Page,
+		PerPage:     query.Limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+
+	return result, pagination, nil
+}
+
+func (s *reviewService) Vote(orgID, userID, ratingID uint, req dto.ReviewVoteRequest) (*dto.ReviewResponse, error) {
+	rating, err := s.findVotable(orgID, userID, ratingID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		// a moderator may have hidden the review since it was loaded
+		if _, err := s.ratingRepo.FindVisibleForUpdate(tx, ratingID); err != nil {
+			return err
+		}
+		vote := &model.ReviewVote{
+			RatingID:  ratingID,
+			UserID:    userID,
+			IsHelpful: *req.IsHelpful,
+		}
+		if err := s.voteRepo.Upsert(tx, vote); err != nil {
+			return err
+		}
+		return s.ratingRepo.UpdateVoteStats(tx, ratingID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch updated rating for fresh counters
+	rating, err = s.ratingRepo.FindByID(orgID, rating.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToReviewResponse(*rating)
+	return &res, nil
+}
+
+func (s *reviewService) Unvote(orgID, userID, ratingID uint) error {
+	if _, err := s.findVotable(orgID, userID, ratingID); err != nil {
+		return err
+	}
+
+	return repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if _, err := s.ratingRepo.FindVisibleForUpdate(tx, ratingID); err != nil {
+			return err
+		}
+		deleted, err := s.voteRepo.Delete(tx, ratingID, userID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return apperror.ErrNotFound
+		}
+		return s.ratingRepo.UpdateVoteStats(tx, ratingID)
+	})
+}
+
+// Hide removes a rating from the public listing and drops its votes.
+// avg_rating is left untouched so gift statistics stay stable.
+func (s *reviewService) Hide(orgID, ratingID uint) error {
+	if _, err := s.ratingRepo.FindByID(orgID, ratingID); err != nil {
+		return err
+	}
+
+	// hiding first locks the rating, so a vote still in flight commits before
+	// its votes are dropped and any later one finds the review hidden
+	return repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.ratingRepo.Hide(tx, ratingID); err != nil {
+			return err
+		}
+		return s.voteRepo.DeleteByRating(tx, ratingID)
+	})
+}
+
+func (s *reviewService) Reply(orgID, adminID, ratingID uint, req dto.ReviewReplyRequest) (*dto.ReviewResponse, error) {
+	rating, err := s.ratingRepo.FindByID(orgID, ratingID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.ReviewReply{
+		RatingID: ratingID,
+		AdminID:  adminID,
+		Body:     req.Body,
+	}
+	if err := s.replyRepo.Upsert(reply); err != nil {
+		return nil, err
+	}
+	rating.Reply = reply
+
+	// notification failure must not fail the reply itself
+	event := notifier.Event{
+		Type:   notifier.EventReviewReplied,
+		UserID: rating.UserID,
+		Payload: map[string]interface{}{
+			"rating_id": rating.ID,
+			"gift_id":   rating.GiftID,
+			"reply":     reply.Body,
+		},
+	}
+	if err := s.notifier.Notify(event); err != nil {
+		log.Printf("notify review reply %d: %v", rating.ID, err)
+	}
+
+	res := dto.ToReviewResponse(*rating)
+	return &res, nil
+}
+
+// findVotable loads a rating and checks the user is allowed to vote on it
+func (s *reviewService) findVotable(orgID, userID, ratingID uint) (*model.Rating, error) {
+	rating, err := s.ratingRepo.FindByID(orgID, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.IsHidden() {
+		return nil, apperror.ErrNotFound
+	}
+	if rating.UserID == userID {
+		return nil, apperror.ErrOwnReview
+	}
+	return rating, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewService_GetByGift_MostHelpful(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	ratings := []model.Rating{
+		{ID: 2, GiftID: 1, UserID: 3, Score: 4, HelpfulCount: 7, User: &model.User{Name: "Jane"}, CreatedAt: time.Now()},
+		{ID: 1, GiftID: 1, UserID: 2, Score: 5, HelpfulCount: 1, CreatedAt: time.Now()},
+	}
+
+	filter := repository.ReviewFilter{
+		OrganisationID: 1,
+		GiftID:         1,
+		Page:           1,
+		Limit:          10,
+		SortBy:         "most_helpful",
+		SortDir:        "desc",
+	}
+
+	mockGiftRepo.On("FindByID", uint(1), uint(1)).Return(&model.Gift{ID: 1}, nil)
+	mockRatingRepo.On("FindVisibleByGift", filter).Return(ratings, int64(2), nil)
+
+	result, pagination, err := reviewService.GetByGift(1, 1, dto.ReviewQuery{SortBy: "most_helpful"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "Jane", result[0].UserName)
+	assert.Equal(t, 7, result[0].HelpfulCount)
+	assert.Equal(t, int64(2), pagination.Total)
+	mockRatingRepo.AssertExpectations(t)
+}
+
+func TestReviewService_GetByGift_GiftNotFound(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockGiftRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
+
+	result, pagination, err := reviewService.GetByGift(1, 999, dto.ReviewQuery{})
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	assert.Nil(t, result)
+	assert.Nil(t, pagination)
+	mockRatingRepo.AssertNotCalled(t, "FindVisibleByGift")
+}
+
+func TestReviewService_Vote_OwnReview(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(1)).Return(&model.Rating{ID: 1, UserID: 5}, nil)
+
+	helpful := true
+	result, err := reviewService.Vote(1, 5, 1, dto.ReviewVoteRequest{IsHelpful: &helpful})
+
+	assert.Equal(t, apperror.ErrOwnReview, err)
+	assert.Nil(t, result)
+	mockVoteRepo.AssertNotCalled(t, "Upsert")
+}
+
+func TestReviewService_Vote_HiddenReview(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	hiddenAt := time.Now()
+	mockRatingRepo.On("FindByID", uint(1), uint(1)).Return(&model.Rating{ID: 1, UserID: 5, HiddenAt: &hiddenAt}, nil)
+
+	helpful := false
+	result, err := reviewService.Vote(1, 6, 1, dto.ReviewVoteRequest{IsHelpful: &helpful})
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	assert.Nil(t, result)
+	mockVoteRepo.AssertNotCalled(t, "Upsert")
+}
+
+func TestReviewService_Hide_NotFound(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
+
+	err := reviewService.Hide(1, 999)
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	mockVoteRepo.AssertNotCalled(t, "DeleteByRating")
+}
+
+func TestReviewService_Reply_Success(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(1)).Return(&model.Rating{ID: 1, UserID: 7, GiftID: 3}, nil)
+	mockReplyRepo.On("Upsert", mock.MatchedBy(func(r *model.ReviewReply) bool {
+		return r.RatingID == 1 && r.AdminID == 99 && r.Body == "Sorry, we will send a replacement"
+	})).Return(nil)
+	mockNotifier.On("Notify", mock.MatchedBy(func(e notifier.Event) bool {
+		return e.Type == notifier.EventReviewReplied && e.UserID == 7
+	})).Return(nil)
+
+	result, err := reviewService.Reply(1, 99, 1, dto.ReviewReplyRequest{Body: "Sorry, we will send a replacement"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Reply)
+	assert.Equal(t, "Sorry, we will send a replacement", result.Reply.Body)
+	mockReplyRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestReviewService_Reply_NotifierFailureIgnored(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(1)).Return(&model.Rating{ID: 1, UserID: 7}, nil)
+	mockReplyRepo.On("Upsert", mock.Anything).Return(nil)
+	mockNotifier.On("Notify", mock.Anything).Return(errors.New("smtp down"))
+
+	result, err := reviewService.Reply(1, 99, 1, dto.ReviewReplyRequest{Body: "Thanks"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestReviewService_Reply_ReviewNotFound(t *testing.T) {
+	mockGiftRepo := new(mocks.MockGiftRepository)
+	mockRatingRepo := new(mocks.MockRatingRepository)
+	mockVoteRepo := new(mocks.MockReviewVoteRepository)
+	mockReplyRepo := new(mocks.MockReviewReplyRepository)
+	mockNotifier := new(mocks.MockNotifier)
+
+	reviewService := NewReviewService(nil, mockGiftRepo, mockRatingRepo, mockVoteRepo, mockReplyRepo, mockNotifier)
+
+	mockRatingRepo.On("FindByID", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
+
+	result, err := reviewService.Reply(1, 99, 999, dto.ReviewReplyRequest{Body: "Thanks"})
+
+	assert.Equal(t, apperror.ErrNotFound, err)
+	assert.Nil(t, result)
+	mockReplyRepo.AssertNotCalled(t, "Upsert")
+	mockNotifier.AssertNotCalled(t, "Notify")
+}
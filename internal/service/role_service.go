@@ -0,0 +1,249 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditRoleCreate = "role.created"
+	AuditRoleUpdate = "role.updated"
+	AuditRoleDelete = "role.deleted"
+
+	// permissions are resolved on every request; other instances pick up
+	// role edits within this window
+	rolePermissionsTTL = 30 * time.Second
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+type RoleService interface {
+	GetPermissions() []dto.PermissionResponse
+	GetAll() ([]dto.RoleResponse, error)
+	GetByID(id uint) (*dto.RoleResponse, error)
+	Create(adminID uint, req dto.CreateRoleRequest, ip string) (*dto.RoleResponse, error)
+	Update(adminID, id uint, req dto.UpdateRoleRequest, ip string) (*dto.RoleResponse, error)
+	Delete(adminID, id uint, ip string) error
+	// PermissionsForRole returns the permissions granted to a role name, cached briefly
+	PermissionsForRole(role string) ([]string, error)
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+type roleService struct {
+	db        *gorm.DB
+	roleRepo  repository.RoleRepository
+	auditRepo repository.AuditRepository
+	now       func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cachedPermissions
+}
+
+func NewRoleService(db *gorm.DB, roleRepo repository.RoleRepository, auditRepo repository.AuditRepository) RoleService {
+	return &roleService{
+		db:        db,
+		roleRepo:  roleRepo,
+		auditRepo: auditRepo,
+		now:       time.Now,
+		cache:     make(map[string]cachedPermissions),
+	}
+}
+
+func (s *roleService) GetPermissions() []dto.PermissionResponse {
+	perms := model.Permissions()
+	result := make([]dto.PermissionResponse, len(perms))
+	for i, p := range perms {
+		result[i] = dto.ToPermissionResponse(p)
+	}
+	return result
+}
+
+func (s *roleService) GetAll() ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.RoleResponse, len(roles))
+	for i, r := range roles {
+		result[i] = dto.ToRoleResponse(r)
+	}
+	return result, nil
+}
+
+func (s *roleService) GetByID(id uint) (*dto.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	res := dto.ToRoleResponse(*role)
+	return &res, nil
+}
+
+func (s *roleService) Create(adminID uint, req dto.CreateRoleRequest, ip string) (*dto.RoleResponse, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, apperror.ErrInvalidRoleName
+	}
+	perms, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{Name: req.Name, Description: req.Description}
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.roleRepo.Create(tx, role); err != nil {
+			return err
+		}
+		return s.roleRepo.ReplacePermissions(tx, role.ID, perms)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(adminID, AuditRoleCreate, role, ip, model.JSONMap{"permissions": perms})
+	return s.GetByID(role.ID)
+}
+
+func (s *roleService) Update(adminID, id uint, req dto.UpdateRoleRequest, ip string) (*dto.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// admin roles hold fixed permissions so they can never be locked out
+	if _, ok := model.BuiltInPermissions(model.UserRole(role.Name)); ok {
+		return nil, apperror.ErrSystemRole
+	}
+	perms, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	before := role.PermissionNames()
+	role.Description = req.Description
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.roleRepo.Update(tx, role); err != nil {
+			return err
+		}
+		return s.roleRepo.ReplacePermissions(tx, role.ID, perms)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(role.Name)
+
+	s.audit(adminID, AuditRoleUpdate, role, ip, model.JSONMap{"before": before, "after": perms})
+	return s.GetByID(role.ID)
+}
+
+func (s *roleService) Delete(adminID, id uint, ip string) error {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return apperror.ErrSystemRole
+	}
+
+	count, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperror.ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate(role.Name)
+
+	s.audit(adminID, AuditRoleDelete, role, ip, nil)
+	return nil
+}
+
+func (s *roleService) PermissionsForRole(name string) ([]string, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	cached, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	// built-in admin roles never depend on the database
+	if perms, ok := model.BuiltInPermissions(model.UserRole(name)); ok {
+		return perms, nil
+	}
+
+	var perms []string
+	role, err := s.roleRepo.FindByName(name)
+	switch {
+	case err == nil:
+		perms = role.PermissionNames()
+	case errors.Is(err, apperror.ErrNotFound):
+		perms = []string{}
+	default:
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[name] = cachedPermissions{perms, now.Add(rolePermissionsTTL)}
+	s.mu.Unlock()
+	return perms, nil
+}
+
+func (s *roleService) invalidate(name string) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+}
+
+func (s *roleService) audit(adminID uint, action string, role *model.Role, ip string, metadata model.JSONMap) {
+	entry := &model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     action,
+		TargetType: "role",
+		TargetID:   strconv.FormatUint(uint64(role.ID), 10),
+		IP:         ip,
+		Metadata:   model.JSONMap{"name": role.Name},
+	}
+	for k, v := range metadata {
+		entry.Metadata[k] = v
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
+
+// normalizePermissions validates and de-duplicates a permission list
+func normalizePermissions(perms []string) ([]string, error) {
+	result := make([]string, 0, len(perms))
+	seen := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		if !model.IsValidPermission(p) {
+			return nil, fmt.Errorf("%w: %s", apperror.ErrInvalidPermission, p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoleService(roleRepo *mocks.MockRoleRepository, auditRepo *mocks.MockAuditRepository, now *time.Time) RoleService {
+	svc := NewRoleService(nil, roleRepo, auditRepo)
+	svc.(*roleService).now = func() time.Time { return *now }
+	return svc
+}
+
+func TestRoleService_PermissionsForRole_Cached(t *testing.T) {
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+	mockRoleRepo.On("FindByName", "catalog_manager").Return(&model.Role{
+		Name: "catalog_manager",
+		Permissions: []model.RolePermission{
+			{Permission: model.PermGiftsRead},
+			{Permission: model.PermGiftsWrite},
+		},
+	}, nil)
+
+	perms, err := roleService.PermissionsForRole("catalog_manager")
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.PermGiftsRead, model.PermGiftsWrite}, perms)
+
+	_, err = roleService.PermissionsForRole("catalog_manager")
+	require.NoError(t, err)
+	mockRoleRepo.AssertNumberOfCalls(t, "FindByName", 1)
+
+	now = now.Add(rolePermissionsTTL)
+	_, err = roleService.PermissionsForRole("catalog_manager")
+	require.NoError(t, err)
+	mockRoleRepo.AssertNumberOfCalls(t, "FindByName", 2)
+}
+
+func TestRoleService_PermissionsForRole_BuiltInAdmins(t *testing.T) {
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Now()
+	roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+	perms, err := roleService.PermissionsForRole("super_admin")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, model.AllPermissionNames(), perms)
+
+	perms, err = roleService.PermissionsForRole("admin")
+	require.NoError(t, err)
+	assert.Contains(t, perms, model.PermGiftsWrite)
+	assert.NotContains(t, perms, model.PermOrgsManage)
+	assert.NotContains(t, perms, model.PermRolesManage)
+
+	mockRoleRepo.AssertNotCalled(t, "FindByName", mock.Anything)
+}
+
+func TestRoleService_PermissionsForRole_UnknownRole(t *testing.T) {
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Now()
+	roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+	mockRoleRepo.On("FindByName", "deleted_role").Return(nil, apperror.ErrNotFound)
+
+	perms, err := roleService.PermissionsForRole("deleted_role")
+
+	require.NoError(t, err)
+	assert.Empty(t, perms)
+}
+
+func TestRoleService_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.CreateRoleRequest
+		wantErr error
+	}{
+		{"uppercase name", dto.CreateRoleRequest{Name: "Support", Permissions: []string{}}, apperror.ErrInvalidRoleName},
+		{"name with spaces", dto.CreateRoleRequest{Name: "support agent", Permissions: []string{}}, apperror.ErrInvalidRoleName},
+		{"unknown permission", dto.CreateRoleRequest{Name: "support", Permissions: []string{"gifts:steal"}}, apperror.ErrInvalidPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoleRepo := new(mocks.MockRoleRepository)
+			mockAuditRepo := new(mocks.MockAuditRepository)
+			now := time.Now()
+			roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+			_, err := roleService.Create(1, tt.req, "10.0.0.1")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockRoleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRoleService_Update_AdminIsImmutable(t *testing.T) {
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	now := time.Now()
+	roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+	mockRoleRepo.On("FindByID", uint(1)).Return(&model.Role{ID: 1, Name: "admin", IsSystem: true}, nil)
+
+	_, err := roleService.Update(1, 1, dto.UpdateRoleRequest{Permissions: []string{model.PermGiftsRead}}, "10.0.0.1")
+
+	assert.ErrorIs(t, err, apperror.ErrSystemRole)
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    *model.Role
+		users   int64
+		wantErr error
+	}{
+		{"system role", &model.Role{ID: 2, Name: "user", IsSystem: true}, 0, apperror.ErrSystemRole},
+		{"still assigned", &model.Role{ID: 3, Name: "auditor"}, 2, apperror.ErrRoleInUse},
+		{"unused custom role", &model.Role{ID: 3, Name: "auditor"}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoleRepo := new(mocks.MockRoleRepository)
+			mockAuditRepo := new(mocks.MockAuditRepository)
+			now := time.Now()
+			roleService := newTestRoleService(mockRoleRepo, mockAuditRepo, &now)
+
+			mockRoleRepo.On("FindByID", tt.role.ID).Return(tt.role, nil)
+			mockRoleRepo.On("CountUsers", tt.role.Name).Return(tt.users, nil)
+			mockRoleRepo.On("Delete", tt.role.ID).Return(nil)
+			mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+				return e.Action == AuditRoleDelete
+			})).Return(nil)
+
+			err := roleService.Delete(1, tt.role.ID, "10.0.0.1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRoleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRoleRepo.AssertCalled(t, "Delete", tt.role.ID)
+			mockAuditRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
+)
+
+// tierEvaluationBatchSize is how many users are loaded at a time during evaluation
+const tierEvaluationBatchSize = 500
+
+// TierService manages an organisation's loyalty tiers and places users in
+// them by the points they redeemed over the qualifying window.
+type TierService interface {
+	GetAll(orgID uint) ([]dto.TierResponse, error)
+	Create(orgID uint, req dto.TierRequest) (*dto.TierResponse, error)
+	Update(orgID, id uint, req dto.TierRequest) (*dto.TierResponse, error)
+	Delete(orgID, id uint) error
+	// Evaluate re-places every active user of the organisation and records
+	// each tier change
+	Evaluate(orgID uint) (*dto.TierEvaluationResponse, error)
+	// EvaluateAll evaluates every organisation; run by the scheduler
+	EvaluateAll(ctx context.Context) error
+}
+
+type tierService struct {
+	tierRepo       repository.TierRepository
+	userRepo       repository.UserRepository
+	redemptionRepo repository.RedemptionRepository
+	orgRepo        repository.OrganisationRepository
+	cfg            config.TierConfig
+	now            func() time.Time
+}
+
+func NewTierService(
+	tierRepo repository.TierRepository,
+	userRepo repository.UserRepository,
+	redemptionRepo repository.RedemptionRepository,
+	orgRepo repository.OrganisationRepository,
+	cfg config.TierConfig,
+) TierService {
+	return &tierService{tierRepo, userRepo, redemptionRepo, orgRepo, cfg, time.Now}
+}
+
+func (s *tierService) GetAll(orgID uint) ([]dto.TierResponse, error) {
+	tiers, err := s.tierRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.TierResponse, len(tiers))
+	for i, t := range tiers {
+		res[i] = dto.ToTierResponse(t)
+	}
+	return res, nil
+}
+
+func (s *tierService) Create(orgID uint, req dto.TierRequest) (*dto.TierResponse, error) {
+	tier := &model.Tier{
+		OrganisationID:   orgID,
+		Name:             req.Name,
+		MinPoints:        req.MinPoints,
+		DiscountPercent:  req.DiscountPercent,
+		Benefits:         req.Benefits,
+		TransfersEnabled: req.AllowsTransfers(),
+	}
+	if err := s.tierRepo.Create(tier); err != nil {
+		return nil, err
+	}
+	res := dto.ToTierResponse(*tier)
+	return &res, nil
+}
+
+// Update takes effect on prices at once; users move at the next evaluation
+func (s *tierService) Update(orgID, id uint, req dto.TierRequest) (*dto.TierResponse, error) {
+	tier, err := s.tierRepo.FindByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tier.Name = req.Name
+	tier.MinPoints = req.MinPoints
+	tier.DiscountPercent = req.DiscountPercent
+	tier.Benefits = req.Benefits
+	tier.TransfersEnabled = req.AllowsTransfers()
+
+	if err := s.tierRepo.Update(tier); err != nil {
+		return nil, err
+	}
+	res := dto.ToTierResponse(*tier)
+	return &res, nil
+}
+
+func (s *tierService) Delete(orgID, id uint) error {
+	return s.tierRepo.Delete(orgID, id)
+}
+
+func (s *tierService) Evaluate(orgID uint) (*dto.TierEvaluationResponse, error) {
+	tiers, err := s.tierRepo.FindAll(orgID)
+	if err != nil {
+		return nil, err
+	}
+	points, err := s.redemptionRepo.PointsRedeemedSince(orgID, s.now().Add(-s.cfg.QualifyingWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.TierEvaluationResponse{}
+	filter := repository.UserFilter{OrganisationID: orgID, Deleted: repository.DeletedExclude}
+	err = s.userRepo.FindInBatches(filter, tierEvaluationBatchSize, func(users []model.User) error {
+		for _, user := range users {
+			res.UsersEvaluated++
+
+			current := model.FindTier(tiers, user.TierID)
+			target := model.TierFor(tiers, points[user.ID])
+			if tierID(current) == tierID(target) {
+				continue
+			}
+
+			change := &model.TierChange{
+				OrganisationID:   orgID,
+				UserID:           user.ID,
+				FromTierID:       user.TierID,
+				FromTierName:     tierName(current),
+				ToTierID:         tierIDPtr(target),
+				ToTierName:       tierName(target),
+				QualifyingPoints: points[user.ID],
+			}
+			applied, err := s.tierRepo.ApplyChange(change)
+			if err != nil {
+				return fmt.Errorf("change tier of user %d: %w", user.ID, err)
+			}
+			if !applied {
+				continue
+			}
+			if tierRank(target) > tierRank(current) {
+				res.Promoted++
+			} else {
+				res.Demoted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *tierService) EvaluateAll(ctx context.Context) error {
+	orgs, err := s.orgRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, org := range orgs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res, err := s.Evaluate(org.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organisation %d: %w", org.ID, err))
+			continue
+		}
+		if res.Promoted > 0 || res.Demoted > 0 {
+			log.Printf("tiers: organisation %d: %d promoted, %d demoted", org.ID, res.Promoted, res.Demoted)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func tierID(t *model.Tier) uint {
+	if t == nil {
+		return 0
+	}
+	return t.ID
+}
+
+func tierIDPtr(t *model.Tier) *uint {
+	if t == nil {
+		return nil
+	}
+	id := t.ID
+	return &id
+}
+
+func tierName(t *model.Tier) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// tierRank orders tiers by threshold; no tier ranks below every tier
+func tierRank(t *model.Tier) int {
+	if t == nil {
+		return -1
+	}
+	return t.MinPoints
+}
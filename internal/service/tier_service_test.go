@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type tierMocks struct {
+	tiers       *mocks.MockTierRepository
+	users       *mocks.MockUserRepository
+	redemptions *mocks.MockRedemptionRepository
+	orgs        *mocks.MockOrganisationRepository
+}
+
+func newTestTierService() (*tierService, tierMocks) {
+	m := tierMocks{
+		tiers:       new(mocks.MockTierRepository),
+		users:       new(mocks.MockUserRepository),
+		redemptions: new(mocks.MockRedemptionRepository),
+		orgs:        new(mocks.MockOrganisationRepository),
+	}
+	cfg := config.TierConfig{EvaluationInterval: 24 * time.Hour, QualifyingWindow: 365 * 24 * time.Hour}
+	svc := NewTierService(m.tiers, m.users, m.redemptions, m.orgs, cfg).(*tierService)
+	return svc, m
+}
+
+func TestTierService_Evaluate(t *testing.T) {
+	svc, m := newTestTierService()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	silver, gold := uint(3), uint(4)
+	m.tiers.On("FindAll", uint(1)).Return([]model.Tier{
+		{ID: silver, Name: "Silver", MinPoints: 1000},
+		{ID: gold, Name: "Gold", MinPoints: 5000},
+	}, nil)
+	m.redemptions.On("PointsRedeemedSince", uint(1), now.AddDate(-1, 0, 0)).Return(map[uint]int64{
+		10: 6000, // none -> gold
+		11: 1500, // gold -> silver
+		12: 1200, // stays silver
+		13: 200,  // silver -> none, lost to a concurrent evaluation
+	}, nil)
+	m.users.On("FindInBatches", repository.UserFilter{OrganisationID: 1, Deleted: repository.DeletedExclude}, tierEvaluationBatchSize).
+		Return([]model.User{{ID: 10}, {ID: 11, TierID: &gold}, {ID: 12, TierID: &silver}, {ID: 13, TierID: &silver}}, nil)
+	m.tiers.On("ApplyChange", mock.MatchedBy(func(c *model.TierChange) bool { return c.UserID == 13 })).Return(false, nil)
+	m.tiers.On("ApplyChange", mock.AnythingOfType("*model.TierChange")).Return(true, nil)
+
+	res, err := svc.Evaluate(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, res.UsersEvaluated)
+	assert.Equal(t, 1, res.Promoted)
+	assert.Equal(t, 1, res.Demoted)
+	m.tiers.AssertCalled(t, "ApplyChange", mock.MatchedBy(func(c *model.TierChange) bool {
+		return c.UserID == 10 && c.FromTierID == nil && c.FromTierName == "" &&
+			*c.ToTierID == gold && c.ToTierName == "Gold" && c.QualifyingPoints == 6000
+	}))
+	m.tiers.AssertCalled(t, "ApplyChange", mock.MatchedBy(func(c *model.TierChange) bool {
+		return c.UserID == 11 && *c.FromTierID == gold && c.FromTierName == "Gold" && *c.ToTierID == silver
+	}))
+	m.tiers.AssertNotCalled(t, "ApplyChange", mock.MatchedBy(func(c *model.TierChange) bool { return c.UserID == 12 }))
+}
+
+func TestTierService_EvaluateAll_ContinuesAfterFailure(t *testing.T) {
+	svc, m := newTestTierService()
+
+	m.orgs.On("FindAll").Return([]model.Organisation{{ID: 1}, {ID: 2}}, nil)
+	m.tiers.On("FindAll", uint(1)).Return([]model.Tier(nil), errors.New("db down"))
+	m.tiers.On("FindAll", uint(2)).Return([]model.Tier{}, nil)
+	m.redemptions.On("PointsRedeemedSince", uint(2), mock.AnythingOfType("time.Time")).Return(map[uint]int64{}, nil)
+	m.users.On("FindInBatches", mock.AnythingOfType("repository.UserFilter"), tierEvaluationBatchSize).Return([]model.User{}, nil)
+
+	err := svc.EvaluateAll(context.Background())
+
+	assert.ErrorContains(t, err, "organisation 1: db down")
+	m.users.AssertCalled(t, "FindInBatches", repository.UserFilter{OrganisationID: 2, Deleted: repository.DeletedExclude}, tierEvaluationBatchSize)
+}
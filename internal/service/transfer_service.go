@@ -0,0 +1,270 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/notifier"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+// transferHistoryLimit is how many transfers the history shows
+const transferHistoryLimit = 50
+
+// TransferService moves points between colleagues of one organisation under
+// the organisation's transfer policy.
+type TransferService interface {
+	// Send debits the sender and credits the recipient in one transaction.
+	// The recipient's points keep the expiry of the lots they came from.
+	Send(senderID uint, req dto.TransferRequest) (*dto.TransferResponse, error)
+	// History lists the transfers the user sent or received, newest first
+	History(userID uint) ([]dto.TransferResponse, error)
+	GetPolicy(orgID uint) (*dto.TransferPolicyResponse, error)
+	UpdatePolicy(orgID, adminID uint, req dto.TransferPolicyRequest, ip string) (*dto.TransferPolicyResponse, error)
+}
+
+type transferService struct {
+	db           *gorm.DB
+	userRepo     repository.UserRepository
+	tierRepo     repository.TierRepository
+	pointRepo    repository.PointRepository
+	transferRepo repository.TransferRepository
+	auditRepo    repository.AuditRepository
+	notifier     notifier.Notifier
+	now          func() time.Time
+}
+
+func NewTransferService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	tierRepo repository.TierRepository,
+	pointRepo repository.PointRepository,
+	transferRepo repository.TransferRepository,
+	auditRepo repository.AuditRepository,
+	n notifier.Notifier,
+) TransferService {
+	return &transferService{db, userRepo, tierRepo, pointRepo, transferRepo, auditRepo, n, time.Now}
+}
+
+func (s *transferService) Send(senderID uint, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	sender, err := s.userRepo.FindByID(senderID)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := s.findRecipient(sender, req.RecipientEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.transferRepo.FindPolicy(sender.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, apperror.ErrTransfersDisabled
+	}
+	if err := s.checkTier(sender); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	transfer := &model.PointTransfer{
+		OrganisationID: sender.OrganisationID,
+		SenderID:       sender.ID,
+		RecipientID:    recipient.ID,
+		Points:         req.Points,
+		Message:        req.Message,
+	}
+
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		balances, err := s.pointRepo.LockBalances(tx, sender.ID, recipient.ID)
+		if err != nil {
+			return err
+		}
+
+		// the sender's lock serialises their transfers, so the total is exact
+		if policy.DailyLimit > 0 {
+			sent, err := s.transferRepo.SentSince(tx, sender.ID, startOfDay(now))
+			if err != nil {
+				return err
+			}
+			if sent+req.Points > policy.DailyLimit {
+				return fmt.Errorf("%w: %d of %d points left today", apperror.ErrTransferLimit, max(policy.DailyLimit-sent, 0), policy.DailyLimit)
+			}
+		}
+		balance := balances[sender.ID]
+		if balance < req.Points {
+			return apperror.ErrInsufficientPoints
+		}
+		if balance-req.Points < policy.MinBalance {
+			return fmt.Errorf("%w of %d points", apperror.ErrBelowMinBalance, policy.MinBalance)
+		}
+
+		if err := s.userRepo.DebitPoints(tx, sender.ID, req.Points); err != nil {
+			return err
+		}
+		consumed, err := s.pointRepo.ConsumeLots(tx, sender.ID, req.Points, now)
+		if err != nil {
+			return err
+		}
+		if err := s.transferRepo.Create(tx, transfer); err != nil {
+			return err
+		}
+
+		// one lot per consumed lot keeps each point's original expiry
+		lots := make([]*model.PointLot, len(consumed))
+		for i, c := range consumed {
+			lots[i] = &model.PointLot{
+				OrganisationID: recipient.OrganisationID,
+				UserID:         recipient.ID,
+				Source:         model.LedgerTransferIn,
+				Points:         c.Points,
+				Remaining:      c.Points,
+				EarnedAt:       now,
+				ExpiresAt:      c.ExpiresAt,
+			}
+		}
+		if err := s.pointRepo.CreditLots(tx, recipient.ID, lots); err != nil {
+			return err
+		}
+
+		entries := []*model.PointLedgerEntry{
+			{UserID: sender.ID, Type: model.LedgerTransferOut, Points: -req.Points},
+			{UserID: recipient.ID, Type: model.LedgerTransferIn, Points: req.Points},
+		}
+		for _, entry := range entries {
+			entry.OrganisationID = transfer.OrganisationID
+			entry.TransferID = &transfer.ID
+			if err := s.pointRepo.CreateEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// notification failure must not fail the transfer itself
+	event := notifier.Event{
+		Type:   notifier.EventPointsReceived,
+		UserID: recipient.ID,
+		Payload: map[string]interface{}{
+			"transfer_id": transfer.ID,
+			"sender_name": sender.Name,
+			"points":      transfer.Points,
+			"message":     transfer.Message,
+		},
+	}
+	if err := s.notifier.Notify(event); err != nil {
+		log.Printf("notify transfer %d: %v", transfer.ID, err)
+	}
+
+	transfer.Sender = sender
+	transfer.Recipient = recipient
+	res := dto.ToTransferResponse(*transfer)
+	return &res, nil
+}
+
+// findRecipient resolves the recipient without telling a sender whether an
+// address belongs to another organisation
+func (s *transferService) findRecipient(sender *model.User, email string) (*model.User, error) {
+	recipient, err := s.userRepo.FindByEmail(email)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, fmt.Errorf("%w: no active colleague with this email", apperror.ErrInvalidRecipient)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if recipient.OrganisationID != sender.OrganisationID || recipient.IsErased() {
+		return nil, fmt.Errorf("%w: no active colleague with this email", apperror.ErrInvalidRecipient)
+	}
+	if recipient.ID == sender.ID {
+		return nil, fmt.Errorf("%w: cannot transfer points to yourself", apperror.ErrInvalidRecipient)
+	}
+	return recipient, nil
+}
+
+// checkTier rejects senders whose tier has transfers switched off; users
+// without a tier may transfer
+func (s *transferService) checkTier(sender *model.User) error {
+	if sender.TierID == nil {
+		return nil
+	}
+	tier, err := s.tierRepo.FindByID(sender.OrganisationID, *sender.TierID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !tier.TransfersEnabled {
+		return fmt.Errorf("%w for the %s tier", apperror.ErrTransfersDisabled, tier.Name)
+	}
+	return nil
+}
+
+func (s *transferService) History(userID uint) ([]dto.TransferResponse, error) {
+	transfers, err := s.transferRepo.FindByUser(userID, transferHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.TransferResponse, len(transfers))
+	for i, t := range transfers {
+		res[i] = dto.ToTransferResponse(t)
+	}
+	return res, nil
+}
+
+func (s *transferService) GetPolicy(orgID uint) (*dto.TransferPolicyResponse, error) {
+	policy, err := s.transferRepo.FindPolicy(orgID)
+	if err != nil {
+		return nil, err
+	}
+	res := dto.ToTransferPolicyResponse(*policy)
+	return &res, nil
+}
+
+func (s *transferService) UpdatePolicy(orgID, adminID uint, req dto.TransferPolicyRequest, ip string) (*dto.TransferPolicyResponse, error) {
+	policy := &model.TransferPolicy{
+		OrganisationID: orgID,
+		Enabled:        req.Enabled,
+		DailyLimit:     req.DailyLimit,
+		MinBalance:     req.MinBalance,
+	}
+	if err := s.transferRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	entry := &model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &adminID,
+		Action:     "transfers.policy_updated",
+		TargetType: "organisation",
+		TargetID:   strconv.FormatUint(uint64(orgID), 10),
+		IP:         ip,
+		Metadata: model.JSONMap{
+			"enabled":     policy.Enabled,
+			"daily_limit": policy.DailyLimit,
+			"min_balance": policy.MinBalance,
+		},
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+
+	res := dto.ToTransferPolicyResponse(*policy)
+	return &res, nil
+}
+
+// startOfDay returns midnight UTC of t's day, when daily limits reset
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type transferMocks struct {
+	users     *mocks.MockUserRepository
+	tiers     *mocks.MockTierRepository
+	transfers *mocks.MockTransferRepository
+	audit     *mocks.MockAuditRepository
+}
+
+func newTestTransferService() (*transferService, transferMocks) {
+	m := transferMocks{
+		users:     new(mocks.MockUserRepository),
+		tiers:     new(mocks.MockTierRepository),
+		transfers: new(mocks.MockTransferRepository),
+		audit:     new(mocks.MockAuditRepository),
+	}
+	svc := NewTransferService(nil, m.users, m.tiers, new(mocks.MockPointRepository), m.transfers, m.audit, new(mocks.MockNotifier)).(*transferService)
+	return svc, m
+}
+
+func TestTransferService_Send_InvalidRecipient(t *testing.T) {
+	sender := &model.User{ID: 1, OrganisationID: 10, Email: "ann@example.com"}
+
+	tests := []struct {
+		name      string
+		recipient *model.User
+		findErr   error
+	}{
+		{"unknown email", nil, apperror.ErrNotFound},
+		{"other organisation", &model.User{ID: 2, OrganisationID: 20}, nil},
+		{"erased user", &model.User{ID: 2, OrganisationID: 10, ErasedAt: &time.Time{}}, nil},
+		{"self", sender, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestTransferService()
+			m.users.On("FindByID", uint(1)).Return(sender, nil)
+			if tt.recipient == nil {
+				m.users.On("FindByEmail", "bob@example.com").Return(nil, tt.findErr)
+			} else {
+				m.users.On("FindByEmail", "bob@example.com").Return(tt.recipient, nil)
+			}
+
+			_, err := svc.Send(1, dto.TransferRequest{RecipientEmail: "bob@example.com", Points: 100})
+
+			assert.ErrorIs(t, err, apperror.ErrInvalidRecipient)
+			m.transfers.AssertNotCalled(t, "FindPolicy", mock.Anything)
+		})
+	}
+}
+
+func TestTransferService_Send_PolicyDisabled(t *testing.T) {
+	svc, m := newTestTransferService()
+	m.users.On("FindByID", uint(1)).Return(&model.User{ID: 1, OrganisationID: 10}, nil)
+	m.users.On("FindByEmail", "bob@example.com").Return(&model.User{ID: 2, OrganisationID: 10}, nil)
+	m.transfers.On("FindPolicy", uint(10)).Return(&model.TransferPolicy{OrganisationID: 10}, nil)
+
+	_, err := svc.Send(1, dto.TransferRequest{RecipientEmail: "bob@example.com", Points: 100})
+
+	assert.ErrorIs(t, err, apperror.ErrTransfersDisabled)
+}
+
+func TestTransferService_Send_TierDisabled(t *testing.T) {
+	svc, m := newTestTransferService()
+	tierID := uint(3)
+	m.users.On("FindByID", uint(1)).Return(&model.User{ID: 1, OrganisationID: 10, TierID: &tierID}, nil)
+	m.users.On("FindByEmail", "bob@example.com").Return(&model.User{ID: 2, OrganisationID: 10}, nil)
+	m.transfers.On("FindPolicy", uint(10)).Return(&model.TransferPolicy{OrganisationID: 10, Enabled: true}, nil)
+	m.tiers.On("FindByID", uint(10), tierID).Return(&model.Tier{ID: tierID, Name: "Bronze"}, nil)
+
+	_, err := svc.Send(1, dto.TransferRequest{RecipientEmail: "bob@example.com", Points: 100})
+
+	require.ErrorIs(t, err, apperror.ErrTransfersDisabled)
+	assert.Contains(t, err.Error(), "Bronze")
+}
+
+func TestTransferService_UpdatePolicy(t *testing.T) {
+	svc, m := newTestTransferService()
+	m.transfers.On("SavePolicy", mock.MatchedBy(func(p *model.TransferPolicy) bool {
+		return p.OrganisationID == 10 && p.Enabled && p.DailyLimit == 500 && p.MinBalance == 100
+	})).Return(nil)
+	m.audit.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == "transfers.policy_updated" && e.TargetID == "10"
+	})).Return(nil)
+
+	res, err := svc.UpdatePolicy(10, 7, dto.TransferPolicyRequest{Enabled: true, DailyLimit: 500, MinBalance: 100}, "127.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.TransferPolicyResponse{Enabled: true, DailyLimit: 500, MinBalance: 100}, *res)
+	m.audit.AssertExpectations(t)
+}
+
+func TestStartOfDay(t *testing.T) {
+	at := time.Date(2025, 6, 1, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), startOfDay(at))
+}
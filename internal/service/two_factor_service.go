@@ -0,0 +1,260 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/pkg/totp"
+	"github.com/gift-redemption/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts the previous and next step to tolerate clock drift
+	totpSkew = 1
+)
+
+type TwoFactorService interface {
+	GetStatus(userID uint) (*dto.TwoFactorStatusResponse, error)
+	// BeginSetup generates a new pending secret; 2FA stays off until ConfirmSetup
+	BeginSetup(userID uint) (*dto.TwoFactorSetupResponse, error)
+	ConfirmSetup(userID uint, code string) (*dto.RecoveryCodesResponse, error)
+	Disable(userID uint, req dto.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(userID uint, code string) (*dto.RecoveryCodesResponse, error)
+	// Verify checks an authenticator or recovery code for a user with 2FA enabled
+	Verify(user *model.User, code string) error
+	IsRequired(user *model.User) bool
+}
+
+type twoFactorService struct {
+	db           *gorm.DB
+	userRepo     repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	roleRepo     repository.RoleRepository
+	cfg          config.TwoFactorConfig
+	now          func() time.Time
+}
+
+func NewTwoFactorService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	roleRepo repository.RoleRepository,
+	cfg config.TwoFactorConfig,
+) TwoFactorService {
+	return &twoFactorService{db, userRepo, recoveryRepo, roleRepo, cfg, time.Now}
+}
+
+func (s *twoFactorService) GetStatus(userID uint) (*dto.TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.TwoFactorStatusResponse{
+		Enabled:  user.IsTwoFactorEnabled(),
+		Required: s.IsRequired(user),
+	}
+	if res.Enabled {
+		if res.RecoveryCodesRemaining, err = s.recoveryRepo.CountUnused(userID); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (s *twoFactorService) BeginSetup(userID uint) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	// replacing the secret of an enabled account would bypass the current factor
+	if user.IsTwoFactorEnabled() {
+		return nil, apperror.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTOTPSecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.ProvisioningURI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *twoFactorService) ConfirmSetup(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, apperror.ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, apperror.ErrTwoFactorNotSetUp
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, s.now(), totpSkew)
+	if !ok {
+		return nil, apperror.ErrInvalidOTP
+	}
+
+	var codes []string
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.userRepo.EnableTOTP(tx, userID, step); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) Disable(userID uint, req dto.DisableTwoFactorRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.CheckPassword(req.Password) {
+		return apperror.ErrInvalidPassword
+	}
+	if s.IsRequired(user) {
+		return apperror.ErrTwoFactorRequired
+	}
+	if err := s.Verify(user, req.Code); err != nil {
+		return err
+	}
+
+	return repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.userRepo.DisableTOTP(tx, userID); err != nil {
+			return err
+		}
+		return s.recoveryRepo.DeleteByUser(tx, userID)
+	})
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Verify(user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = repository.WithTransaction(s.db, func(tx *gorm.DB) error {
+		codes, err = s.replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) Verify(user *model.User, code string) error {
+	if !user.IsTwoFactorEnabled() {
+		return apperror.ErrTwoFactorNotSetUp
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, s.now(), totpSkew)
+		if !ok {
+			return apperror.ErrInvalidOTP
+		}
+		// a code that was already accepted must not work twice
+		advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return apperror.ErrInvalidOTP
+		}
+		return nil
+	}
+
+	used, err := s.recoveryRepo.Use(user.ID, securetoken.Hash(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return apperror.ErrInvalidOTP
+	}
+	return nil
+}
+
+// IsRequired covers admins, super admins and custom roles holding a platform
+// permission. It fails closed when the role cannot be looked up.
+func (s *twoFactorService) IsRequired(user *model.User) bool {
+	if !s.cfg.RequiredForAdmin {
+		return false
+	}
+	switch user.Role {
+	case model.RoleAdmin, model.RoleSuperAdmin:
+		return true
+	case model.RoleUser:
+		return false
+	}
+	role, err := s.roleRepo.FindByName(string(user.Role))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return false
+	}
+	return err != nil || role.IsPlatform()
+}
+
+func (s *twoFactorService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = securetoken.Hash(normalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryRepo.Replace(tx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+var base32NoPad = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns a code like "k7qxm-2vd4p" (50 bits of entropy)
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate recovery code: %w", err)
+	}
+	raw := strings.ToLower(base32NoPad.EncodeToString(b))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+// normalizeRecoveryCode lets users type codes without the dash or in upper case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/pkg/totp"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTestTwoFactorService(userRepo *mocks.MockUserRepository, recoveryRepo *mocks.MockRecoveryCodeRepository, cfg config.TwoFactorConfig, now time.Time) TwoFactorService {
+	svc := NewTwoFactorService(nil, userRepo, recoveryRepo, new(mocks.MockRoleRepository), cfg)
+	svc.(*twoFactorService).now = func() time.Time { return now }
+	return svc
+}
+
+func TestTwoFactorService_BeginSetup(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{Issuer: "Gift Redemption"}, time.Now())
+
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, Email: "admin@example.com"}, nil)
+	mockUserRepo.On("SetTOTPSecret", uint(1), mock.AnythingOfType("string")).Return(nil)
+
+	result, err := twoFactorService.BeginSetup(1)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Secret, 32)
+	assert.Contains(t, result.OTPAuthURI, "otpauth://totp/Gift%20Redemption:admin@example.com?")
+	assert.Contains(t, result.OTPAuthURI, "secret="+result.Secret)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_BeginSetup_AlreadyEnabled(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, time.Now())
+
+	now := time.Now()
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &now}, nil)
+
+	result, err := twoFactorService.BeginSetup(1)
+
+	assert.Equal(t, apperror.ErrTwoFactorEnabled, err)
+	assert.Nil(t, result)
+	mockUserRepo.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_ConfirmSetup_InvalidCode(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, time.Now())
+
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, TOTPSecret: testTOTPSecret}, nil)
+
+	result, err := twoFactorService.ConfirmSetup(1, "000000")
+
+	assert.Equal(t, apperror.ErrInvalidOTP, err)
+	assert.Nil(t, result)
+	mockUserRepo.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_ConfirmSetup_NotStarted(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, time.Now())
+
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1}, nil)
+
+	_, err := twoFactorService.ConfirmSetup(1, "123456")
+
+	assert.Equal(t, apperror.ErrTwoFactorNotSetUp, err)
+}
+
+func TestTwoFactorService_Verify_TOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := totp.Code(testTOTPSecret, totp.Step(now))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		code     string
+		advanced bool
+		wantErr  error
+	}{
+		{"valid code", code, true, nil},
+		{"replayed code", code, false, apperror.ErrInvalidOTP},
+		{"wrong code", "000000", false, apperror.ErrInvalidOTP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.MockUserRepository)
+			mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+			twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, now)
+
+			enabledAt := now
+			user := &model.User{ID: 1, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &enabledAt}
+			mockUserRepo.On("AdvanceTOTPStep", uint(1), totp.Step(now)).Return(tt.advanced, nil)
+
+			err := twoFactorService.Verify(user, tt.code)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockRecoveryRepo.AssertNotCalled(t, "Use", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTwoFactorService_Verify_RecoveryCode(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, time.Now())
+
+	now := time.Now()
+	user := &model.User{ID: 1, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &now}
+	mockRecoveryRepo.On("Use", uint(1), securetoken.Hash("k7qxm2vd4p")).Return(true, nil).Once()
+	mockRecoveryRepo.On("Use", uint(1), securetoken.Hash("k7qxm2vd4p")).Return(false, nil).Once()
+
+	// codes are accepted without the dash and in any case, but only once
+	assert.NoError(t, twoFactorService.Verify(user, "K7QXM-2VD4P"))
+	assert.Equal(t, apperror.ErrInvalidOTP, twoFactorService.Verify(user, "k7qxm2vd4p"))
+	mockRecoveryRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_Verify_NotEnabled(t *testing.T) {
+	twoFactorService := newTestTwoFactorService(new(mocks.MockUserRepository), new(mocks.MockRecoveryCodeRepository), config.TwoFactorConfig{}, time.Now())
+
+	err := twoFactorService.Verify(&model.User{ID: 1, TOTPSecret: testTOTPSecret}, "123456")
+
+	assert.Equal(t, apperror.ErrTwoFactorNotSetUp, err)
+}
+
+func TestTwoFactorService_Disable_RequiredForAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{RequiredForAdmin: true}, time.Now())
+
+	now := time.Now()
+	user := &model.User{ID: 1, Role: model.RoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &now}
+	_ = user.HashPassword("password123")
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+
+	err := twoFactorService.Disable(1, dto.DisableTwoFactorRequest{Password: "password123", Code: "123456"})
+
+	assert.Equal(t, apperror.ErrTwoFactorRequired, err)
+	mockUserRepo.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_Disable_WrongPassword(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{}, time.Now())
+
+	now := time.Now()
+	user := &model.User{ID: 1, Role: model.RoleUser, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &now}
+	_ = user.HashPassword("password123")
+	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+
+	err := twoFactorService.Disable(1, dto.DisableTwoFactorRequest{Password: "wrong-password", Code: "123456"})
+
+	assert.Equal(t, apperror.ErrInvalidPassword, err)
+}
+
+func TestTwoFactorService_GetStatus(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRecoveryRepo := new(mocks.MockRecoveryCodeRepository)
+	twoFactorService := newTestTwoFactorService(mockUserRepo, mockRecoveryRepo, config.TwoFactorConfig{RequiredForAdmin: true}, time.Now())
+
+	now := time.Now()
+	mockUserRepo.On("FindByID", uint(1)).Return(&model.User{ID: 1, Role: model.RoleAdmin, TOTPEnabledAt: &now}, nil)
+	mockRecoveryRepo.On("CountUnused", uint(1)).Return(int64(7), nil)
+
+	status, err := twoFactorService.GetStatus(1)
+
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.True(t, status.Required)
+	assert.Equal(t, int64(7), status.RecoveryCodesRemaining)
+}
+
+func TestGenerateRecoveryCode_Format(t *testing.T) {
+	code, err := generateRecoveryCode()
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+}
+
+func TestTwoFactorService_IsRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+		role     model.UserRole
+		found    *model.Role
+		findErr  error
+		want     bool
+	}{
+		{"not enforced", false, model.RoleSuperAdmin, nil, nil, false},
+		{"admin", true, model.RoleAdmin, nil, nil, true},
+		{"super admin", true, model.RoleSuperAdmin, nil, nil, true},
+		{"user", true, model.RoleUser, nil, nil, false},
+		{"platform role", true, "support", &model.Role{Name: "support", Permissions: []model.RolePermission{{Permission: model.PermOrgsManage}}}, nil, true},
+		{"tenant role", true, "editor", &model.Role{Name: "editor", Permissions: []model.RolePermission{{Permission: model.PermGiftsWrite}}}, nil, false},
+		{"unknown role", true, "gone", nil, apperror.ErrNotFound, false},
+		{"lookup failure", true, "support", nil, assert.AnError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoleRepo := new(mocks.MockRoleRepository)
+			svc := NewTwoFactorService(nil, new(mocks.MockUserRepository), new(mocks.MockRecoveryCodeRepository), mockRoleRepo,
+				config.TwoFactorConfig{RequiredForAdmin: tt.required})
+			if tt.found != nil || tt.findErr != nil {
+				mockRoleRepo.On("FindByName", string(tt.role)).Return(tt.found, tt.findErr)
+			}
+
+			assert.Equal(t, tt.want, svc.IsRequired(&model.User{Role: tt.role}))
+		})
+	}
+}
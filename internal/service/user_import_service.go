@@ -0,0 +1,400 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/dto"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/pkg/securetoken"
+	"github.com/gift-redemption/internal/repository"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// MaxImportBytes caps the size of an uploaded import file
+	MaxImportBytes = 5 << 20
+	// MaxImportRows caps the number of users in one import
+	MaxImportRows = 10000
+
+	AuditUsersImport = "users.imported"
+
+	// inviteTTL is how long an invited user has to choose a password
+	inviteTTL = 7 * 24 * time.Hour
+	// maxImportPoints keeps initial balances well inside the INTEGER column
+	maxImportPoints = 1000000000
+	// maxImportFailures bounds the stored failure list; failed_rows still counts every one
+	maxImportFailures = 1000
+	// importProgressEvery is how many rows are processed between progress saves
+	importProgressEvery = 50
+	// maxConcurrentImports bounds how many imports run at once per instance
+	maxConcurrentImports = 2
+)
+
+var (
+	// errInviteNotSent marks a row whose account was created but whose invite failed
+	errInviteNotSent = errors.New("account created, but the invite could not be sent; ask the user to reset their password")
+	// errPointsNotCredited marks a row whose account was created without its points
+	errPointsNotCredited = errors.New("account created, but its points could not be credited; no invite was sent")
+)
+
+// UserImportService provisions users in bulk from CSV. Imported users get no
+// password; they receive an invite link to choose one instead.
+type UserImportService interface {
+	// Start checks the file layout, records the import and processes the rows
+	// in the background. Row-level problems are reported on the import, not
+	// returned.
+	Start(orgID, adminID uint, platformAdmin bool, filename string, r io.Reader, ip string) (*dto.UserImportResponse, error)
+	Get(orgID, id uint) (*dto.UserImportResponse, error)
+}
+
+type userImportService struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	resetRepo  repository.PasswordResetRepository
+	importRepo repository.UserImportRepository
+	pointRepo  repository.PointRepository
+	auditRepo  repository.AuditRepository
+	mailer     mailer.Mailer
+	cfg        *config.Config
+	slots      chan struct{}
+	// background runs an import; replaced in tests to run synchronously
+	background func(func())
+	now        func() time.Time
+}
+
+func NewUserImportService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	resetRepo repository.PasswordResetRepository,
+	importRepo repository.UserImportRepository,
+	pointRepo repository.PointRepository,
+	auditRepo repository.AuditRepository,
+	mailer mailer.Mailer,
+	cfg *config.Config,
+) UserImportService {
+	return &userImportService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		resetRepo:  resetRepo,
+		importRepo: importRepo,
+		pointRepo:  pointRepo,
+		auditRepo:  auditRepo,
+		mailer:     mailer,
+		cfg:        cfg,
+		slots:      make(chan struct{}, maxConcurrentImports),
+		background: func(f func()) { go f() },
+		now:        time.Now,
+	}
+}
+
+func (s *userImportService) Start(orgID, adminID uint, platformAdmin bool, filename string, r io.Reader, ip string) (*dto.UserImportResponse, error) {
+	rows, err := parseImportFile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.UserImport{
+		OrganisationID: orgID,
+		CreatedByID:    adminID,
+		Filename:       filename,
+		Status:         model.ImportPending,
+		TotalRows:      len(rows),
+		Failures:       model.ImportFailures{},
+	}
+	if err := s.importRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	res := dto.ToUserImportResponse(*job)
+	s.background(func() { s.run(job, rows, platformAdmin, ip) })
+	return &res, nil
+}
+
+func (s *userImportService) Get(orgID, id uint) (*dto.UserImportResponse, error) {
+	job, err := s.importRepo.FindByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	res := dto.ToUserImportResponse(*job)
+	return &res, nil
+}
+
+// run processes every row of the import, saving progress as it goes
+func (s *userImportService) run(job *model.UserImport, rows []dto.ImportRow, platformAdmin bool, ip string) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("user import %d: panic: %v", job.ID, p)
+			s.finish(job, model.ImportFailed)
+		}
+	}()
+
+	started := s.now()
+	job.Status = model.ImportRunning
+	job.StartedAt = &started
+	s.save(job)
+
+	for i, row := range rows {
+		err := s.importRow(job.OrganisationID, row, platformAdmin)
+		job.ProcessedRows++
+		if err != nil {
+			job.FailedRows++
+			if len(job.Failures) < maxImportFailures {
+				job.Failures = append(job.Failures, model.ImportFailure{
+					Row:   row.Line,
+					Email: row.Email,
+					Error: importErrorMessage(err),
+				})
+			}
+		} else {
+			job.SucceededRows++
+		}
+
+		if (i+1)%importProgressEvery == 0 {
+			s.save(job)
+		}
+	}
+
+	s.finish(job, model.ImportCompleted)
+
+	entry := &model.AuditLog{
+		ActorType:  model.ActorUser,
+		ActorID:    &job.CreatedByID,
+		Action:     AuditUsersImport,
+		TargetType: "organisation",
+		TargetID:   strconv.FormatUint(uint64(job.OrganisationID), 10),
+		IP:         ip,
+		Metadata: model.JSONMap{
+			"import_id": job.ID,
+			"rows":      job.TotalRows,
+			"succeeded": job.SucceededRows,
+			"failed":    job.FailedRows,
+		},
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+}
+
+func (s *userImportService) finish(job *model.UserImport, status string) {
+	finished := s.now()
+	job.Status = status
+	job.FinishedAt = &finished
+	s.save(job)
+}
+
+// save records progress; a failed save only delays what pollers see
+func (s *userImportService) save(job *model.UserImport) {
+	if err := s.importRepo.Update(job); err != nil {
+		log.Printf("user import %d: save progress: %v", job.ID, err)
+	}
+}
+
+// importRow creates one user and emails the invite. The row is checked with
+// the same rules as dto.CreateUserRequest, minus the password.
+func (s *userImportService) importRow(orgID uint, row dto.ImportRow, platformAdmin bool) error {
+	req := dto.CreateUserRequest{Name: row.Name, Email: row.Email, Role: row.Role}
+	if err := binding.Validator.Engine().(*validator.Validate).StructPartial(req, "Name", "Email", "Role"); err != nil {
+		return err
+	}
+
+	points := 0
+	if row.Points != "" {
+		n, err := strconv.Atoi(row.Points)
+		if err != nil || n < 0 || n > maxImportPoints {
+			return fmt.Errorf("%w: points must be a whole number between 0 and %d", apperror.ErrInvalidImport, maxImportPoints)
+		}
+		points = n
+	}
+
+	role := model.RoleUser
+	if req.Role != "" && req.Role != string(model.RoleUser) {
+		if err := checkRoleAssignable(s.roleRepo, req.Role, platformAdmin); err != nil {
+			return err
+		}
+		role = model.UserRole(req.Role)
+	}
+
+	// accounts provisioned by an admin are trusted; the empty password hash
+	// matches nothing until the invite is accepted
+	now := s.now()
+	user := &model.User{
+		OrganisationID:  orgID,
+		Name:            req.Name,
+		Email:           req.Email,
+		Role:            role,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+
+	// opening points are a lot like any other, so they expire too
+	if points > 0 {
+		lot := model.NewPointLot(orgID, user.ID, model.LedgerImport, points, now, s.cfg.Points.ValidMonths)
+		if err := s.pointRepo.Credit(lot); err != nil {
+			log.Printf("user import: credit user %d: %v", user.ID, err)
+			return errPointsNotCredited
+		}
+		user.PointBalance = points
+	}
+
+	if err := s.sendInvite(user); err != nil {
+		log.Printf("user import: invite user %d: %v", user.ID, err)
+		return errInviteNotSent
+	}
+	return nil
+}
+
+// sendInvite issues a long-lived password reset token that lets the user
+// choose their first password
+func (s *userImportService) sendInvite(user *model.User) error {
+	plain, err := securetoken.Generate()
+	if err != nil {
+		return err
+	}
+
+	token := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: securetoken.Hash(plain),
+		ExpiresAt: s.now().Add(inviteTTL),
+	}
+	if err := s.resetRepo.Create(token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.AppBaseURL, url.QueryEscape(plain))
+	body := fmt.Sprintf("Hi %s,\n\nAn account has been created for you. "+
+		"Open the link below within %d days to choose your password.\n\n%s\n",
+		user.Name, int(inviteTTL.Hours()/24), link)
+	if user.PointBalance > 0 {
+		body += fmt.Sprintf("\nYour account starts with %d points.\n", user.PointBalance)
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "You've been invited",
+		Body:    body,
+	})
+}
+
+// importErrorMessage explains a row failure without leaking internals
+func importErrorMessage(err error) string {
+	var verrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		msgs := make([]string, 0, len(verrs))
+		for _, fe := range verrs {
+			msgs = append(msgs, strings.ToLower(fe.Field())+" "+validationMessage(fe.Tag()))
+		}
+		return strings.Join(msgs, "; ")
+	case errors.Is(err, apperror.ErrDuplicateEntry):
+		return "email already registered"
+	case errors.Is(err, apperror.ErrInvalidRole):
+		return err.Error()
+	case errors.Is(err, apperror.ErrForbidden):
+		return "only super admins can assign platform roles"
+	case errors.Is(err, apperror.ErrInvalidImport), errors.Is(err, errInviteNotSent), errors.Is(err, errPointsNotCredited):
+		return strings.TrimPrefix(err.Error(), apperror.ErrInvalidImport.Error()+": ")
+	default:
+		return "internal error"
+	}
+}
+
+func validationMessage(tag string) string {
+	switch tag {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "max":
+		return "is too long"
+	default:
+		return "is invalid"
+	}
+}
+
+// parseImportFile reads a CSV with a header row naming the columns name,
+// email and optionally role and points, in any order
+func parseImportFile(r io.Reader) ([]dto.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", apperror.ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperror.ErrInvalidImport, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "name", "email", "role", "points":
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", apperror.ErrInvalidImport, name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate column %q", apperror.ErrInvalidImport, name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", apperror.ErrInvalidImport, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []dto.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", apperror.ErrInvalidImport, err)
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", apperror.ErrInvalidImport, MaxImportRows)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, dto.ImportRow{
+			Line:   line,
+			Name:   field(record, "name"),
+			Email:  field(record, "email"),
+			Role:   field(record, "role"),
+			Points: field(record, "points"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no rows", apperror.ErrInvalidImport)
+	}
+	return rows, nil
+}
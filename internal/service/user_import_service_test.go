@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gift-redemption/internal/config"
+	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/mailer"
+	"github.com/gift-redemption/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type importMocks struct {
+	users   *mocks.MockUserRepository
+	roles   *mocks.MockRoleRepository
+	resets  *mocks.MockPasswordResetRepository
+	imports *mocks.MockUserImportRepository
+	points  *mocks.MockPointRepository
+	audit   *mocks.MockAuditRepository
+	mail    *mocks.MockMailer
+}
+
+// newTestImportService runs imports synchronously so tests see the final state
+func newTestImportService() (*userImportService, importMocks) {
+	m := importMocks{
+		users:   new(mocks.MockUserRepository),
+		roles:   new(mocks.MockRoleRepository),
+		resets:  new(mocks.MockPasswordResetRepository),
+		imports: new(mocks.MockUserImportRepository),
+		points:  new(mocks.MockPointRepository),
+		audit:   new(mocks.MockAuditRepository),
+		mail:    new(mocks.MockMailer),
+	}
+	cfg := &config.Config{AppBaseURL: "https://gifts.example.com", Points: config.PointConfig{ValidMonths: 12}}
+	svc := NewUserImportService(m.users, m.roles, m.resets, m.imports, m.points, m.audit, m.mail, cfg).(*userImportService)
+	svc.background = func(f func()) { f() }
+	return svc, m
+}
+
+func TestUserImportService_Start_ProcessesRows(t *testing.T) {
+	svc, m := newTestImportService()
+
+	csvData := "Email,Name,Role,Points\n" +
+		"alice@example.com,Alice,,250\n" +
+		"taken@example.com,Taken,,\n" +
+		"not-an-email,Bad Email,,\n" +
+		"bob@example.com,Bob,ghost,\n" +
+		"carol@example.com,Carol,,-5\n" +
+		"dave@example.com,Dave,,\n"
+
+	var job *model.UserImport
+	m.imports.On("Create", mock.AnythingOfType("*model.UserImport")).Run(func(args mock.Arguments) {
+		job = args.Get(0).(*model.UserImport)
+		job.ID = 7
+	}).Return(nil)
+	m.imports.On("Update", mock.AnythingOfType("*model.UserImport")).Return(nil)
+
+	m.users.On("Create", mock.MatchedBy(func(u *model.User) bool { return u.Email == "taken@example.com" })).
+		Return(apperror.ErrDuplicateEntry)
+	m.users.On("Create", mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.User).ID = 42
+	}).Return(nil)
+	m.roles.On("FindByName", "ghost").Return(nil, apperror.ErrNotFound)
+	m.points.On("Credit", mock.AnythingOfType("*model.PointLot")).Return(nil)
+	m.resets.On("Create", mock.AnythingOfType("*model.PasswordResetToken")).Return(nil)
+	m.mail.On("Send", mock.MatchedBy(func(msg mailer.Message) bool { return msg.To == "dave@example.com" })).
+		Return(errors.New("smtp down"))
+	m.mail.On("Send", mock.AnythingOfType("mailer.Message")).Return(nil)
+	m.audit.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditUsersImport && e.Metadata["succeeded"] == 1 && e.Metadata["failed"] == 5
+	})).Return(nil)
+
+	res, err := svc.Start(1, 9, false, "users.csv", strings.NewReader(csvData), "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), res.ID)
+	assert.Equal(t, model.ImportPending, res.Status)
+	assert.Equal(t, 6, res.TotalRows)
+
+	assert.Equal(t, model.ImportCompleted, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, 6, job.ProcessedRows)
+	assert.Equal(t, 1, job.SucceededRows)
+	assert.Equal(t, 5, job.FailedRows)
+	assert.Equal(t, model.ImportFailures{
+		{Row: 3, Email: "taken@example.com", Error: "email already registered"},
+		{Row: 4, Email: "not-an-email", Error: "email must be a valid email address"},
+		{Row: 5, Email: "bob@example.com", Error: "role does not exist"},
+		{Row: 6, Email: "carol@example.com", Error: "points must be a whole number between 0 and 1000000000"},
+		{Row: 7, Email: "dave@example.com", Error: errInviteNotSent.Error()},
+	}, job.Failures)
+
+	// imported users are verified, carry their points and have no usable password
+	m.users.AssertCalled(t, "Create", mock.MatchedBy(func(u *model.User) bool {
+		return u.Email == "alice@example.com" && u.OrganisationID == 1 && u.Role == model.RoleUser &&
+			u.EmailVerifiedAt != nil && u.Password == "" && !u.CheckPassword("")
+	}))
+	m.points.AssertNumberOfCalls(t, "Credit", 1)
+	m.points.AssertCalled(t, "Credit", mock.MatchedBy(func(lot *model.PointLot) bool {
+		return lot.UserID == 42 && lot.OrganisationID == 1 && lot.Source == model.LedgerImport &&
+			lot.Points == 250 && lot.Remaining == 250 && lot.ExpiresAt.Equal(lot.EarnedAt.AddDate(1, 0, 0))
+	}))
+	m.resets.AssertCalled(t, "Create", mock.MatchedBy(func(tok *model.PasswordResetToken) bool {
+		return tok.UserID == 42 && tok.ExpiresAt.Sub(svc.now()) > 6*24*time.Hour
+	}))
+	m.mail.AssertCalled(t, "Send", mock.MatchedBy(func(msg mailer.Message) bool {
+		return msg.To == "alice@example.com" &&
+			strings.Contains(msg.Body, "https://gifts.example.com/reset-password?token=") &&
+			strings.Contains(msg.Body, "250 points")
+	}))
+	m.audit.AssertExpectations(t)
+}
+
+func TestUserImportService_Start_PlatformRoleRequiresSuperAdmin(t *testing.T) {
+	svc, m := newTestImportService()
+
+	var job *model.UserImport
+	m.imports.On("Create", mock.AnythingOfType("*model.UserImport")).Run(func(args mock.Arguments) {
+		job = args.Get(0).(*model.UserImport)
+	}).Return(nil)
+	m.imports.On("Update", mock.AnythingOfType("*model.UserImport")).Return(nil)
+	m.roles.On("FindByName", string(model.RoleSuperAdmin)).Return(&model.Role{Name: string(model.RoleSuperAdmin)}, nil)
+	m.audit.On("Create", mock.AnythingOfType("*model.AuditLog")).Return(nil)
+
+	_, err := svc.Start(1, 9, false, "users.csv", strings.NewReader("name,email,role\nEve,eve@example.com,super_admin\n"), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, job.FailedRows)
+	assert.Equal(t, "only super admins can assign platform roles", job.Failures[0].Error)
+	m.users.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUserImportService_Start_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{"empty", "", "file is empty"},
+		{"missing column", "name,role\nAlice,user\n", `missing column "email"`},
+		{"unknown column", "name,email,password\nAlice,a@example.com,secret\n", `unknown column "password"`},
+		{"duplicate column", "name,email,email\n", `duplicate column "email"`},
+		{"no rows", "name,email\n", "file has no rows"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestImportService()
+
+			_, err := svc.Start(1, 9, false, "users.csv", strings.NewReader(tt.csv), "")
+
+			assert.ErrorIs(t, err, apperror.ErrInvalidImport)
+			assert.Contains(t, err.Error(), tt.want)
+			m.imports.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestUserImportService_Start_TooManyRows(t *testing.T) {
+	svc, _ := newTestImportService()
+
+	var b strings.Builder
+	b.WriteString("name,email\n")
+	for i := 0; i <= MaxImportRows; i++ {
+		b.WriteString("User,user@example.com\n")
+	}
+
+	_, err := svc.Start(1, 9, false, "users.csv", strings.NewReader(b.String()), "")
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidImport)
+}
+
+func TestUserImportService_Get_NotFound(t *testing.T) {
+	svc, m := newTestImportService()
+	m.imports.On("FindByID", uint(2), uint(7)).Return(nil, apperror.ErrNotFound)
+
+	_, err := svc.Get(2, 7)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
@@ -1,59 +1,162 @@
 package service
 
 import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
+	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/pkg/response"
 	"github.com/gift-redemption/internal/repository"
 )
 
+// UserService manages the users of one organisation. platformAdmin reports
+// whether the caller may grant or change roles with platform permissions.
 type UserService interface {
-	GetAll() ([]dto.UserResponse, error)
-	GetByID(id uint) (*dto.UserResponse, error)
-	Create(req dto.CreateUserRequest) (*dto.UserResponse, error)
-	Update(id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error)
-	Delete(id uint) error
+	GetAll(orgID uint, query dto.UserQuery) ([]dto.UserResponse, *response.Pagination, error)
+	// Export writes every user matching query as CSV, ignoring pagination
+	Export(orgID uint, actor model.Actor, query dto.UserQuery, w io.Writer, ip string) error
+	GetByID(orgID, id uint) (*dto.UserResponse, error)
+	Create(orgID uint, req dto.CreateUserRequest, platformAdmin bool) (*dto.UserResponse, error)
+	Update(orgID, id uint, req dto.UpdateUserRequest, platformAdmin bool) (*dto.UserResponse, error)
+	Delete(orgID, id uint, platformAdmin bool) error
+}
+
+const (
+	AuditUsersExport = "users.exported"
+
+	userExportBatchSize = 500
+)
+
+var userExportHeader = []string{
+	"id", "name", "email", "role", "email_verified", "two_factor_enabled", "locale",
+	"point_balance", "redemption_count", "points_redeemed", "created_at", "deleted_at",
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	redemptionRepo repository.RedemptionRepository
+	auditRepo      repository.AuditRepository
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo}
+func NewUserService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	redemptionRepo repository.RedemptionRepository,
+	auditRepo repository.AuditRepository,
+) UserService {
+	return &userService{userRepo, roleRepo, redemptionRepo, auditRepo}
 }
 
-func (s *userService) GetAll() ([]dto.UserResponse, error) {
-	users, err := s.userRepo.FindAll()
+func (s *userService) GetAll(orgID uint, query dto.UserQuery) ([]dto.UserResponse, *response.Pagination, error) {
+	query.Normalize()
+
+	users, total, err := s.userRepo.FindAll(userFilter(orgID, query))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	result := make([]dto.UserResponse, len(users))
-	for i, u := range users {
-		result[i] = dto.ToUserResponse(u)
+	result, err := toUserResponses(s.redemptionRepo, users)
+	if err != nil {
+		return nil, nil, err
 	}
-	return result, nil
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+	pagination := &response.Pagination{
+		CurrentPage: query.Page,
+		PerPage:     query.Limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+
+	return result, pagination, nil
 }
 
-func (s *userService) GetByID(id uint) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
+func (s *userService) Export(orgID uint, actor model.Actor, query dto.UserQuery, w io.Writer, ip string) error {
+	query.Normalize()
+
+	out := csv.NewWriter(w)
+	if err := out.Write(userExportHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := s.userRepo.FindInBatches(userFilter(orgID, query), userExportBatchSize, func(users []model.User) error {
+		batch, err := toUserResponses(s.redemptionRepo, users)
+		if err != nil {
+			return err
+		}
+		for _, u := range batch {
+			if err := out.Write(userExportRow(u)); err != nil {
+				return err
+			}
+		}
+		rows += len(batch)
+		out.Flush()
+		return out.Error()
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+
+	// exports carry personal data in bulk, so each one is accounted for
+	entry := &model.AuditLog{
+		ActorType:  actor.Type,
+		ActorID:    actor.ActorIDPtr(),
+		Action:     AuditUsersExport,
+		TargetType: "organisation",
+		TargetID:   strconv.FormatUint(uint64(orgID), 10),
+		IP:         ip,
+		Metadata: model.JSONMap{
+			"rows":    rows,
+			"search":  query.Search,
+			"role":    query.Role,
+			"deleted": query.Deleted,
+		},
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("audit %s: %v", entry.Action, err)
+	}
+	return nil
+}
+
+func (s *userService) GetByID(orgID, id uint) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindInOrganisation(orgID, id)
 	if err != nil {
 		return nil, err
 	}
-	res := dto.ToUserResponse(*user)
-	return &res, nil
+	return toUserResponse(s.redemptionRepo, user)
 }
 
-func (s *userService) Create(req dto.CreateUserRequest) (*dto.UserResponse, error) {
+func (s *userService) Create(orgID uint, req dto.CreateUserRequest, platformAdmin bool) (*dto.UserResponse, error) {
 	role := model.RoleUser
-	if req.Role == string(model.RoleAdmin) {
-		role = model.RoleAdmin
+	if req.Role != "" && req.Role != string(role) {
+		if err := s.checkAssignable(req.Role, platformAdmin); err != nil {
+			return nil, err
+		}
+		role = model.UserRole(req.Role)
 	}
 
+	// accounts provisioned by an admin are trusted
+	now := time.Now()
 	user := &model.User{
-		Name:  req.Name,
-		Email: req.Email,
-		Role:  role,
+		OrganisationID:  orgID,
+		Name:            req.Name,
+		Email:           req.Email,
+		Role:            role,
+		EmailVerifiedAt: &now,
 	}
 
 	if err := user.HashPassword(req.Password); err != nil {
@@ -68,24 +171,167 @@ func (s *userService) Create(req dto.CreateUserRequest) (*dto.UserResponse, erro
 	return &res, nil
 }
 
-func (s *userService) Update(id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
+func (s *userService) Update(orgID, id uint, req dto.UpdateUserRequest, platformAdmin bool) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindInOrganisation(orgID, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// tokens carry the role, so a role change invalidates them
+	revokeTokens := false
+	if role := model.UserRole(req.Role); role != user.Role {
+		if err := s.checkManageable(user, platformAdmin); err != nil {
+			return nil, err
+		}
+		if err := s.checkAssignable(req.Role, platformAdmin); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		user.Role = role
+		user.TokensValidAfter = &now
+		revokeTokens = true
+	}
 	user.Name = req.Name
 	user.Email = req.Email
-	user.Role = model.UserRole(req.Role)
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.UpdateAccount(user, revokeTokens); err != nil {
 		return nil, err
 	}
+	return toUserResponse(s.redemptionRepo, user)
+}
+
+func (s *userService) Delete(orgID, id uint, platformAdmin bool) error {
+	user, err := s.userRepo.FindInOrganisation(orgID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkManageable(user, platformAdmin); err != nil {
+		return err
+	}
+	return s.userRepo.Delete(orgID, id)
+}
 
-	res := dto.ToUserResponse(*user)
-	return &res, nil
+func (s *userService) checkAssignable(name string, platformAdmin bool) error {
+	return checkRoleAssignable(s.roleRepo, name, platformAdmin)
+}
+
+// checkRoleAssignable verifies the role exists and that only platform admins
+// hand out platform permissions
+func checkRoleAssignable(roleRepo repository.RoleRepository, name string, platformAdmin bool) error {
+	role, err := roleRepo.FindByName(name)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return apperror.ErrInvalidRole
+	}
+	if err != nil {
+		return err
+	}
+	if role.IsPlatform() && !platformAdmin {
+		return apperror.ErrForbidden
+	}
+	return nil
 }
 
-func (s *userService) Delete(id uint) error {
-	return s.userRepo.Delete(id)
+func (s *userService) checkManageable(user *model.User, platformAdmin bool) error {
+	return checkUserManageable(s.roleRepo, user, platformAdmin)
+}
+
+// checkUserManageable stops tenant admins from changing, deleting or erasing
+// platform admins
+func checkUserManageable(roleRepo repository.RoleRepository, user *model.User, platformAdmin bool) error {
+	if platformAdmin {
+		return nil
+	}
+	if user.Role == model.RoleSuperAdmin {
+		return apperror.ErrForbidden
+	}
+	if _, ok := model.BuiltInPermissions(user.Role); ok || user.Role == model.RoleUser {
+		return nil
+	}
+
+	role, err := roleRepo.FindByName(string(user.Role))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if role.IsPlatform() {
+		return apperror.ErrForbidden
+	}
+	return nil
+}
+
+// toUserResponses converts users and fills in their redemption counts with a
+// single query
+func toUserResponses(redemptionRepo repository.RedemptionRepository, users []model.User) ([]dto.UserResponse, error) {
+	ids := make([]uint, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	stats, err := redemptionRepo.StatsByUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.UserResponse, len(users))
+	for i, u := range users {
+		result[i] = dto.ToUserResponse(u)
+		result[i].RedemptionCount = stats[u.ID].Count
+		result[i].PointsRedeemed = stats[u.ID].PointsRedeemed
+	}
+	return result, nil
+}
+
+func toUserResponse(redemptionRepo repository.RedemptionRepository, user *model.User) (*dto.UserResponse, error) {
+	result, err := toUserResponses(redemptionRepo, []model.User{*user})
+	if err != nil {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+func userFilter(orgID uint, query dto.UserQuery) repository.UserFilter {
+	filter := repository.UserFilter{
+		OrganisationID: orgID,
+		Page:           query.Page,
+		Limit:          query.Limit,
+		SortBy:         query.SortBy,
+		SortDir:        query.SortDir,
+		Search:         query.Search,
+		Role:           query.Role,
+		Verified:       query.Verified,
+		CreatedFrom:    query.CreatedFrom,
+		Deleted:        query.Deleted,
+	}
+	// created_to names a whole day
+	if !query.CreatedTo.IsZero() {
+		filter.CreatedTo = query.CreatedTo.AddDate(0, 0, 1)
+	}
+	return filter
+}
+
+func userExportRow(u dto.UserResponse) []string {
+	return []string{
+		strconv.FormatUint(uint64(u.ID), 10),
+		csvSafe(u.Name),
+		csvSafe(u.Email),
+		u.Role,
+		strconv.FormatBool(u.EmailVerified),
+		strconv.FormatBool(u.TwoFactorEnabled),
+		u.Locale,
+		strconv.Itoa(u.PointBalance),
+		strconv.FormatInt(u.RedemptionCount, 10),
+		strconv.FormatInt(u.PointsRedeemed, 10),
+		u.CreatedAt,
+		u.DeletedAt,
+	}
+}
+
+// csvSafe stops spreadsheet applications from evaluating user-supplied text
+// as a formula
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
 }
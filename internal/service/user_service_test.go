@@ -1,45 +1,131 @@
 package service
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gift-redemption/internal/dto"
 	"github.com/gift-redemption/internal/model"
 	"github.com/gift-redemption/internal/pkg/apperror"
+	"github.com/gift-redemption/internal/repository"
 	"github.com/gift-redemption/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// noRedemptions returns a redemption repository reporting no redemptions for anyone
+func noRedemptions() *mocks.MockRedemptionRepository {
+	repo := new(mocks.MockRedemptionRepository)
+	repo.On("StatsByUsers", mock.Anything).Return(map[uint]repository.RedemptionStats{}, nil)
+	return repo
+}
+
 func TestUserService_GetByID_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	mockRedemptionRepo := new(mocks.MockRedemptionRepository)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), mockRedemptionRepo, new(mocks.MockAuditRepository))
 
 	user := &model.User{
-		ID:    1,
-		Name:  "Test User",
-		Email: "test@example.com",
-		Role:  model.RoleUser,
+		ID:           1,
+		Name:         "Test User",
+		Email:        "test@example.com",
+		Role:         model.RoleUser,
+		PointBalance: 5000,
 	}
 
-	mockUserRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(1)).Return(user, nil)
+	mockRedemptionRepo.On("StatsByUsers", []uint{1}).Return(map[uint]repository.RedemptionStats{
+		1: {UserID: 1, Count: 3, PointsRedeemed: 1200},
+	}, nil)
 
-	result, err := userService.GetByID(1)
+	result, err := userService.GetByID(1, 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, user.Name, result.Name)
 	assert.Equal(t, user.Email, result.Email)
+	assert.Equal(t, 5000, result.PointBalance)
+	assert.Equal(t, int64(3), result.RedemptionCount)
+	assert.Equal(t, int64(1200), result.PointsRedeemed)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_GetAll(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
+
+	verified := true
+	createdTo := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	filter := repository.UserFilter{
+		OrganisationID: 2,
+		Page:           1,
+		Limit:          10,
+		SortBy:         "name",
+		SortDir:        "asc",
+		Search:         "doe",
+		Role:           "user",
+		Verified:       &verified,
+		CreatedTo:      createdTo.AddDate(0, 0, 1),
+		Deleted:        repository.DeletedExclude,
+	}
+	users := []model.User{{ID: 1, Name: "Jane Doe"}, {ID: 2, Name: "John Doe"}}
+	mockUserRepo.On("FindAll", filter).Return(users, int64(12), nil)
+
+	result, pagination, err := userService.GetAll(2, dto.UserQuery{
+		SortBy:    "name",
+		SortDir:   "asc",
+		Search:    "  doe ",
+		Role:      "user",
+		Verified:  &verified,
+		CreatedTo: createdTo,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, int64(12), pagination.Total)
+	assert.Equal(t, 2, pagination.TotalPages)
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUserService_Export(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), mockAuditRepo)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []model.User{
+		{ID: 1, Name: "Jane Doe", Email: "jane@example.com", Role: model.RoleUser, Locale: "en", PointBalance: 50, CreatedAt: created},
+		{ID: 2, Name: "=HYPERLINK(\"http://evil\")", Email: "x@example.com", Role: model.RoleUser, Locale: "en", CreatedAt: created},
+	}
+	mockUserRepo.On("FindInBatches", mock.MatchedBy(func(f repository.UserFilter) bool {
+		return f.OrganisationID == 2 && f.Deleted == repository.DeletedInclude
+	}), 500).Return(users, nil)
+	mockAuditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == AuditUsersExport && e.Metadata["rows"] == 2 && *e.ActorID == 9
+	})).Return(nil)
+
+	var out bytes.Buffer
+	err := userService.Export(2, model.UserActor(9), dto.UserQuery{Deleted: "include"}, &out, "10.0.0.1")
+
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id,name,email,role"))
+	assert.Equal(t, "1,Jane Doe,jane@example.com,user,false,false,en,50,0,0,2026-01-02T03:04:05Z,", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], `2,"'=HYPERLINK(""http://evil"")"`))
+	mockAuditRepo.AssertExpectations(t)
+}
+
 func TestUserService_GetByID_NotFound(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
 
-	mockUserRepo.On("FindByID", uint(999)).Return(nil, apperror.ErrNotFound)
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
 
-	result, err := userService.GetByID(999)
+	result, err := userService.GetByID(1, 999)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
@@ -49,7 +135,7 @@ func TestUserService_GetByID_NotFound(t *testing.T) {
 
 func TestUserService_Create_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
 
 	req := dto.CreateUserRequest{
 		Name:     "New User",
@@ -59,10 +145,10 @@ func TestUserService_Create_Success(t *testing.T) {
 	}
 
 	mockUserRepo.On("Create", mock.MatchedBy(func(u *model.User) bool {
-		return u.Name == req.Name && u.Email == req.Email && u.Role == model.RoleUser
+		return u.Name == req.Name && u.Email == req.Email && u.Role == model.RoleUser && u.OrganisationID == 3
 	})).Return(nil)
 
-	result, err := userService.Create(req)
+	result, err := userService.Create(3, req, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -73,7 +159,7 @@ func TestUserService_Create_Success(t *testing.T) {
 
 func TestUserService_Create_DuplicateEmail(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
 
 	req := dto.CreateUserRequest{
 		Name:     "Duplicate User",
@@ -86,7 +172,7 @@ func TestUserService_Create_DuplicateEmail(t *testing.T) {
 		return u.Email == req.Email
 	})).Return(apperror.ErrDuplicateEntry)
 
-	result, err := userService.Create(req)
+	result, err := userService.Create(1, req, false)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrDuplicateEntry, err)
@@ -96,7 +182,8 @@ func TestUserService_Create_DuplicateEmail(t *testing.T) {
 
 func TestUserService_Update_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	userService := NewUserService(mockUserRepo, mockRoleRepo, noRedemptions(), new(mocks.MockAuditRepository))
 
 	existingUser := &model.User{
 		ID:    1,
@@ -111,26 +198,93 @@ func TestUserService_Update_Success(t *testing.T) {
 		Role:  "admin",
 	}
 
-	mockUserRepo.On("FindByID", uint(1)).Return(existingUser, nil)
-	mockUserRepo.On("Update", existingUser).Return(nil)
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(1)).Return(existingUser, nil)
+	mockRoleRepo.On("FindByName", "admin").Return(&model.Role{ID: 1, Name: "admin"}, nil)
+	mockUserRepo.On("UpdateAccount", existingUser, true).Return(nil)
 
-	result, err := userService.Update(1, req)
+	result, err := userService.Update(1, 1, req, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, req.Name, result.Name)
 	assert.Equal(t, req.Email, result.Email)
 	assert.Equal(t, "admin", result.Role)
+	assert.NotNil(t, existingUser.TokensValidAfter, "role change revokes issued tokens")
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUserService_Update_SameRoleKeepsTokens(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
+
+	existingUser := &model.User{ID: 1, Name: "Old Name", Email: "old@example.com", Role: model.RoleUser}
+
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(1)).Return(existingUser, nil)
+	// a stale tokens_valid_after is not written back over a concurrent revoke
+	mockUserRepo.On("UpdateAccount", existingUser, false).Return(nil)
+
+	_, err := userService.Update(1, 1, dto.UpdateUserRequest{Name: "New Name", Email: "old@example.com", Role: "user"}, false)
+
+	assert.NoError(t, err)
+	assert.Nil(t, existingUser.TokensValidAfter)
+}
+
+func TestUserService_Update_UnknownRole(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	userService := NewUserService(mockUserRepo, mockRoleRepo, noRedemptions(), new(mocks.MockAuditRepository))
+
+	existingUser := &model.User{ID: 1, Name: "Old Name", Email: "old@example.com", Role: model.RoleUser}
+
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(1)).Return(existingUser, nil)
+	mockRoleRepo.On("FindByName", "superhero").Return(nil, apperror.ErrNotFound)
+
+	_, err := userService.Update(1, 1, dto.UpdateUserRequest{Name: "Old Name", Email: "old@example.com", Role: "superhero"}, false)
+
+	assert.ErrorIs(t, err, apperror.ErrInvalidRole)
+	mockUserRepo.AssertNotCalled(t, "UpdateAccount", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Create_PlatformRoleRequiresSuperAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	userService := NewUserService(mockUserRepo, mockRoleRepo, noRedemptions(), new(mocks.MockAuditRepository))
+
+	mockRoleRepo.On("FindByName", "super_admin").Return(&model.Role{Name: "super_admin", IsSystem: true}, nil)
+
+	req := dto.CreateUserRequest{Name: "Sneaky", Email: "sneaky@example.com", Password: "password123", Role: "super_admin"}
+
+	result, err := userService.Create(1, req, false)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	mockUserRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	result, err = userService.Create(1, req, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestUserService_Delete_SuperAdminByTenantAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
+
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(2)).Return(&model.User{ID: 2, Role: model.RoleSuperAdmin}, nil)
+
+	err := userService.Delete(1, 2, false)
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestUserService_Delete_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
 
-	mockUserRepo.On("Delete", uint(1)).Return(nil)
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(1)).Return(&model.User{ID: 1, Role: model.RoleUser}, nil)
+	mockUserRepo.On("Delete", uint(1), uint(1)).Return(nil)
 
-	err := userService.Delete(1)
+	err := userService.Delete(1, 1, false)
 
 	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
@@ -138,11 +292,11 @@ func TestUserService_Delete_Success(t *testing.T) {
 
 func TestUserService_Delete_NotFound(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
-	userService := NewUserService(mockUserRepo)
+	userService := NewUserService(mockUserRepo, new(mocks.MockRoleRepository), noRedemptions(), new(mocks.MockAuditRepository))
 
-	mockUserRepo.On("Delete", uint(999)).Return(apperror.ErrNotFound)
+	mockUserRepo.On("FindInOrganisation", uint(1), uint(999)).Return(nil, apperror.ErrNotFound)
 
-	err := userService.Delete(999)
+	err := userService.Delete(1, 999, false)
 
 	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrNotFound, err)
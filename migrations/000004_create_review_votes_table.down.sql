@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS review_votes;
+
+ALTER TABLE ratings
+    DROP COLUMN IF EXISTS helpful_count,
+    DROP COLUMN IF EXISTS not_helpful_count,
+    DROP COLUMN IF EXISTS hidden_at;
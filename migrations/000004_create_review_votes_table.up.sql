@@ -0,0 +1,18 @@
+ALTER TABLE ratings
+    ADD COLUMN helpful_count     INT         NOT NULL DEFAULT 0,
+    ADD COLUMN not_helpful_count INT         NOT NULL DEFAULT 0,
+    ADD COLUMN hidden_at         TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    id          SERIAL PRIMARY KEY,
+    rating_id   INT         NOT NULL REFERENCES ratings(id) ON DELETE CASCADE,
+    user_id     INT         NOT NULL REFERENCES users(id),
+    is_helpful  BOOLEAN     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- one vote per user per review
+    CONSTRAINT uq_review_vote_user UNIQUE (rating_id, user_id)
+);
+
+CREATE INDEX idx_review_votes_user_id ON review_votes(user_id);
+CREATE INDEX idx_ratings_helpful_count ON ratings(gift_id, helpful_count) WHERE hidden_at IS NULL;
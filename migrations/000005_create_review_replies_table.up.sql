@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS review_replies (
+    id         SERIAL PRIMARY KEY,
+    rating_id  INT         NOT NULL REFERENCES ratings(id) ON DELETE CASCADE,
+    admin_id   INT         NOT NULL REFERENCES users(id),
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- one reply per review
+    CONSTRAINT uq_review_reply_rating UNIQUE (rating_id)
+);
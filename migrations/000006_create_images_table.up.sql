@@ -0,0 +1,19 @@
+-- images is shared by gift galleries (owner_type = 'gifts') and review photos (owner_type = 'ratings')
+CREATE TABLE IF NOT EXISTS images (
+    id             SERIAL PRIMARY KEY,
+    owner_type     VARCHAR(20)  NOT NULL,
+    owner_id       INT          NOT NULL,
+    storage_key    VARCHAR(255) NOT NULL,
+    thumbnail_key  VARCHAR(255) NOT NULL,
+    url            VARCHAR(500) NOT NULL,
+    thumbnail_url  VARCHAR(500) NOT NULL,
+    content_type   VARCHAR(50)  NOT NULL,
+    size_bytes     BIGINT       NOT NULL,
+    width          INT          NOT NULL,
+    height         INT          NOT NULL,
+    position       INT          NOT NULL DEFAULT 0,
+    uploaded_by    INT          NOT NULL REFERENCES users(id),
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_images_owner ON images(owner_type, owner_id, position);
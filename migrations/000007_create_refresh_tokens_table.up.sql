@@ -0,0 +1,14 @@
+-- refresh tokens rotate on every use; all tokens issued from one login share a family_id (the session)
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id             SERIAL PRIMARY KEY,
+    user_id        INT         NOT NULL REFERENCES users(id),
+    family_id      VARCHAR(64) NOT NULL,
+    token_hash     VARCHAR(64) NOT NULL UNIQUE,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at     TIMESTAMPTZ,
+    replaced_by_id INT         REFERENCES refresh_tokens(id),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id) WHERE revoked_at IS NULL;
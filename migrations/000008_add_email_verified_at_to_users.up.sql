@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- accounts created before self-registration were provisioned by admins
+UPDATE users SET email_verified_at = created_at;
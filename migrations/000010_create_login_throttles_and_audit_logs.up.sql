@@ -0,0 +1,30 @@
+-- failed login counters shared by all replicas; scope is 'account' (key = email) or 'ip' (key = client IP)
+CREATE TABLE IF NOT EXISTS login_throttles (
+    id             SERIAL PRIMARY KEY,
+    scope          VARCHAR(10)  NOT NULL,
+    key            VARCHAR(255) NOT NULL,
+    failed_count   INT          NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    locked_until   TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_login_throttle_scope_key UNIQUE (scope, key)
+);
+
+CREATE INDEX idx_login_throttles_locked_until ON login_throttles(locked_until) WHERE locked_until IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_type  VARCHAR(20)  NOT NULL,
+    actor_id    INT,
+    action      VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50),
+    target_id   VARCHAR(255),
+    ip          VARCHAR(64),
+    metadata    JSONB        NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_type, actor_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action);
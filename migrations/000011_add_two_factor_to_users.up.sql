@@ -0,0 +1,14 @@
+-- totp_secret is set when enrolment starts; 2FA is only enforced once totp_enabled_at is set.
+-- totp_last_step stores the last accepted time step so a code cannot be replayed.
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         SERIAL PRIMARY KEY,
+    user_id    INT         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_recovery_codes_user_hash UNIQUE (user_id, code_hash)
+);
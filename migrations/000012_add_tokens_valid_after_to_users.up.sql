@@ -0,0 +1,2 @@
+-- access tokens issued before this moment are rejected (set on password and role changes)
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMPTZ;
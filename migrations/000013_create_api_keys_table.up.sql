@@ -0,0 +1,15 @@
+-- keys are presented as gr_<prefix>_<secret>; the prefix is the lookup key and only a hash of the secret is stored
+CREATE TABLE IF NOT EXISTS api_keys (
+    id            SERIAL PRIMARY KEY,
+    name          VARCHAR(100) NOT NULL,
+    prefix        VARCHAR(16)  NOT NULL UNIQUE,
+    secret_hash   VARCHAR(64)  NOT NULL,
+    scopes        JSONB        NOT NULL DEFAULT '[]',
+    created_by_id INT          NOT NULL REFERENCES users(id),
+    expires_at    TIMESTAMPTZ,
+    last_used_at  TIMESTAMPTZ,
+    last_used_ip  VARCHAR(64),
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'user');
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(10);
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,47 @@
+-- permissions are defined in code; roles are data and map to a set of permissions
+CREATE TABLE IF NOT EXISTS roles (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(50)  NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    is_system   BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id    INT         NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+-- admin is granted every permission in code, so it needs no rows here
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Full access', TRUE),
+    ('user', 'Customer: browse, redeem and review gifts', TRUE),
+    ('catalog_manager', 'Maintains the gift catalogue', FALSE),
+    ('support_agent', 'Looks up customers and moderates reviews', FALSE),
+    ('auditor', 'Read-only access to users and the catalogue', FALSE);
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.permission
+FROM roles r
+JOIN (VALUES
+    ('user', 'gifts:read'),
+    ('user', 'gifts:redeem'),
+    ('user', 'reviews:read'),
+    ('user', 'reviews:write'),
+    ('catalog_manager', 'gifts:read'),
+    ('catalog_manager', 'gifts:write'),
+    ('catalog_manager', 'reviews:read'),
+    ('support_agent', 'gifts:read'),
+    ('support_agent', 'reviews:read'),
+    ('support_agent', 'reviews:moderate'),
+    ('support_agent', 'users:read'),
+    ('auditor', 'gifts:read'),
+    ('auditor', 'reviews:read'),
+    ('auditor', 'users:read')
+) AS p(role_name, permission) ON p.role_name = r.name;
+
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50);
+ALTER TABLE users
+    ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name);
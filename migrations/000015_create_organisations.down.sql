@@ -0,0 +1,10 @@
+UPDATE users SET role = 'admin' WHERE role = 'super_admin';
+DELETE FROM roles WHERE name = 'super_admin';
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS organisation_id;
+ALTER TABLE ratings DROP COLUMN IF EXISTS organisation_id;
+ALTER TABLE redemptions DROP COLUMN IF EXISTS organisation_id;
+ALTER TABLE gifts DROP COLUMN IF EXISTS organisation_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organisation_id;
+
+DROP TABLE IF EXISTS organisations;
@@ -0,0 +1,39 @@
+-- every tenant-owned row carries organisation_id; existing data moves to the default organisation
+CREATE TABLE IF NOT EXISTS organisations (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    slug       VARCHAR(50)  NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organisations (name, slug) VALUES ('Default', 'default');
+
+ALTER TABLE users ADD COLUMN organisation_id INT REFERENCES organisations(id);
+ALTER TABLE gifts ADD COLUMN organisation_id INT REFERENCES organisations(id);
+ALTER TABLE redemptions ADD COLUMN organisation_id INT REFERENCES organisations(id);
+ALTER TABLE ratings ADD COLUMN organisation_id INT REFERENCES organisations(id);
+
+UPDATE users SET organisation_id = (SELECT id FROM organisations WHERE slug = 'default');
+UPDATE gifts SET organisation_id = (SELECT id FROM organisations WHERE slug = 'default');
+UPDATE redemptions SET organisation_id = (SELECT id FROM organisations WHERE slug = 'default');
+UPDATE ratings SET organisation_id = (SELECT id FROM organisations WHERE slug = 'default');
+
+ALTER TABLE users ALTER COLUMN organisation_id SET NOT NULL;
+ALTER TABLE gifts ALTER COLUMN organisation_id SET NOT NULL;
+ALTER TABLE redemptions ALTER COLUMN organisation_id SET NOT NULL;
+ALTER TABLE ratings ALTER COLUMN organisation_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_organisation_id ON users(organisation_id);
+CREATE INDEX IF NOT EXISTS idx_gifts_organisation_id ON gifts(organisation_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_organisation_id ON redemptions(organisation_id);
+CREATE INDEX IF NOT EXISTS idx_ratings_organisation_id ON ratings(organisation_id);
+
+-- NULL means a platform key that names its tenant in the X-Organisation header
+ALTER TABLE api_keys ADD COLUMN organisation_id INT REFERENCES organisations(id);
+
+INSERT INTO roles (name, description, is_system)
+VALUES ('super_admin', 'Manages organisations, roles and platform security across all tenants', TRUE);
+
+-- admins used to run the whole deployment; keep their access to platform settings
+UPDATE users SET role = 'super_admin' WHERE role = 'admin';
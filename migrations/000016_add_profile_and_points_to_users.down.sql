@@ -0,0 +1,5 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_point_balance;
+ALTER TABLE users DROP COLUMN IF EXISTS point_balance;
+ALTER TABLE users DROP COLUMN IF EXISTS notification_preferences;
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_url;
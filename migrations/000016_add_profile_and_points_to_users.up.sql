@@ -0,0 +1,9 @@
+-- self-service profile
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'en';
+-- event type -> enabled; missing events are enabled
+ALTER TABLE users ADD COLUMN notification_preferences JSONB NOT NULL DEFAULT '{}';
+
+-- spendable points, debited by redemptions
+ALTER TABLE users ADD COLUMN point_balance INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD CONSTRAINT chk_users_point_balance CHECK (point_balance >= 0);
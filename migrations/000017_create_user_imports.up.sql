@@ -0,0 +1,21 @@
+-- bulk CSV user provisioning; rows are processed in the background and
+-- progress is polled from this table
+CREATE TABLE IF NOT EXISTS user_imports (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    created_by_id   INT          NOT NULL REFERENCES users(id),
+    filename        VARCHAR(255) NOT NULL DEFAULT '',
+    status          VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    total_rows      INT          NOT NULL DEFAULT 0,
+    processed_rows  INT          NOT NULL DEFAULT 0,
+    succeeded_rows  INT          NOT NULL DEFAULT 0,
+    failed_rows     INT          NOT NULL DEFAULT 0,
+    -- [{row, email, error}]
+    failures        JSONB        NOT NULL DEFAULT '[]',
+    started_at      TIMESTAMPTZ,
+    finished_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_imports_organisation_id ON user_imports(organisation_id);
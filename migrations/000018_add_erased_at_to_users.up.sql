@@ -0,0 +1,2 @@
+-- set when a user's personal data is anonymised under a right-to-erasure request
+ALTER TABLE users ADD COLUMN erased_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tier_changes;
+ALTER TABLE gifts DROP COLUMN IF EXISTS min_tier_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tier_id;
+DROP TABLE IF EXISTS tiers;
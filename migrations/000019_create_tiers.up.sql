@@ -0,0 +1,33 @@
+-- loyalty tiers; users reach the highest tier whose min_points their
+-- qualifying points over the rolling window cover
+CREATE TABLE IF NOT EXISTS tiers (
+    id               SERIAL PRIMARY KEY,
+    organisation_id  INT          NOT NULL REFERENCES organisations(id),
+    name             VARCHAR(50)  NOT NULL,
+    min_points       INT          NOT NULL DEFAULT 0 CHECK (min_points >= 0),
+    discount_percent INT          NOT NULL DEFAULT 0 CHECK (discount_percent BETWEEN 0 AND 100),
+    benefits         VARCHAR(500) NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (organisation_id, name),
+    UNIQUE (organisation_id, min_points)
+);
+
+-- a deleted tier drops its users to no tier until the next evaluation
+ALTER TABLE users ADD COLUMN tier_id INT REFERENCES tiers(id) ON DELETE SET NULL;
+-- tier-exclusive gifts; a tier cannot be deleted while gifts require it
+ALTER TABLE gifts ADD COLUMN min_tier_id INT REFERENCES tiers(id);
+
+CREATE TABLE IF NOT EXISTS tier_changes (
+    id                SERIAL PRIMARY KEY,
+    organisation_id   INT         NOT NULL REFERENCES organisations(id),
+    user_id           INT         NOT NULL REFERENCES users(id),
+    from_tier_id      INT,
+    from_tier_name    VARCHAR(50) NOT NULL DEFAULT '',
+    to_tier_id        INT,
+    to_tier_name      VARCHAR(50) NOT NULL DEFAULT '',
+    qualifying_points BIGINT      NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tier_changes_user_id ON tier_changes(user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS point_ledger_entries;
+DROP TABLE IF EXISTS point_lots;
@@ -0,0 +1,50 @@
+-- earned points, consumed oldest-expiry first; users.point_balance is the
+-- sum of remaining over a user's lots
+CREATE TABLE IF NOT EXISTS point_lots (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    user_id         INT          NOT NULL REFERENCES users(id),
+    source          VARCHAR(50)  NOT NULL,
+    points          INT          NOT NULL CHECK (points > 0),
+    remaining       INT          NOT NULL CHECK (remaining >= 0 AND remaining <= points),
+    earned_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    -- NULL never expires
+    expires_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_lots_user_open ON point_lots(user_id, expires_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_point_lots_due ON point_lots(expires_at) WHERE remaining > 0;
+
+-- every movement of a balance; points are signed
+CREATE TABLE IF NOT EXISTS point_ledger_entries (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    user_id         INT          NOT NULL REFERENCES users(id),
+    type            VARCHAR(30)  NOT NULL,
+    points          INT          NOT NULL,
+    lot_id          INT          REFERENCES point_lots(id),
+    redemption_id   INT          REFERENCES redemptions(id),
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_ledger_entries_user_id ON point_ledger_entries(user_id, created_at);
+
+-- existing balances become one lot each with a full validity period, and the
+-- ledger is rebuilt from the redemptions so it adds up to the balance
+INSERT INTO point_lots (organisation_id, user_id, source, points, remaining, expires_at)
+SELECT organisation_id, id, 'opening_balance', point_balance, point_balance, NOW() + INTERVAL '12 months'
+FROM users
+WHERE point_balance > 0;
+
+INSERT INTO point_ledger_entries (organisation_id, user_id, type, points, lot_id, created_at)
+SELECT u.organisation_id, u.id, 'opening_balance', u.point_balance + COALESCE(r.spent, 0), l.id, u.created_at
+FROM users u
+LEFT JOIN (SELECT user_id, SUM(total_point) AS spent FROM redemptions GROUP BY user_id) r ON r.user_id = u.id
+LEFT JOIN point_lots l ON l.user_id = u.id AND l.source = 'opening_balance'
+WHERE u.point_balance + COALESCE(r.spent, 0) > 0;
+
+INSERT INTO point_ledger_entries (organisation_id, user_id, type, points, redemption_id, created_at)
+SELECT organisation_id, user_id, 'redemption', -total_point, id, redeemed_at
+FROM redemptions;
@@ -0,0 +1,4 @@
+ALTER TABLE point_ledger_entries DROP COLUMN IF EXISTS transfer_id;
+DROP TABLE IF EXISTS point_transfers;
+ALTER TABLE tiers DROP COLUMN IF EXISTS transfers_enabled;
+DROP TABLE IF EXISTS transfer_policies;
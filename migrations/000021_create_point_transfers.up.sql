@@ -0,0 +1,29 @@
+-- per-organisation rules for sending points to colleagues; no row means
+-- transfers are disabled
+CREATE TABLE IF NOT EXISTS transfer_policies (
+    organisation_id INT         PRIMARY KEY REFERENCES organisations(id),
+    enabled         BOOLEAN     NOT NULL DEFAULT FALSE,
+    -- points one user may send per UTC day, 0 for no limit
+    daily_limit     INT         NOT NULL DEFAULT 0 CHECK (daily_limit >= 0),
+    -- points a sender must keep after a transfer
+    min_balance     INT         NOT NULL DEFAULT 0 CHECK (min_balance >= 0),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- tiers whose members may not send points
+ALTER TABLE tiers ADD COLUMN transfers_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS point_transfers (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    sender_id       INT          NOT NULL REFERENCES users(id),
+    recipient_id    INT          NOT NULL REFERENCES users(id) CHECK (recipient_id <> sender_id),
+    points          INT          NOT NULL CHECK (points > 0),
+    message         VARCHAR(200) NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_transfers_sender ON point_transfers(sender_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_point_transfers_recipient ON point_transfers(recipient_id, created_at);
+
+ALTER TABLE point_ledger_entries ADD COLUMN transfer_id INT REFERENCES point_transfers(id);
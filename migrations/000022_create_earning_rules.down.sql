@@ -0,0 +1,4 @@
+ALTER TABLE point_ledger_entries DROP COLUMN IF EXISTS event_id;
+DROP TABLE IF EXISTS point_awards;
+DROP TABLE IF EXISTS point_events;
+DROP TABLE IF EXISTS earning_rules;
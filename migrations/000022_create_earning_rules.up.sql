@@ -0,0 +1,60 @@
+-- rules that turn business events into points
+CREATE TABLE IF NOT EXISTS earning_rules (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT            NOT NULL REFERENCES organisations(id),
+    name            VARCHAR(100)   NOT NULL,
+    event_type      VARCHAR(50)    NOT NULL,
+    -- attribute values an event must carry for the rule to apply
+    conditions      JSONB          NOT NULL DEFAULT '{}',
+    points          INT            NOT NULL DEFAULT 0 CHECK (points >= 0),
+    -- points per unit of a numeric attribute, e.g. per currency unit spent
+    unit_attribute  VARCHAR(50)    NOT NULL DEFAULT '',
+    points_per_unit NUMERIC(12, 4) NOT NULL DEFAULT 0 CHECK (points_per_unit >= 0),
+    multiplier      NUMERIC(8, 4)  NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+    -- 0 means no cap
+    max_per_event   INT            NOT NULL DEFAULT 0 CHECK (max_per_event >= 0),
+    user_cap        INT            NOT NULL DEFAULT 0 CHECK (user_cap >= 0),
+    -- window of the user cap in days, 0 for the rule's lifetime
+    user_cap_days   INT            NOT NULL DEFAULT 0 CHECK (user_cap_days >= 0),
+    starts_at       TIMESTAMPTZ,
+    ends_at         TIMESTAMPTZ,
+    active          BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ,
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_earning_rules_event_type ON earning_rules(organisation_id, event_type) WHERE deleted_at IS NULL;
+
+-- events received from other systems; the external ID makes delivery idempotent
+CREATE TABLE IF NOT EXISTS point_events (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    external_id     VARCHAR(100) NOT NULL,
+    type            VARCHAR(50)  NOT NULL,
+    user_id         INT          NOT NULL REFERENCES users(id),
+    attributes      JSONB        NOT NULL DEFAULT '{}',
+    occurred_at     TIMESTAMPTZ  NOT NULL,
+    points_awarded  INT          NOT NULL DEFAULT 0,
+    api_key_id      INT          REFERENCES api_keys(id),
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (organisation_id, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_events_user ON point_events(user_id);
+
+-- what each rule awarded for an event; user caps are summed from here
+CREATE TABLE IF NOT EXISTS point_awards (
+    id         SERIAL PRIMARY KEY,
+    event_id   INT         NOT NULL REFERENCES point_events(id),
+    rule_id    INT         NOT NULL REFERENCES earning_rules(id),
+    user_id    INT         NOT NULL REFERENCES users(id),
+    points     INT         NOT NULL CHECK (points > 0),
+    lot_id     INT         NOT NULL REFERENCES point_lots(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_awards_rule_user ON point_awards(rule_id, user_id, created_at);
+
+ALTER TABLE point_ledger_entries ADD COLUMN event_id INT REFERENCES point_events(id);
@@ -0,0 +1,4 @@
+ALTER TABLE redemptions DROP COLUMN IF EXISTS campaign_discount;
+ALTER TABLE redemptions DROP COLUMN IF EXISTS campaign_id;
+DROP TABLE IF EXISTS campaigns;
+ALTER TABLE gifts DROP COLUMN IF EXISTS category;
@@ -0,0 +1,30 @@
+-- free-form gift categories that campaigns can target, e.g. "electronics"
+ALTER TABLE gifts ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_gifts_category ON gifts(organisation_id, category) WHERE deleted_at IS NULL;
+
+-- time-boxed discounts on the point price of some gifts
+CREATE TABLE IF NOT EXISTS campaigns (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT          NOT NULL REFERENCES organisations(id),
+    name            VARCHAR(100) NOT NULL,
+    -- percent takes discount_value percent off, fixed takes discount_value points off
+    discount_type   VARCHAR(10)  NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value  INT          NOT NULL CHECK (discount_value > 0),
+    -- targeted gift IDs and categories; a gift matching either is discounted
+    gift_ids        JSONB        NOT NULL DEFAULT '[]',
+    categories      JSONB        NOT NULL DEFAULT '[]',
+    starts_at       TIMESTAMPTZ  NOT NULL,
+    ends_at         TIMESTAMPTZ  NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ,
+    CHECK (ends_at > starts_at),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_schedule ON campaigns(organisation_id, starts_at, ends_at) WHERE deleted_at IS NULL;
+
+-- the campaign that priced a redemption and the points it took off
+ALTER TABLE redemptions ADD COLUMN campaign_id INT REFERENCES campaigns(id);
+ALTER TABLE redemptions ADD COLUMN campaign_discount INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_redemptions_campaign_id ON redemptions(campaign_id) WHERE campaign_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flash_sale_units;
+DROP TABLE IF EXISTS flash_sales;
@@ -0,0 +1,30 @@
+-- a limited drop of a gift; its quantity is taken out of the gift's stock when
+-- the sale is created and handed out one unit per user from starts_at
+CREATE TABLE IF NOT EXISTS flash_sales (
+    id              SERIAL PRIMARY KEY,
+    organisation_id INT         NOT NULL REFERENCES organisations(id),
+    gift_id         INT         NOT NULL REFERENCES gifts(id),
+    quantity        INT         NOT NULL CHECK (quantity > 0),
+    starts_at       TIMESTAMPTZ NOT NULL,
+    ends_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ,
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_flash_sales_gift ON flash_sales(organisation_id, gift_id, starts_at) WHERE deleted_at IS NULL;
+
+-- one row per unit on sale, so concurrent buyers claim different rows instead
+-- of queueing on the gift row; user_id is set once the unit is claimed
+CREATE TABLE IF NOT EXISTS flash_sale_units (
+    id            SERIAL PRIMARY KEY,
+    flash_sale_id INT NOT NULL REFERENCES flash_sales(id),
+    user_id       INT REFERENCES users(id),
+    redemption_id INT REFERENCES redemptions(id),
+    claimed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_flash_sale_units_free ON flash_sale_units(flash_sale_id) WHERE user_id IS NULL;
+-- one unit per user and sale
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flash_sale_units_user ON flash_sale_units(flash_sale_id, user_id) WHERE user_id IS NOT NULL;
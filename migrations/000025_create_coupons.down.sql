@@ -0,0 +1,4 @@
+ALTER TABLE redemptions DROP COLUMN IF EXISTS coupon_discount;
+ALTER TABLE redemptions DROP COLUMN IF EXISTS coupon_id;
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
@@ -0,0 +1,44 @@
+-- codes users enter at redemption to take points off or get a gift for free
+CREATE TABLE IF NOT EXISTS coupons (
+    id                SERIAL PRIMARY KEY,
+    organisation_id   INT         NOT NULL REFERENCES organisations(id),
+    -- stored upper case; matching is case-insensitive
+    code              VARCHAR(50) NOT NULL,
+    -- percent and fixed take discount_value off the total, free makes one unit free
+    discount_type     VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed', 'free')),
+    discount_value    INT         NOT NULL DEFAULT 0 CHECK (discount_value >= 0),
+    -- 0 means no limit
+    max_uses          INT         NOT NULL DEFAULT 0 CHECK (max_uses >= 0),
+    max_uses_per_user INT         NOT NULL DEFAULT 0 CHECK (max_uses_per_user >= 0),
+    uses              INT         NOT NULL DEFAULT 0,
+    -- the redemption total before the coupon must reach min_points
+    min_points        INT         NOT NULL DEFAULT 0 CHECK (min_points >= 0),
+    -- targeted gift IDs and categories; with neither the coupon applies to every gift
+    gift_ids          JSONB       NOT NULL DEFAULT '[]',
+    categories        JSONB       NOT NULL DEFAULT '[]',
+    starts_at         TIMESTAMPTZ,
+    ends_at           TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at        TIMESTAMPTZ,
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at),
+    CHECK (max_uses = 0 OR uses <= max_uses)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons(organisation_id, code) WHERE deleted_at IS NULL;
+
+-- every use of a coupon, counted for the per-user limit
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id            SERIAL PRIMARY KEY,
+    coupon_id     INT         NOT NULL REFERENCES coupons(id),
+    user_id       INT         NOT NULL REFERENCES users(id),
+    redemption_id INT         NOT NULL REFERENCES redemptions(id),
+    discount      INT         NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_user ON coupon_redemptions(coupon_id, user_id);
+
+-- the coupon used on a redemption and the points it took off
+ALTER TABLE redemptions ADD COLUMN coupon_id INT REFERENCES coupons(id);
+ALTER TABLE redemptions ADD COLUMN coupon_discount INT NOT NULL DEFAULT 0;
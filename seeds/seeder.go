@@ -2,30 +2,43 @@ package seeds
 
 import (
 	"log"
+	"time"
 
 	"github.com/gift-redemption/internal/model"
 	"gorm.io/gorm"
 )
 
 func Run(db *gorm.DB) {
-	seedUsers(db)
-	seedGifts(db)
+	orgID := defaultOrganisationID(db)
+	seedUsers(db, orgID)
+	seedGifts(db, orgID)
 	log.Println("seeding completed")
 }
 
-func seedUsers(db *gorm.DB) {
+// defaultOrganisationID returns the organisation created by the migrations
+func defaultOrganisationID(db *gorm.DB) uint {
+	var org model.Organisation
+	if err := db.Where("slug = ?", model.DefaultOrganisationSlug).First(&org).Error; err != nil {
+		log.Fatalf("failed to find default organisation: %v", err)
+	}
+	return org.ID
+}
+
+func seedUsers(db *gorm.DB, orgID uint) {
 	var count int64
 	db.Model(&model.User{}).Count(&count)
 	if count > 0 {
 		return
 	}
 
+	now := time.Now()
 	users := []model.User{
-		{Name: "Admin gift-redemption", Email: "admin@gift-redemption.com", Role: model.RoleAdmin},
-		{Name: "John Doe", Email: "john@example.com", Role: model.RoleUser},
+		{Name: "Admin gift-redemption", Email: "admin@gift-redemption.com", Role: model.RoleSuperAdmin, EmailVerifiedAt: &now},
+		{Name: "John Doe", Email: "john@example.com", Role: model.RoleUser, EmailVerifiedAt: &now, PointBalance: 1000000},
 	}
 
 	for i := range users {
+		users[i].OrganisationID = orgID
 		if err := users[i].HashPassword("password123"); err != nil {
 			log.Fatalf("failed to hash password: %v", err)
 		}
@@ -37,7 +50,7 @@ func seedUsers(db *gorm.DB) {
 	log.Printf("seeded %d users", len(users))
 }
 
-func seedGifts(db *gorm.DB) {
+func seedGifts(db *gorm.DB, orgID uint) {
 	var count int64
 	db.Model(&model.Gift{}).Count(&count)
 	if count > 0 {
@@ -112,6 +125,9 @@ func seedGifts(db *gorm.DB) {
 			TotalReviews: 185,
 		},
 	}
+	for i := range gifts {
+		gifts[i].OrganisationID = orgID
+	}
 
 	if err := db.Create(&gifts).Error; err != nil {
 		log.Fatalf("failed to seed gifts: %v", err)